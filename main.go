@@ -4,6 +4,7 @@ import (
 	"log"
 	"myapp/pkg/configs"
 	"myapp/pkg/middleware"
+	"myapp/pkg/response"
 	"myapp/routes"
 
 	"github.com/gofiber/fiber/v2"
@@ -16,7 +17,10 @@ func init() {
 	}
 	configs.ConnectDB(&envConfig)
 	configs.ConnectRedis(&envConfig)
-	configs.MigrateDB()
+	if err := configs.MigrateDB(); err != nil {
+		log.Fatalln("Failed to migrate the database! \n", err.Error())
+	}
+	response.SetCursorSecret(envConfig.PaginationCursorSecret)
 }
 
 // @title Fiber Example API
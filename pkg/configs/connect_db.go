@@ -2,6 +2,8 @@ package configs
 
 import (
 	"fmt"
+	"myapp/pkg/money"
+	"myapp/pkg/utils"
 	"myapp/src/models"
 
 	"gorm.io/driver/postgres"
@@ -48,7 +50,9 @@ func GetDBConnection() *gorm.DB {
 	return DB
 }
 
-func MigrateDB() {
+// MigrateDB migrate the tables and backfill the new columns of the existing rows,
+// the error should stop the start of the server
+func MigrateDB() error {
 	// DROP TABLES
 	// DB.Migrator().DropTable(
 	// 	// &models.User{},
@@ -58,14 +62,78 @@ func MigrateDB() {
 	// 	&models.MyDrive{},
 	// )
 
+	hasProducts := DB.Migrator().HasTable(&models.Product{})
+	// the products before the publishing workflow are published when enabled
+	backfillStatus := hasProducts && !DB.Migrator().HasColumn(&models.Product{}, "status")
+	// the float prices before the money columns are copied to the minor units
+	backfillPrice := !DB.Migrator().HasColumn(&models.Product{}, "price_amount") && DB.Migrator().HasColumn(&models.Product{}, "price")
+	backfillVariantPrice := !DB.Migrator().HasColumn(&models.ProductVariant{}, "price_amount") && DB.Migrator().HasColumn(&models.ProductVariant{}, "price")
+	// the products before the slugs get the slug of their title
+	backfillSlug := hasProducts && !DB.Migrator().HasColumn(&models.Product{}, "slug")
+
+	// each backfill add its columns in its transaction before the AutoMigrate,
+	// so a failed backfill leave the columns missing and is retried on the next start
+	if backfillStatus {
+		if err := migrateStatus(); err != nil {
+			return fmt.Errorf("backfill of the product status: %w", err)
+		}
+	}
+	if backfillPrice || backfillVariantPrice {
+		if err := migratePrice(backfillPrice, backfillVariantPrice); err != nil {
+			return fmt.Errorf("backfill of the prices: %w", err)
+		}
+	}
+	if backfillSlug {
+		if err := migrateSlug(); err != nil {
+			return fmt.Errorf("backfill of the product slugs: %w", err)
+		}
+	}
+
 	// Migrate the database
-	DB.AutoMigrate(
+	err := DB.AutoMigrate(
 		// &models.User{},
 		// &models.UserProfile{},
 		// &models.OTPRequest{},
-		// &models.Product{},
+		&models.Organization{},
+		&models.OrganizationMember{},
+		&models.OrganizationInvitation{},
+		&models.Category{},
+		&models.Tag{},
+		&models.Product{},
+		&models.ProductVariant{},
+		&models.ProductImage{},
+		&models.StockMovement{},
+		&models.ProductReview{},
+		&models.Wishlist{},
+		&models.WishlistItem{},
+		&models.CartItem{},
+		&models.Order{},
+		&models.OrderItem{},
+		&models.Payment{},
+		&models.PaymentEvent{},
+		&models.ProductImport{},
+		&models.ProductRevision{},
+		&models.ProductSlug{},
+		&models.ProductReport{},
+		&models.ProductStat{},
 		&models.MyDrive{},
+		&models.Passkey{},
+		&models.Invitation{},
+		&models.AccountDeletion{},
+		&models.LoginHistory{},
+		&models.DataExport{},
+		&models.ExchangeRate{},
 	)
+	if err != nil {
+		return fmt.Errorf("auto migrate: %w", err)
+	}
+
+	if err := migrateProductSearch(); err != nil {
+		return fmt.Errorf("product search: %w", err)
+	}
+	if err := migrateOrderOwners(); err != nil {
+		return fmt.Errorf("order buyer and seller: %w", err)
+	}
 
 	fmt.Println("👍 Migration complete")
 
@@ -73,4 +141,130 @@ func MigrateDB() {
 	// DB.AutoMigrate(&models.Status{})
 	// var status = []models.Status{{Name: "Active"}, {Name: "Inactive"}, {Name: "Pending"}, {Name: "Suspended"}}
 	// DB.Create(&status)
+	return nil
+}
+
+// migrateProductSearch add the generated tsvector of the product search and its GIN index,
+// the title is weighted above the description
+func migrateProductSearch() error {
+	config, _ := LoadConfig(".")
+	language := config.FullTextLanguage()
+
+	err := DB.Exec(fmt.Sprintf(`ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (
+			setweight(to_tsvector('%[1]s'::regconfig, COALESCE(title, '')), 'A') ||
+			setweight(to_tsvector('%[1]s'::regconfig, COALESCE(description, '')), 'B')
+		) STORED`, language)).Error
+	if err != nil {
+		return err
+	}
+	return DB.Exec("CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector)").Error
+}
+
+// migrateOrderOwners keep the orders of the purged accounts, the first orders table deleted them with the account,
+// the AutoMigrate doesn't change the existing constraints
+func migrateOrderOwners() error {
+	var names []string
+	err := DB.Raw("SELECT conname FROM pg_constraint WHERE conrelid = 'orders'::regclass AND conname IN ? AND confdeltype = 'c'",
+		[]string{"fk_orders_buyer", "fk_orders_seller"}).Scan(&names).Error
+	if err != nil || len(names) == 0 {
+		return err
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		for _, relation := range []string{"Buyer", "Seller"} {
+			if err := tx.Migrator().DropConstraint(&models.Order{}, relation); err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateConstraint(&models.Order{}, relation); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// migrateStatus add the status of the publishing workflow, the enabled products are published
+func migrateStatus() error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&models.Product{}, "status"); err != nil {
+			return err
+		}
+		if !tx.Migrator().HasColumn(&models.Product{}, "publish_at") {
+			if err := tx.Migrator().AddColumn(&models.Product{}, "publish_at"); err != nil {
+				return err
+			}
+		}
+		return tx.Exec("UPDATE products SET status = 'published', publish_at = created_at WHERE is_enable = true").Error
+	})
+}
+
+// migratePrice add the money columns and copy the float prices to the minor units of the default currency,
+// the old price columns are kept, drop them manually once the data is checked
+func migratePrice(products, variants bool) error {
+	config, _ := LoadConfig(".")
+	currency := config.BaseCurrency()
+	exponent, ok := money.Exponent(currency)
+	if !ok {
+		return fmt.Errorf("unknown DEFAULT_CURRENCY %s", currency)
+	}
+
+	scale := int64(1)
+	for i := 0; i < exponent; i++ {
+		scale *= 10
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if products {
+			for _, column := range []string{"price_amount", "price_currency"} {
+				if err := tx.Migrator().AddColumn(&models.Product{}, column); err != nil {
+					return err
+				}
+			}
+			err := tx.Exec("UPDATE products SET price_amount = COALESCE(ROUND(price::numeric * ?), 0), price_currency = ?", scale, currency).Error
+			if err != nil {
+				return err
+			}
+		}
+		if variants {
+			if err := tx.Migrator().AddColumn(&models.ProductVariant{}, "price_amount"); err != nil {
+				return err
+			}
+			err := tx.Exec("UPDATE product_variants SET price_amount = ROUND(price::numeric * ?) WHERE price IS NOT NULL", scale).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// migrateSlug add the slug and set it from the title of the existing products,
+// the oldest product get the slug without suffix, the unique index is added by the AutoMigrate
+func migrateSlug() error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&models.Product{}, "slug"); err != nil {
+			return err
+		}
+
+		var products []*models.Product
+		used := map[string]bool{}
+		return tx.Unscoped().Select("id", "title").Order("id asc").FindInBatches(&products, 500, func(batchTx *gorm.DB, batch int) error {
+			for _, product := range products {
+				base := utils.Slugify(product.Title)
+				if base == "" {
+					base = "product"
+				}
+				slug := base
+				for n := 2; used[slug]; n++ {
+					slug = fmt.Sprintf("%s-%d", base, n)
+				}
+				used[slug] = true
+				if err := tx.Unscoped().Model(product).UpdateColumn("slug", slug).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+	})
 }
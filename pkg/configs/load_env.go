@@ -1,6 +1,8 @@
 package configs
 
 import (
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -29,6 +31,58 @@ type Config struct {
 	RefreshTokenExpiresIn  time.Duration `mapstructure:"REFRESH_TOKEN_EXPIRED_IN"`
 	AccessTokenMaxAge      int           `mapstructure:"ACCESS_TOKEN_MAXAGE"`
 	RefreshTokenMaxAge     int           `mapstructure:"REFRESH_TOKEN_MAXAGE"`
+
+	RegistrationMode string `mapstructure:"REGISTRATION_MODE"`
+	InvitationByUser bool   `mapstructure:"INVITATION_BY_USER"`
+
+	AccountDeletionGraceDays int           `mapstructure:"ACCOUNT_DELETION_GRACE_DAYS"`
+	AccountPurgeInterval     time.Duration `mapstructure:"ACCOUNT_PURGE_INTERVAL"`
+
+	DataExportExpiresIn  time.Duration `mapstructure:"DATA_EXPORT_EXPIRED_IN"`
+	DataExportMaxWorkers int           `mapstructure:"DATA_EXPORT_MAX_WORKERS"`
+
+	WebAuthnRPID      string `mapstructure:"WEBAUTHN_RP_ID"`
+	WebAuthnRPOrigins string `mapstructure:"WEBAUTHN_RP_ORIGINS"`
+
+	SearchLanguage         string        `mapstructure:"SEARCH_LANGUAGE"`
+	ProductPublishInterval time.Duration `mapstructure:"PRODUCT_PUBLISH_INTERVAL"`
+	DefaultCurrency        string        `mapstructure:"DEFAULT_CURRENCY"`
+	ProductTrashDays       int           `mapstructure:"PRODUCT_TRASH_DAYS"`
+	ProductStatsInterval   time.Duration `mapstructure:"PRODUCT_STATS_INTERVAL"`
+	CatalogCacheTTL        time.Duration `mapstructure:"CATALOG_CACHE_TTL"`
+
+	PaginationCursorSecret string `mapstructure:"PAGINATION_CURSOR_SECRET"`
+
+	PaymentProvider      string `mapstructure:"PAYMENT_PROVIDER"`
+	PaymentWebhookSecret string `mapstructure:"PAYMENT_WEBHOOK_SECRET"`
+	StripeSecretKey      string `mapstructure:"STRIPE_SECRET_KEY"`
+}
+
+var searchLanguagePattern = regexp.MustCompile(`^[a-z_]+$`)
+
+// FullTextLanguage return the postgres text search configuration used to stem the products,
+// the value is put in the SQL so only a plain name is accepted
+func (c Config) FullTextLanguage() string {
+	if !searchLanguagePattern.MatchString(c.SearchLanguage) {
+		return "english"
+	}
+	return c.SearchLanguage
+}
+
+// BaseCurrency return the ISO 4217 currency of the new prices and the base of the exchange rates
+func (c Config) BaseCurrency() string {
+	if c.DefaultCurrency == "" {
+		return "USD"
+	}
+	return strings.ToUpper(c.DefaultCurrency)
+}
+
+// TrashRetention return the time the deleted products are kept in the trash
+func (c Config) TrashRetention() time.Duration {
+	if c.ProductTrashDays <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(c.ProductTrashDays) * 24 * time.Hour
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -0,0 +1,24 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"myapp/pkg/configs"
+	"myapp/pkg/payment"
+	"myapp/src/models"
+	"strings"
+)
+
+// NewPaymentGateway build the payment provider from environment config
+func NewPaymentGateway(config *configs.Config) (models.PaymentGateway, error) {
+	switch strings.ToLower(config.PaymentProvider) {
+	case "", "fake":
+		return payment.NewFake(config.PaymentWebhookSecret), nil
+	case "stripe":
+		if config.StripeSecretKey == "" || config.PaymentWebhookSecret == "" {
+			return nil, errors.New("STRIPE_SECRET_KEY and PAYMENT_WEBHOOK_SECRET are required")
+		}
+		return payment.NewStripe(config.StripeSecretKey, config.PaymentWebhookSecret), nil
+	}
+	return nil, fmt.Errorf("unknown payment provider %q", config.PaymentProvider)
+}
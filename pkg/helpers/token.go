@@ -15,7 +15,7 @@ import (
 	uuid "github.com/satori/go.uuid"
 )
 
-func CreateToken(userid uint) (*models.TokenDetails, error) {
+func CreateToken(userid uint, organizationID uint) (*models.TokenDetails, error) {
 	config, _ := configs.LoadConfig(".")
 
 	now := time.Now().UTC()
@@ -42,6 +42,7 @@ func CreateToken(userid uint) (*models.TokenDetails, error) {
 	atClaims["authorized"] = true
 	atClaims["sub"] = userid
 	atClaims["token_uuid"] = td.AccessUuid
+	atClaims["org"] = organizationID
 	atClaims["exp"] = td.AtExpires
 	td.AccessToken, err = jwt.NewWithClaims(jwt.SigningMethodRS256, atClaims).SignedString(atKey)
 	if err != nil {
@@ -62,6 +63,7 @@ func CreateToken(userid uint) (*models.TokenDetails, error) {
 	rtClaims := make(jwt.MapClaims)
 	rtClaims["sub"] = userid
 	rtClaims["token_uuid"] = td.RefreshUuid
+	rtClaims["org"] = organizationID
 	rtClaims["exp"] = td.RtExpires
 	td.RefreshToken, err = jwt.NewWithClaims(jwt.SigningMethodRS256, rtClaims).SignedString(rtKey)
 	if err != nil {
@@ -108,9 +110,16 @@ func ValidateToken(token string, publicKey string) (*models.AccessDetails, error
 		return nil, err
 	}
 
+	// active organization, the token before organization has no claim
+	var organizationID uint64
+	if org, ok := claims["org"].(float64); ok {
+		organizationID = uint64(org)
+	}
+
 	return &models.AccessDetails{
-		TokenUuid: tokenUuid,
-		UserID:    uint(userId),
+		TokenUuid:      tokenUuid,
+		UserID:         uint(userId),
+		OrganizationID: uint(organizationID),
 	}, nil
 }
 
@@ -0,0 +1,34 @@
+package helpers
+
+import (
+	"myapp/pkg/configs"
+	"strings"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+// NewWebAuthn build the WebAuthn relying party from environment config
+func NewWebAuthn(config *configs.Config) (*webauthn.WebAuthn, error) {
+	var origins []string
+	for _, origin := range strings.Split(config.WebAuthnRPOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+
+	// fallback to client origin
+	if len(origins) == 0 {
+		origins = []string{config.ClientOrigin}
+	}
+
+	return webauthn.New(&webauthn.Config{
+		RPID:          config.WebAuthnRPID,
+		RPDisplayName: config.AppName,
+		RPOrigins:     origins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementPreferred,
+			UserVerification: protocol.VerificationPreferred,
+		},
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"myapp/pkg/configs"
 	"myapp/pkg/helpers"
+	"myapp/pkg/utils"
 	"myapp/src/models"
 
 	"github.com/gofiber/fiber/v2"
@@ -44,9 +45,37 @@ func JWTAuthMiddleware() fiber.Handler {
 			})
 		}
 
+		// the suspended user can't use the tokens issued before
+		if user.IsLocked() {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"code":    fiber.ErrForbidden.Code,
+				"error":   fiber.ErrForbidden.Message,
+				"message": utils.ERR_ACCOUNT_LOCKED,
+			})
+		}
+
+		// active organization from the token, the membership can be revoked any time
+		tenant := models.Tenant{UserID: user.ID}
+		if tokenClaims.OrganizationID != 0 {
+			var member models.OrganizationMember
+			err = configs.DB.Preload("Organization").
+				First(&member, "organization_id = ? AND user_id = ?", tokenClaims.OrganizationID, user.ID).Error
+			if err != nil || member.Organization == nil {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"code":    fiber.ErrForbidden.Code,
+					"error":   fiber.ErrForbidden.Message,
+					"message": "You are no longer a member of this organization, please switch the organization",
+				})
+			}
+
+			tenant.OrganizationID = member.OrganizationID
+			tenant.Role = member.Role
+		}
+
 		SaveUserLogs(c, user)
 
 		c.Locals("user", user)
+		c.Locals("tenant", tenant)
 		c.Locals("token_uuid", tokenClaims.TokenUuid)
 
 		return c.Next()
@@ -86,6 +115,15 @@ func AdminAuthMiddleware() fiber.Handler {
 			})
 		}
 
+		// the suspended user can't use the tokens issued before
+		if user.IsLocked() {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"code":    fiber.ErrForbidden.Code,
+				"error":   fiber.ErrForbidden.Message,
+				"message": utils.ERR_ACCOUNT_LOCKED,
+			})
+		}
+
 		/// ==================== is staff protected ======================
 		if !user.IsStaff {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
@@ -98,6 +136,7 @@ func AdminAuthMiddleware() fiber.Handler {
 		SaveUserLogs(c, user)
 
 		c.Locals("user", user)
+		c.Locals("tenant", models.Tenant{UserID: user.ID})
 		c.Locals("token_uuid", tokenClaims.TokenUuid)
 
 		return c.Next()
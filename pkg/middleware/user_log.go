@@ -44,5 +44,11 @@ func SaveUserLogs(c *fiber.Ctx, user models.User) {
 		if err != nil {
 			log.Errorf(fmt.Sprintf(err.Error()))
 		}
+
+		// keep the history for the data export
+		history := models.LoginHistory{UserID: user.ID, Ip: c.IP(), UserAgent: c.Get(fiber.HeaderUserAgent), CreatedAt: now}
+		if err := configs.DB.Create(&history).Error; err != nil {
+			log.Errorf(err.Error())
+		}
 	}
 }
@@ -0,0 +1,253 @@
+// Package money is the exact monetary amount, stored as the integer minor units
+// of an ISO 4217 currency (1999 USD is 19.99 USD). The floats are never used,
+// the decimal input is parsed digit by digit and the conversion is done with big.Rat.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("the amounts have different currencies")
+	ErrOverflow         = errors.New("the amount is too large")
+)
+
+// Money is the amount in the minor units of the currency, embedded in the model with a prefix
+//
+//	Price money.Money `gorm:"embedded;embeddedPrefix:price_"`
+type Money struct {
+	Amount   int64  `gorm:"not null;default:0"`
+	Currency string `gorm:"size:3"`
+}
+
+// New return the money of the minor units
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// Parse read the decimal amount ("19.99", "-5", "1000.5") in the currency,
+// more decimal places than the currency has is an error, it's never rounded
+func Parse(value, currency string) (Money, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	exponent, ok := Exponent(currency)
+	if !ok {
+		return Money{}, fmt.Errorf("unknown currency %q", currency)
+	}
+
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "-"), "+")
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("%q isn't a decimal amount", value)
+	}
+	if len(fraction) > exponent {
+		return Money{}, fmt.Errorf("%s has only %d decimal places", currency, exponent)
+	}
+
+	var amount int64
+	digits := strings.TrimLeft(whole+fraction+strings.Repeat("0", exponent-len(fraction)), "0")
+	if digits != "" {
+		var err error
+		if amount, err = strconv.ParseInt(digits, 10, 64); err != nil {
+			return Money{}, ErrOverflow
+		}
+	}
+	if negative {
+		amount = -amount
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// Add return the sum, both amounts must be in the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return m, ErrCurrencyMismatch
+	}
+	sum := m.Amount + other.Amount
+	if (sum > m.Amount) != (other.Amount > 0) {
+		return m, ErrOverflow
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Sub return the difference, both amounts must be in the same currency
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Amount == math.MinInt64 {
+		return m, ErrOverflow
+	}
+	return m.Add(Money{Amount: -other.Amount, Currency: other.Currency})
+}
+
+// Mul return the amount multiplied by the quantity
+func (m Money) Mul(quantity int64) (Money, error) {
+	if quantity == 0 || m.Amount == 0 {
+		return Money{Currency: m.Currency}, nil
+	}
+	total := m.Amount * quantity
+	// the minimum of int64 negated wrap to itself, the division doesn't see it
+	negateMin := (m.Amount == -1 && quantity == math.MinInt64) || (m.Amount == math.MinInt64 && quantity == -1)
+	if total/quantity != m.Amount || negateMin {
+		return m, ErrOverflow
+	}
+	return Money{Amount: total, Currency: m.Currency}, nil
+}
+
+// Convert return the amount in the currency, rate is the units of the currency for one unit of m,
+// the result is rounded half away from zero to the minor unit of the currency
+func (m Money) Convert(currency string, rate *big.Rat) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if currency == m.Currency {
+		return m, nil
+	}
+	from, ok := Exponent(m.Currency)
+	if !ok {
+		return m, fmt.Errorf("unknown currency %q", m.Currency)
+	}
+	to, ok := Exponent(currency)
+	if !ok {
+		return m, fmt.Errorf("unknown currency %q", currency)
+	}
+
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), rate)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(to-from))), nil))
+	if to > from {
+		value.Mul(value, scale)
+	} else {
+		value.Quo(value, scale)
+	}
+
+	amount := roundHalfAway(value)
+	if !amount.IsInt64() {
+		return m, ErrOverflow
+	}
+	return Money{Amount: amount.Int64(), Currency: currency}, nil
+}
+
+// IsZero check the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// String format the amount as decimal with the decimal places of the currency, e.g. 19.99
+func (m Money) String() string {
+	exponent, _ := Exponent(m.Currency)
+
+	// the minimum of int64 can't be negated, big.Int handle it
+	digits := new(big.Int).Abs(big.NewInt(m.Amount)).String()
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+
+	sign := ""
+	if m.Amount < 0 {
+		sign = "-"
+	}
+	if exponent == 0 {
+		return sign + digits
+	}
+	point := len(digits) - exponent
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// Format return the amount with the currency, e.g. 19.99 USD
+func (m Money) Format() string {
+	return m.String() + " " + m.Currency
+}
+
+type jsonMoney struct {
+	// the decimal is a string, the JSON number may lose the precision
+	Amount     string `json:"amount"`
+	Currency   string `json:"currency"`
+	MinorUnits int64  `json:"minor_units"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonMoney{Amount: m.String(), Currency: m.Currency, MinorUnits: m.Amount})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var aux jsonMoney
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*m = New(aux.MinorUnits, aux.Currency)
+	return nil
+}
+
+// Decimal is the decimal amount of the input, the JSON number is kept as written
+// so 0.1 stay exact. Parse it with the currency of the amount
+type Decimal string
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = ""
+		return nil
+	}
+	if strings.HasPrefix(string(data), `"`) {
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		*d = Decimal(value)
+		return nil
+	}
+	*d = Decimal(data)
+	return nil
+}
+
+// Parse return the money of the decimal in the currency
+func (d Decimal) Parse(currency string) (Money, error) {
+	return Parse(string(d), currency)
+}
+
+// ParseRate read the decimal exchange rate, it must be positive
+func ParseRate(value string) (*big.Rat, error) {
+	value = strings.TrimSpace(value)
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return nil, fmt.Errorf("%q isn't a decimal rate", value)
+	}
+	rate, ok := new(big.Rat).SetString(value)
+	if !ok || rate.Sign() <= 0 {
+		return nil, fmt.Errorf("%q isn't a positive rate", value)
+	}
+	return rate, nil
+}
+
+func roundHalfAway(value *big.Rat) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	// |remainder| * 2 >= denominator round away from zero
+	if new(big.Int).Abs(remainder).Lsh(new(big.Int).Abs(remainder), 1).Cmp(value.Denom()) >= 0 {
+		if value.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
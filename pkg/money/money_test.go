@@ -0,0 +1,229 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value, currency string
+		want            Money
+	}{
+		{"19.99", "USD", Money{1999, "USD"}},
+		{"19.9", "usd", Money{1990, "USD"}},
+		{"19", "USD", Money{1900, "USD"}},
+		{".5", "EUR", Money{50, "EUR"}},
+		{"-5", "USD", Money{-500, "USD"}},
+		{"+0.01", "USD", Money{1, "USD"}},
+		{" 1000.5 ", "USD", Money{100050, "USD"}},
+		{"0", "USD", Money{0, "USD"}},
+		{"1500", "JPY", Money{1500, "JPY"}},
+		{"1.234", "KWD", Money{1234, "KWD"}},
+		{"92233720368547758.07", "USD", Money{math.MaxInt64, "USD"}},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.value, tt.currency)
+		if err != nil {
+			t.Errorf("Parse(%q, %q): %v", tt.value, tt.currency, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q, %q) = %v, want %v", tt.value, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestParseRejected(t *testing.T) {
+	tests := []struct {
+		value, currency string
+	}{
+		{"19.999", "USD"},
+		{"1.5", "JPY"},
+		{"", "USD"},
+		{".", "USD"},
+		{"1e3", "USD"},
+		{"1,000", "USD"},
+		{"--1", "USD"},
+		{"10", "XXX"},
+		{"92233720368547758.08", "USD"},
+	}
+
+	for _, tt := range tests {
+		if got, err := Parse(tt.value, tt.currency); err == nil {
+			t.Errorf("Parse(%q, %q) = %v, want an error", tt.value, tt.currency, got)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := New(1999, "USD"), New(1, "USD")
+
+	if got, err := a.Add(b); err != nil || got != New(2000, "USD") {
+		t.Errorf("Add = %v, %v", got, err)
+	}
+	if got, err := a.Sub(New(2000, "USD")); err != nil || got != New(-1, "USD") {
+		t.Errorf("Sub = %v, %v", got, err)
+	}
+	if got, err := a.Mul(3); err != nil || got != New(5997, "USD") {
+		t.Errorf("Mul = %v, %v", got, err)
+	}
+	if got, err := a.Mul(0); err != nil || !got.IsZero() || got.Currency != "USD" {
+		t.Errorf("Mul(0) = %v, %v", got, err)
+	}
+
+	if _, err := a.Add(New(1, "EUR")); err != ErrCurrencyMismatch {
+		t.Errorf("Add of another currency: got %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := a.Sub(New(1, "EUR")); err != ErrCurrencyMismatch {
+		t.Errorf("Sub of another currency: got %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	max, min := New(math.MaxInt64, "USD"), New(math.MinInt64, "USD")
+
+	tests := map[string]func() (Money, error){
+		"add":            func() (Money, error) { return max.Add(New(1, "USD")) },
+		"add negative":   func() (Money, error) { return min.Add(New(-1, "USD")) },
+		"sub":            func() (Money, error) { return min.Sub(New(1, "USD")) },
+		"sub min":        func() (Money, error) { return New(0, "USD").Sub(min) },
+		"mul":            func() (Money, error) { return max.Mul(2) },
+		"mul negative":   func() (Money, error) { return min.Mul(-1) },
+		"mul min by one": func() (Money, error) { return New(-1, "USD").Mul(math.MinInt64) },
+	}
+	for name, op := range tests {
+		if got, err := op(); err != ErrOverflow {
+			t.Errorf("%s = %v, %v, want ErrOverflow", name, got, err)
+		}
+	}
+
+	if got, err := max.Add(New(-1, "USD")); err != nil || got.Amount != math.MaxInt64-1 {
+		t.Errorf("max - 1 = %v, %v", got, err)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		from     Money
+		currency string
+		rate     string
+		want     Money
+	}{
+		{New(1000, "USD"), "EUR", "0.9", New(900, "EUR")},
+		// 0.005 EUR is rounded half away from zero
+		{New(1, "USD"), "EUR", "0.5", New(1, "EUR")},
+		{New(-1, "USD"), "EUR", "0.5", New(-1, "EUR")},
+		{New(1, "USD"), "EUR", "0.49", New(0, "EUR")},
+		// 19.99 USD * 149.5 = 2988.505 JPY
+		{New(1999, "USD"), "JPY", "149.5", New(2989, "JPY")},
+		{New(1500, "JPY"), "USD", "0.0067", New(1005, "USD")},
+		{New(1000, "USD"), "KWD", "0.3075", New(3075, "KWD")},
+		{New(1999, "USD"), "usd", "2", New(1999, "USD")},
+	}
+
+	for _, tt := range tests {
+		rate, err := ParseRate(tt.rate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := tt.from.Convert(tt.currency, rate)
+		if err != nil {
+			t.Errorf("%v to %s: %v", tt.from, tt.currency, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%v to %s at %s = %v, want %v", tt.from, tt.currency, tt.rate, got, tt.want)
+		}
+	}
+
+	rate, _ := ParseRate("1000")
+	if _, err := New(math.MaxInt64, "USD").Convert("EUR", rate); err != ErrOverflow {
+		t.Errorf("got %v, want ErrOverflow", err)
+	}
+	if _, err := New(1, "USD").Convert("XXX", rate); err == nil {
+		t.Error("the unknown currency should be rejected")
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	for _, value := range []string{"0.9", "149.5", "1", " 0.0067 "} {
+		if _, err := ParseRate(value); err != nil {
+			t.Errorf("ParseRate(%q): %v", value, err)
+		}
+	}
+	for _, value := range []string{"0", "0.000", "-1", "1/3", "1e3", ""} {
+		if _, err := ParseRate(value); err == nil {
+			t.Errorf("ParseRate(%q) should be rejected", value)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{New(1999, "USD"), "19.99"},
+		{New(5, "USD"), "0.05"},
+		{New(-5, "USD"), "-0.05"},
+		{New(0, "USD"), "0.00"},
+		{New(1500, "JPY"), "1500"},
+		{New(1, "KWD"), "0.001"},
+		{New(math.MinInt64, "USD"), "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.money, got, tt.want)
+		}
+	}
+
+	if got := New(1999, "usd").Format(); got != "19.99 USD" {
+		t.Errorf("Format() = %q", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(New(1999, "USD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"amount":"19.99","currency":"USD","minor_units":1999}`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+
+	var got Money
+	if err := json.Unmarshal([]byte(`{"amount":"ignored","currency":"eur","minor_units":250}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != New(250, "EUR") {
+		t.Errorf("got %v, want 2.50 EUR", got)
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	var input struct {
+		Price Decimal `json:"price"`
+	}
+
+	for body, want := range map[string]Money{
+		`{"price": 0.1}`:     New(10, "USD"),
+		`{"price": "19.99"}`: New(1999, "USD"),
+		`{"price": 1000}`:    New(100000, "USD"),
+	} {
+		if err := json.Unmarshal([]byte(body), &input); err != nil {
+			t.Fatal(err)
+		}
+		got, err := input.Price.Parse("USD")
+		if err != nil || got != want {
+			t.Errorf("%s = %v, %v, want %v", body, got, err, want)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"price": null}`), &input); err != nil || input.Price != "" {
+		t.Errorf("null = %q, %v", input.Price, err)
+	}
+}
@@ -0,0 +1,130 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"myapp/pkg/money"
+	"sync"
+	"time"
+)
+
+// FakeSignatureHeader is the header of the signature of the fake webhooks
+const FakeSignatureHeader = "X-Payment-Signature"
+
+// Fake is the in-process gateway for development and tests, the intents are kept in memory
+// and confirmed without any card. The webhooks are signed with Sign(secret, payload)
+type Fake struct {
+	mu      sync.Mutex
+	secret  string
+	intents map[string]*Intent
+	keys    map[string]string
+}
+
+// NewFake return the fake gateway, secret sign the webhooks
+func NewFake(secret string) *Fake {
+	return &Fake{
+		secret:  secret,
+		intents: map[string]*Intent{},
+		keys:    map[string]string{},
+	}
+}
+
+// FakeEvent is the webhook body of the fake gateway
+type FakeEvent struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	IntentID  string      `json:"intent_id"`
+	Status    Status      `json:"status"`
+	Amount    money.Money `json:"amount"`
+	Reason    string      `json:"failure_reason,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+func (*Fake) Name() string {
+	return "fake"
+}
+
+func (g *Fake) CreateIntent(_ context.Context, req IntentRequest) (Intent, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if id, ok := g.keys[req.IdempotencyKey]; ok && req.IdempotencyKey != "" {
+		return *g.intents[id], nil
+	}
+
+	intent := &Intent{
+		ID:     randomID("pi_fake_"),
+		Status: StatusPending,
+		Amount: req.Amount,
+	}
+	intent.ClientSecret = intent.ID + "_secret_" + randomID("")
+	g.intents[intent.ID] = intent
+	if req.IdempotencyKey != "" {
+		g.keys[req.IdempotencyKey] = intent.ID
+	}
+
+	return *intent, nil
+}
+
+func (g *Fake) Confirm(_ context.Context, intentID string) (Intent, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	intent, ok := g.intents[intentID]
+	if !ok {
+		return Intent{}, ErrUnknownIntent
+	}
+	if intent.Status == StatusPending || intent.Status == StatusFailed {
+		intent.Status = StatusSucceeded
+		intent.FailureReason = ""
+	}
+
+	return *intent, nil
+}
+
+func (g *Fake) Refund(_ context.Context, intentID string, amount money.Money) (Refund, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	intent, ok := g.intents[intentID]
+	if !ok {
+		return Refund{}, ErrUnknownIntent
+	}
+	if intent.Status != StatusSucceeded {
+		return Refund{}, fmt.Errorf("the %s payment can't be refunded", intent.Status)
+	}
+	if amount != intent.Amount {
+		return Refund{}, fmt.Errorf("only the full amount %s can be refunded", intent.Amount.Format())
+	}
+	intent.Status = StatusRefunded
+
+	return Refund{ID: randomID("re_fake_"), IntentID: intent.ID, Amount: amount}, nil
+}
+
+func (g *Fake) VerifyWebhook(payload []byte, header func(key string) string) (Event, error) {
+	if !VerifySignature(g.secret, payload, header(FakeSignatureHeader)) {
+		return Event{}, ErrInvalidSignature
+	}
+
+	var data FakeEvent
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return Event{}, err
+	}
+	if data.ID == "" || data.IntentID == "" {
+		return Event{}, fmt.Errorf("the event id and intent_id are required")
+	}
+	if _, ok := statusRank[data.Status]; !ok {
+		data.Status = ""
+	}
+
+	return Event{
+		ID:            data.ID,
+		Type:          data.Type,
+		IntentID:      data.IntentID,
+		Status:        data.Status,
+		Amount:        data.Amount,
+		FailureReason: data.Reason,
+		CreatedAt:     data.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,105 @@
+// Package payment is the common types of the payment providers, the gateways
+// (the in-process fake and the HTTP adapters) translate the provider API to them.
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"myapp/pkg/money"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("the webhook signature is invalid")
+	ErrUnknownIntent    = errors.New("the payment intent doesn't exist")
+)
+
+// Status is the state of the payment
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusFailed    Status = "failed"
+	StatusSucceeded Status = "succeeded"
+	StatusRefunded  Status = "refunded"
+)
+
+// the order of the statuses, a payment only moves forward so the late event is ignored
+var statusRank = map[Status]int{
+	StatusPending:   0,
+	StatusFailed:    1,
+	StatusSucceeded: 2,
+	StatusRefunded:  3,
+}
+
+// CanMoveTo check the payment can move to the next status, the failed payment can still succeed
+// but the succeeded one can't go back to failed when the events come out of order
+func (s Status) CanMoveTo(next Status) bool {
+	rank, ok := statusRank[next]
+	return ok && rank > statusRank[s]
+}
+
+// IntentRequest is the amount to be collected
+type IntentRequest struct {
+	Amount money.Money
+	// the reference of the payment at the provider, e.g. order_12
+	Reference   string
+	Description string
+	// the same key return the same intent
+	IdempotencyKey string
+}
+
+// Intent is the payment at the provider
+type Intent struct {
+	ID string
+	// the secret used by the client to confirm the payment
+	ClientSecret  string
+	Status        Status
+	Amount        money.Money
+	FailureReason string
+}
+
+// Refund is the money given back of a succeeded intent
+type Refund struct {
+	ID       string
+	IntentID string
+	Amount   money.Money
+}
+
+// Event is the verified notification of the provider, Status is empty for the ignored event types
+type Event struct {
+	ID            string
+	Type          string
+	IntentID      string
+	Status        Status
+	Amount        money.Money
+	FailureReason string
+	CreatedAt     time.Time
+}
+
+// Sign return the hex HMAC-SHA256 of the payload
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature compare the hex signature with the HMAC-SHA256 of the payload in constant time
+func VerifySignature(secret string, payload []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || secret == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+func randomID(prefix string) string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
@@ -0,0 +1,232 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"myapp/pkg/money"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	stripeBaseURL         = "https://api.stripe.com"
+	stripeSignatureHeader = "Stripe-Signature"
+	// the webhook older than the tolerance is refused, it may be replayed
+	stripeTolerance = 5 * time.Minute
+)
+
+// Stripe is the gateway of the Stripe payment intents API
+type Stripe struct {
+	SecretKey     string
+	WebhookSecret string
+	BaseURL       string
+	Client        *http.Client
+}
+
+// NewStripe return the Stripe gateway, secretKey is the API key and webhookSecret the signing secret of the endpoint
+func NewStripe(secretKey, webhookSecret string) *Stripe {
+	return &Stripe{
+		SecretKey:     secretKey,
+		WebhookSecret: webhookSecret,
+		BaseURL:       stripeBaseURL,
+		Client:        &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type stripeObject struct {
+	ID             string `json:"id"`
+	Object         string `json:"object"`
+	Status         string `json:"status"`
+	Amount         int64  `json:"amount"`
+	AmountReceived int64  `json:"amount_received"`
+	AmountRefunded int64  `json:"amount_refunded"`
+	Currency       string `json:"currency"`
+	ClientSecret   string `json:"client_secret"`
+	PaymentIntent  string `json:"payment_intent"`
+	LastError      *struct {
+		Message string `json:"message"`
+	} `json:"last_payment_error"`
+}
+
+type stripeEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object stripeObject `json:"object"`
+	} `json:"data"`
+}
+
+func (*Stripe) Name() string {
+	return "stripe"
+}
+
+func (g *Stripe) CreateIntent(ctx context.Context, req IntentRequest) (Intent, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(req.Amount.Amount, 10))
+	form.Set("currency", strings.ToLower(req.Amount.Currency))
+	form.Set("description", req.Description)
+	form.Set("metadata[reference]", req.Reference)
+	form.Set("automatic_payment_methods[enabled]", "true")
+
+	var obj stripeObject
+	if err := g.post(ctx, "/v1/payment_intents", form, req.IdempotencyKey, &obj); err != nil {
+		return Intent{}, err
+	}
+
+	return obj.intent(), nil
+}
+
+func (g *Stripe) Confirm(ctx context.Context, intentID string) (Intent, error) {
+	var obj stripeObject
+	if err := g.post(ctx, "/v1/payment_intents/"+url.PathEscape(intentID)+"/confirm", url.Values{}, "", &obj); err != nil {
+		return Intent{}, err
+	}
+
+	return obj.intent(), nil
+}
+
+func (g *Stripe) Refund(ctx context.Context, intentID string, amount money.Money) (Refund, error) {
+	form := url.Values{}
+	form.Set("payment_intent", intentID)
+	form.Set("amount", strconv.FormatInt(amount.Amount, 10))
+
+	var obj stripeObject
+	// one full refund per intent, retrying the same refund is safe
+	if err := g.post(ctx, "/v1/refunds", form, "refund-"+intentID, &obj); err != nil {
+		return Refund{}, err
+	}
+
+	return Refund{ID: obj.ID, IntentID: intentID, Amount: money.New(obj.Amount, strings.ToUpper(obj.Currency))}, nil
+}
+
+func (g *Stripe) VerifyWebhook(payload []byte, header func(key string) string) (Event, error) {
+	// the header is "t=timestamp,v1=signature,v1=..."
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header(stripeSignatureHeader), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return Event{}, ErrInvalidSignature
+	}
+	if age := time.Since(time.Unix(signedAt, 0)); age > stripeTolerance || age < -stripeTolerance {
+		return Event{}, ErrInvalidSignature
+	}
+
+	signed := append([]byte(timestamp+"."), payload...)
+	valid := false
+	for _, signature := range signatures {
+		if VerifySignature(g.WebhookSecret, signed, signature) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return Event{}, ErrInvalidSignature
+	}
+
+	var data stripeEvent
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return Event{}, err
+	}
+
+	obj := data.Data.Object
+	// Stripe send the currency in lower case
+	currency := strings.ToUpper(obj.Currency)
+	event := Event{
+		ID:        data.ID,
+		Type:      data.Type,
+		IntentID:  obj.ID,
+		Amount:    money.New(obj.Amount, currency),
+		CreatedAt: time.Unix(data.Created, 0),
+	}
+	switch data.Type {
+	case "payment_intent.succeeded":
+		// the amount actually captured, it's compared with the payment
+		event.Status = StatusSucceeded
+		event.Amount = money.New(obj.AmountReceived, currency)
+	case "payment_intent.payment_failed", "payment_intent.canceled":
+		event.Status = StatusFailed
+		if obj.LastError != nil {
+			event.FailureReason = obj.LastError.Message
+		}
+	case "charge.refunded":
+		// only the full refund refund the order
+		event.IntentID = obj.PaymentIntent
+		if obj.AmountRefunded == obj.Amount {
+			event.Status = StatusRefunded
+		}
+	}
+
+	return event, nil
+}
+
+func (g *Stripe) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.BaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(g.SecretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var failure struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.Unmarshal(body, &failure)
+		return fmt.Errorf("stripe: %s (%d)", failure.Error.Message, resp.StatusCode)
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+func (obj stripeObject) intent() Intent {
+	intent := Intent{
+		ID:           obj.ID,
+		ClientSecret: obj.ClientSecret,
+		Amount:       money.New(obj.Amount, strings.ToUpper(obj.Currency)),
+	}
+	switch obj.Status {
+	case "succeeded":
+		intent.Status = StatusSucceeded
+		intent.Amount.Amount = obj.AmountReceived
+	case "canceled":
+		intent.Status = StatusFailed
+	default:
+		// requires_payment_method, requires_confirmation, requires_action and processing
+		intent.Status = StatusPending
+	}
+	if obj.LastError != nil {
+		intent.FailureReason = obj.LastError.Message
+	}
+	return intent
+}
@@ -0,0 +1,90 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"myapp/pkg/money"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testWebhookSecret = "whsec_test"
+
+// signStripe return the Stripe-Signature header of the payload
+func signStripe(payload string, at time.Time) func(key string) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	signature := Sign(testWebhookSecret, []byte(timestamp+"."+payload))
+	return func(key string) string {
+		if key == stripeSignatureHeader {
+			return fmt.Sprintf("t=%s,v1=%s", timestamp, signature)
+		}
+		return ""
+	}
+}
+
+func TestStripeWebhook(t *testing.T) {
+	gateway := NewStripe("sk_test", testWebhookSecret)
+
+	tests := []struct {
+		name    string
+		payload string
+		want    Event
+	}{
+		{
+			"succeeded",
+			`{"id":"evt_1","type":"payment_intent.succeeded","created":1700000000,"data":{"object":{"id":"pi_1","amount":1999,"amount_received":1999,"currency":"usd"}}}`,
+			Event{ID: "evt_1", Type: "payment_intent.succeeded", IntentID: "pi_1", Status: StatusSucceeded, Amount: money.New(1999, "USD")},
+		},
+		{
+			// the received amount is compared with the payment, not the requested one
+			"partial capture",
+			`{"id":"evt_2","type":"payment_intent.succeeded","created":1700000000,"data":{"object":{"id":"pi_1","amount":1999,"amount_received":999,"currency":"usd"}}}`,
+			Event{ID: "evt_2", Type: "payment_intent.succeeded", IntentID: "pi_1", Status: StatusSucceeded, Amount: money.New(999, "USD")},
+		},
+		{
+			"failed",
+			`{"id":"evt_3","type":"payment_intent.payment_failed","created":1700000000,"data":{"object":{"id":"pi_1","amount":1999,"currency":"eur","last_payment_error":{"message":"Your card was declined."}}}}`,
+			Event{ID: "evt_3", Type: "payment_intent.payment_failed", IntentID: "pi_1", Status: StatusFailed, Amount: money.New(1999, "EUR"), FailureReason: "Your card was declined."},
+		},
+		{
+			"full refund",
+			`{"id":"evt_4","type":"charge.refunded","created":1700000000,"data":{"object":{"id":"ch_1","payment_intent":"pi_1","amount":1999,"amount_refunded":1999,"currency":"usd"}}}`,
+			Event{ID: "evt_4", Type: "charge.refunded", IntentID: "pi_1", Status: StatusRefunded, Amount: money.New(1999, "USD")},
+		},
+		{
+			// the partial refund doesn't refund the order
+			"partial refund",
+			`{"id":"evt_5","type":"charge.refunded","created":1700000000,"data":{"object":{"id":"ch_1","payment_intent":"pi_1","amount":1999,"amount_refunded":500,"currency":"usd"}}}`,
+			Event{ID: "evt_5", Type: "charge.refunded", IntentID: "pi_1", Amount: money.New(1999, "USD")},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := gateway.VerifyWebhook([]byte(tt.payload), signStripe(tt.payload, time.Now()))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		tt.want.CreatedAt = time.Unix(1700000000, 0)
+		if got != tt.want {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStripeWebhookSignature(t *testing.T) {
+	gateway := NewStripe("sk_test", testWebhookSecret)
+	payload := `{"id":"evt_1","type":"payment_intent.succeeded","created":1700000000,"data":{"object":{"id":"pi_1","amount":1999,"amount_received":1999,"currency":"usd"}}}`
+
+	tests := map[string]func(key string) string{
+		"tampered payload": signStripe(payload+" ", time.Now()),
+		"replayed":         signStripe(payload, time.Now().Add(-time.Hour)),
+		"missing":          func(key string) string { return "" },
+	}
+	for name, header := range tests {
+		if _, err := gateway.VerifyWebhook([]byte(payload), header); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: got %v, want ErrInvalidSignature", name, err)
+		}
+	}
+}
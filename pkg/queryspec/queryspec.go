@@ -0,0 +1,265 @@
+// Package queryspec parse the filter and sort query of the list endpoints
+// against a whitelist of fields and operators, and compile it to GORM scopes.
+//
+//	?filter[price][gte]=10&filter[is_enable]=true&sort=-price,title
+//
+// Only the columns declared in the Spec are put in the SQL, the values are always bound.
+package queryspec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Kind is the type of the field value
+type Kind int
+
+const (
+	String Kind = iota
+	Int
+	Number
+	Bool
+	Time
+)
+
+// Operator is the filter operator, filter[field]=value is the Eq operator
+type Operator string
+
+const (
+	Eq   Operator = "eq"
+	Ne   Operator = "ne"
+	Gt   Operator = "gt"
+	Gte  Operator = "gte"
+	Lt   Operator = "lt"
+	Lte  Operator = "lte"
+	Like Operator = "like"
+	In   Operator = "in"
+	// filter[field][null]=true is IS NULL, false is IS NOT NULL
+	Null Operator = "null"
+)
+
+// the maximum number of filters in a query
+const maxFilters = 20
+
+var sqlOperators = map[Operator]string{
+	Eq:  "=",
+	Ne:  "<>",
+	Gt:  ">",
+	Gte: ">=",
+	Lt:  "<",
+	Lte: "<=",
+}
+
+// Field is a field of the resource allowed in the query
+type Field struct {
+	// the column in the SQL, the field name is used when empty
+	Column string
+	Kind   Kind
+	// the allowed filter operators, empty is not filterable
+	Operators []Operator
+	Sortable  bool
+}
+
+// Spec is the whitelist of the resource
+type Spec struct {
+	Fields map[string]Field
+	// used when the sort query is empty, i. e. -id
+	DefaultSort string
+}
+
+type Filter struct {
+	Column   string
+	Operator Operator
+	Value    interface{}
+}
+
+type Sort struct {
+	// the field name, i. e. to detect the computed field
+	Field  string
+	Column string
+	Desc   bool
+}
+
+// Query is the parsed query of the request
+type Query struct {
+	Filters []Filter
+	Sorts   []Sort
+}
+
+// Parse read the filter[...] and sort query parameters of the request
+func (s Spec) Parse(c *fiber.Ctx) (Query, error) {
+	var query Query
+	var errParse error
+
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		if errParse != nil || !strings.HasPrefix(string(key), "filter[") {
+			return
+		}
+		if len(query.Filters) == maxFilters {
+			errParse = fmt.Errorf("too many filters, the maximum is %d", maxFilters)
+			return
+		}
+
+		filter, err := s.ParseFilter(string(key), string(value))
+		if err != nil {
+			errParse = err
+			return
+		}
+		query.Filters = append(query.Filters, filter)
+	})
+	if errParse != nil {
+		return query, errParse
+	}
+
+	sorts, err := s.ParseSort(c.Query("sort", s.DefaultSort))
+	if err != nil {
+		return query, err
+	}
+	query.Sorts = sorts
+
+	return query, nil
+}
+
+// ParseFilter parse filter[field][operator]=value
+func (s Spec) ParseFilter(key string, value string) (Filter, error) {
+	var filter Filter
+
+	// filter[price][gte] => price, gte
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(key, "filter["), "]"), "][")
+	if len(parts) > 2 || parts[0] == "" {
+		return filter, fmt.Errorf("malformed filter %q, should be filter[field] or filter[field][operator]", key)
+	}
+
+	name := parts[0]
+	operator := Eq
+	if len(parts) == 2 {
+		operator = Operator(parts[1])
+	}
+
+	field, ok := s.Fields[name]
+	if !ok || len(field.Operators) == 0 {
+		return filter, fmt.Errorf("unknown filter field %q", name)
+	}
+	if !operatorInSlice(field.Operators, operator) {
+		return filter, fmt.Errorf("operator %q is not allowed on the field %q", operator, name)
+	}
+
+	filter.Column = field.column(name)
+	filter.Operator = operator
+
+	var err error
+	switch operator {
+	case In:
+		values := []interface{}{}
+		for _, item := range strings.Split(value, ",") {
+			v, errValue := field.Kind.parse(strings.TrimSpace(item))
+			if errValue != nil {
+				return filter, fmt.Errorf("invalid value of the filter %q: %s", name, errValue.Error())
+			}
+			values = append(values, v)
+		}
+		filter.Value = values
+	case Null:
+		filter.Value, err = Bool.parse(value)
+	case Like:
+		// the wildcards of the user are searched literally
+		filter.Value = "%" + likeEscaper.Replace(value) + "%"
+	default:
+		filter.Value, err = field.Kind.parse(value)
+	}
+	if err != nil {
+		return filter, fmt.Errorf("invalid value of the filter %q: %s", name, err.Error())
+	}
+
+	return filter, nil
+}
+
+// ParseSort parse the comma separated fields, the "-" prefix is descending order,
+// the old format field|direction is still accepted
+func (s Spec) ParseSort(sortBy string) ([]Sort, error) {
+	var sorts []Sort
+
+	for _, item := range strings.Split(sortBy, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, desc := strings.TrimPrefix(item, "-"), strings.HasPrefix(item, "-")
+		if field, direction, ok := strings.Cut(item, "|"); ok {
+			switch strings.ToLower(direction) {
+			case "asc":
+				name, desc = field, false
+			case "desc":
+				name, desc = field, true
+			default:
+				return nil, fmt.Errorf("malformed sort direction %q, should be asc or desc", direction)
+			}
+		}
+
+		field, ok := s.Fields[name]
+		if !ok || !field.Sortable {
+			return nil, fmt.Errorf("unknown sort field %q", name)
+		}
+		sorts = append(sorts, Sort{Field: name, Column: field.column(name), Desc: desc})
+	}
+
+	return sorts, nil
+}
+
+// Scope apply the filters to the query
+func (q Query) Scope(db *gorm.DB) *gorm.DB {
+	for _, filter := range q.Filters {
+		switch filter.Operator {
+		case In:
+			db = db.Where(filter.Column+" IN ?", filter.Value)
+		case Null:
+			if filter.Value.(bool) {
+				db = db.Where(filter.Column + " IS NULL")
+			} else {
+				db = db.Where(filter.Column + " IS NOT NULL")
+			}
+		case Like:
+			db = db.Where(filter.Column+" ILIKE ?", filter.Value)
+		default:
+			db = db.Where(filter.Column+" "+sqlOperators[filter.Operator]+" ?", filter.Value)
+		}
+	}
+
+	return db
+}
+
+// OrderBy return the ORDER BY of the sorts, i. e. price desc, title asc
+func (q Query) OrderBy() string {
+	orders := make([]string, 0, len(q.Sorts))
+	for _, sort := range q.Sorts {
+		direction := "asc"
+		if sort.Desc {
+			direction = "desc"
+		}
+		orders = append(orders, sort.Column+" "+direction)
+	}
+
+	return strings.Join(orders, ", ")
+}
+
+func (f Field) column(name string) string {
+	if f.Column != "" {
+		return f.Column
+	}
+	return name
+}
+
+func operatorInSlice(operators []Operator, operator Operator) bool {
+	for _, v := range operators {
+		if v == operator {
+			return true
+		}
+	}
+	return false
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
@@ -0,0 +1,167 @@
+package queryspec
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var testSpec = Spec{
+	Fields: map[string]Field{
+		"id":         {Kind: Int, Operators: Comparable, Sortable: true},
+		"title":      {Kind: String, Operators: Text, Sortable: true},
+		"price":      {Column: "price_amount", Kind: Int, Operators: Comparable, Sortable: true},
+		"is_enable":  {Kind: Bool, Operators: Equality},
+		"created_at": {Kind: Time, Operators: Range, Sortable: true},
+		"deleted_at": {Kind: Time, Operators: []Operator{Null}},
+		"rank":       {Sortable: true},
+	},
+	DefaultSort: "-id",
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		key, value string
+		want       Filter
+	}{
+		{"filter[title]", "shoe", Filter{"title", Eq, "shoe"}},
+		{"filter[price][gte]", "1000", Filter{"price_amount", Gte, int64(1000)}},
+		{"filter[is_enable][ne]", "true", Filter{"is_enable", Ne, true}},
+		{"filter[deleted_at][null]", "false", Filter{"deleted_at", Null, false}},
+		{"filter[created_at][lt]", "2024-01-02", Filter{"created_at", Lt, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}},
+		{"filter[title][like]", `50%_off\`, Filter{"title", Like, `%50\%\_off\\%`}},
+	}
+
+	for _, tt := range tests {
+		got, err := testSpec.ParseFilter(tt.key, tt.value)
+		if err != nil {
+			t.Errorf("%s=%s: %v", tt.key, tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s=%s: got %#v, want %#v", tt.key, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterIn(t *testing.T) {
+	got, err := testSpec.ParseFilter("filter[id][in]", "1, 2,3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, ok := got.Value.([]interface{})
+	if !ok || len(values) != 3 || values[0] != int64(1) || values[1] != int64(2) || values[2] != int64(3) {
+		t.Errorf("got %#v, want [1 2 3]", got.Value)
+	}
+}
+
+func TestParseFilterRejected(t *testing.T) {
+	tests := []struct {
+		key, value, err string
+	}{
+		{"filter[password]", "x", "unknown filter field"},
+		{"filter[rank]", "1", "unknown filter field"},
+		{"filter[]", "x", "malformed filter"},
+		{"filter[price][gte][x]", "1", "malformed filter"},
+		{"filter[title][gt]", "a", "is not allowed"},
+		{"filter[created_at]", "2024-01-01", "is not allowed"},
+		{"filter[price]", "cheap", "invalid value"},
+		{"filter[id][in]", "1,x", "invalid value"},
+		{"filter[created_at][gt]", "yesterday", "invalid value"},
+		{"filter[deleted_at][null]", "maybe", "invalid value"},
+	}
+
+	for _, tt := range tests {
+		_, err := testSpec.ParseFilter(tt.key, tt.value)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s=%s: got error %v, want %q", tt.key, tt.value, err, tt.err)
+		}
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		sort string
+		want string
+	}{
+		{"-price,title", "price_amount desc, title asc"},
+		{" id , -created_at ", "id asc, created_at desc"},
+		{"price|desc", "price_amount desc"},
+		{"title|ASC", "title asc"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		sorts, err := testSpec.ParseSort(tt.sort)
+		if err != nil {
+			t.Errorf("%q: %v", tt.sort, err)
+			continue
+		}
+		if got := (Query{Sorts: sorts}).OrderBy(); got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.sort, got, tt.want)
+		}
+	}
+
+	for _, sort := range []string{"is_enable", "password", "title|up", "price; drop table products"} {
+		if _, err := testSpec.ParseSort(sort); err == nil {
+			t.Errorf("%q: want an error", sort)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	app := fiber.New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	c.Request().URI().SetQueryString("filter[price][gte]=10&filter[is_enable]=true&page=2")
+	query, err := testSpec.Parse(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(query.Filters) != 2 {
+		t.Errorf("got %d filters, want 2", len(query.Filters))
+	}
+	if got := query.OrderBy(); got != "id desc" {
+		t.Errorf("got the sort %q, want the default id desc", got)
+	}
+
+	var filters []string
+	for i := 0; i <= maxFilters; i++ {
+		filters = append(filters, "filter[id][ne]=1")
+	}
+	c.Request().URI().SetQueryString(strings.Join(filters, "&"))
+	if _, err := testSpec.Parse(c); err == nil || !strings.Contains(err.Error(), "too many filters") {
+		t.Errorf("got error %v, want too many filters", err)
+	}
+}
+
+func TestScope(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := Query{Filters: []Filter{
+		{"price_amount", Gte, int64(10)},
+		{"id", In, []interface{}{int64(1), int64(2)}},
+		{"deleted_at", Null, true},
+		{"published_at", Null, false},
+		{"title", Like, "%shoe%"},
+	}}
+
+	got := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var rows []map[string]interface{}
+		return tx.Table("products").Scopes(query.Scope).Find(&rows)
+	})
+	want := "SELECT * FROM \"products\" WHERE price_amount >= 10 AND id IN (1,2) AND deleted_at IS NULL AND published_at IS NOT NULL AND title ILIKE '%shoe%'"
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
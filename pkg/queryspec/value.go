@@ -0,0 +1,37 @@
+package queryspec
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// the common operators of the field
+var (
+	Equality   = []Operator{Eq, Ne, In}
+	Comparable = []Operator{Eq, Ne, Gt, Gte, Lt, Lte, In}
+	Text       = []Operator{Eq, Ne, Like, In}
+	Range      = []Operator{Gt, Gte, Lt, Lte}
+)
+
+// parse convert the query value to the type of the column
+func (k Kind) parse(value string) (interface{}, error) {
+	switch k {
+	case Int:
+		return strconv.ParseInt(value, 10, 64)
+	case Number:
+		return strconv.ParseFloat(value, 64)
+	case Bool:
+		return strconv.ParseBool(value)
+	case Time:
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, nil
+		}
+		if t, err := time.Parse("2006-01-02", value); err == nil {
+			return t, nil
+		}
+		return nil, errors.New("should be a date (2006-01-02) or RFC3339 time")
+	default:
+		return value, nil
+	}
+}
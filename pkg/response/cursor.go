@@ -0,0 +1,292 @@
+package response
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// the key of the cursor signature, a random key is used until SetCursorSecret is called,
+// so the cursors are only valid until the server restart
+var cursorSecret = randomCursorSecret()
+
+var errInvalidCursor = errors.New("invalid or expired cursor, please start again from the first page")
+
+// SetCursorSecret set the key of the cursor signature, empty keep the random key
+func SetCursorSecret(secret string) {
+	if secret != "" {
+		cursorSecret = []byte(secret)
+	}
+}
+
+// cursor is the position in the list, the values of the sort columns of the edge row
+type cursor struct {
+	// the ORDER BY, the cursor can't be used with another sort
+	Sort string `json:"s"`
+	// true is the rows before the cursor
+	Prev   bool              `json:"p,omitempty"`
+	Values []json.RawMessage `json:"v"`
+}
+
+type keysetColumn struct {
+	field *schema.Field
+	desc  bool
+}
+
+// PaginateCursor find the page after or before the cursor with keyset pagination,
+// the rows are ordered by pagination.Sort and the primary key as the tie-breaker.
+// dest is a pointer to the slice of model, the empty cursor is the first page
+func PaginateCursor(db *gorm.DB, dest interface{}, pagination *Pagination, param ParamsPagination) *fiber.Error {
+	pagination.cursorMode = true
+	pagination.Page = 0
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(dest); err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	columns, err := keysetColumns(stmt.Schema, pagination.GetSort())
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	pagination.Sort = keysetSort(columns, false)
+
+	// the estimated count is cheap, it's read from the query plan
+	if param.EstimateCount {
+		count, err := estimateCount(db, dest)
+		if err != nil {
+			return fiber.NewError(500, err.Error())
+		}
+		pagination.EstimatedCount = &count
+	}
+
+	query := db.Session(&gorm.Session{})
+	prev := false
+	if param.Cursor != "" {
+		current, err := decodeCursor(param.Cursor)
+		if err != nil || current.Sort != pagination.Sort || len(current.Values) != len(columns) {
+			return fiber.NewError(fiber.StatusBadRequest, errInvalidCursor.Error())
+		}
+
+		where, err := keysetWhere(columns, current)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, errInvalidCursor.Error())
+		}
+		query = query.Where(where)
+		prev = current.Prev
+	}
+
+	// the rows before the cursor are read backward, one more row tell there is another page
+	limit := pagination.GetLimit()
+	err = query.Order(keysetSort(columns, prev)).Limit(limit + 1).Find(dest).Error
+	if err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	rows := reflect.Indirect(reflect.ValueOf(dest))
+	more := rows.Len() > limit
+	if more {
+		rows.Set(rows.Slice(0, limit))
+	}
+	if prev {
+		reverseRows(rows)
+	}
+
+	if rows.Len() > 0 {
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		first, last := rows.Index(0), rows.Index(rows.Len()-1)
+
+		// there is a next page when more rows are found forward, or when we came back from it
+		if (!prev && more) || (prev && param.Cursor != "") {
+			next := encodeCursor(ctx, columns, last, pagination.Sort, false)
+			pagination.NextCursor = &next
+		}
+		if (prev && more) || (!prev && param.Cursor != "") {
+			previous := encodeCursor(ctx, columns, first, pagination.Sort, true)
+			pagination.PrevCursor = &previous
+		}
+	}
+
+	pagination.Data = rows.Interface()
+
+	return nil
+}
+
+// keysetColumns parse the ORDER BY and add the primary key, so the position is unique
+func keysetColumns(sch *schema.Schema, sort string) ([]keysetColumn, error) {
+	var columns []keysetColumn
+	primary := sch.PrioritizedPrimaryField
+	if primary == nil {
+		return nil, errors.New("cursor pagination needs a primary key")
+	}
+
+	hasPrimary := false
+	desc := true
+	for _, order := range strings.Split(sort, ",") {
+		name, direction, _ := strings.Cut(strings.TrimSpace(order), " ")
+		field := sch.LookUpField(strings.ToLower(name))
+		// the computed and the nullable column can't be compared
+		if field == nil || field.DBName == "" || (!field.Creatable && !field.Updatable) ||
+			field.FieldType.Kind() == reflect.Ptr {
+			return nil, fmt.Errorf("sort by %s isn't supported with the cursor pagination", name)
+		}
+
+		desc = strings.EqualFold(direction, "desc")
+		columns = append(columns, keysetColumn{field: field, desc: desc})
+		hasPrimary = hasPrimary || field == primary
+	}
+
+	// the tie-breaker follow the direction of the last column
+	if !hasPrimary {
+		columns = append(columns, keysetColumn{field: primary, desc: desc})
+	}
+
+	return columns, nil
+}
+
+// keysetSort return the ORDER BY, backward reverse all of the directions
+func keysetSort(columns []keysetColumn, backward bool) string {
+	orders := make([]string, 0, len(columns))
+	for _, column := range columns {
+		direction := "asc"
+		if column.desc != backward {
+			direction = "desc"
+		}
+		orders = append(orders, column.field.DBName+" "+direction)
+	}
+
+	return strings.Join(orders, ", ")
+}
+
+// keysetWhere select the rows after the cursor in the sort order (or before it),
+// (a > 1) OR (a = 1 AND b > 2) OR (a = 1 AND b = 2 AND id > 3)
+func keysetWhere(columns []keysetColumn, current cursor) (clause.Expression, error) {
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		value := reflect.New(column.field.FieldType)
+		if err := json.Unmarshal(current.Values[i], value.Interface()); err != nil {
+			return nil, err
+		}
+		values[i] = value.Elem().Interface()
+	}
+
+	var conditions []clause.Expression
+	for i, column := range columns {
+		var exprs []clause.Expression
+		for j := 0; j < i; j++ {
+			exprs = append(exprs, clause.Eq{Column: keysetColumnName(columns[j]), Value: values[j]})
+		}
+
+		if column.desc != current.Prev {
+			exprs = append(exprs, clause.Lt{Column: keysetColumnName(column), Value: values[i]})
+		} else {
+			exprs = append(exprs, clause.Gt{Column: keysetColumnName(column), Value: values[i]})
+		}
+		conditions = append(conditions, clause.And(exprs...))
+	}
+
+	return clause.Or(conditions...), nil
+}
+
+func keysetColumnName(column keysetColumn) clause.Column {
+	return clause.Column{Table: clause.CurrentTable, Name: column.field.DBName}
+}
+
+// estimateCount read the estimated rows of the query from the planner
+func estimateCount(db *gorm.DB, dest interface{}) (int64, error) {
+	dry := db.Session(&gorm.Session{DryRun: true}).Find(dest)
+	if dry.Error != nil {
+		return 0, dry.Error
+	}
+	stmt := dry.Statement
+
+	var plan string
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Raw("EXPLAIN (FORMAT JSON) "+stmt.SQL.String(), stmt.Vars...).Scan(&plan).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var result []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan), &result); err != nil || len(result) == 0 {
+		return 0, errors.New("can't read the estimated count")
+	}
+
+	return int64(result[0].Plan.Rows), nil
+}
+
+// encodeCursor sign the position of the row, base64(payload).base64(signature)
+func encodeCursor(ctx context.Context, columns []keysetColumn, row reflect.Value, sort string, prev bool) string {
+	current := cursor{Sort: sort, Prev: prev}
+	for _, column := range columns {
+		value, _ := column.field.ValueOf(ctx, row)
+		raw, _ := json.Marshal(value)
+		current.Values = append(current.Values, raw)
+	}
+
+	payload, _ := json.Marshal(current)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(signCursor(payload))
+}
+
+func decodeCursor(token string) (cursor, error) {
+	var current cursor
+
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return current, errInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return current, errInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, signCursor(payload)) {
+		return current, errInvalidCursor
+	}
+
+	if err := json.Unmarshal(payload, &current); err != nil {
+		return current, errInvalidCursor
+	}
+	return current, nil
+}
+
+func signCursor(payload []byte) []byte {
+	mac := hmac.New(sha256.New, cursorSecret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func reverseRows(rows reflect.Value) {
+	swap := reflect.Swapper(rows.Interface())
+	for i, j := 0, rows.Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
+
+func randomCursorSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+	return secret
+}
@@ -0,0 +1,184 @@
+package response
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type cursorRow struct {
+	ID        uint
+	Price     int64
+	Title     string
+	DeletedAt *time.Time
+}
+
+// fakeCursorDB build the SQL without a database, the query return the rows of the test and record the SQL
+func fakeCursorDB(t *testing.T, rows *[]cursorRow, sql *string) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Callback().Query().After("gorm:query").Register("test:rows", func(tx *gorm.DB) {
+		*sql = tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...)
+		reflect.ValueOf(tx.Statement.Dest).Elem().Set(reflect.ValueOf(append([]cursorRow{}, *rows...)))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+// paginate find the page of the cursor, the fake query return the rows
+func paginate(t *testing.T, db *gorm.DB, sort string, cursor *string) Pagination {
+	t.Helper()
+
+	param := ParamsPagination{CursorMode: true}
+	if cursor != nil {
+		param.Cursor = *cursor
+	}
+
+	var data []cursorRow
+	page := Pagination{Limit: 2, Sort: sort}
+	if err := PaginateCursor(db.Model(&cursorRow{}), &data, &page, param); err != nil {
+		t.Fatalf("%d %s", err.Code, err.Message)
+	}
+	return page
+}
+
+func cursorIDs(data interface{}) []uint {
+	var ids []uint
+	for _, row := range data.([]cursorRow) {
+		ids = append(ids, row.ID)
+	}
+	return ids
+}
+
+func TestPaginateCursor(t *testing.T) {
+	var rows []cursorRow
+	var sql string
+	db := fakeCursorDB(t, &rows, &sql)
+
+	// the first page, one more row tell there is a next page
+	rows = []cursorRow{{ID: 5}, {ID: 4}, {ID: 3}}
+	first := paginate(t, db, "", nil)
+	if want := `SELECT * FROM "cursor_rows" ORDER BY id desc LIMIT 3`; sql != want {
+		t.Errorf("first page:\ngot  %s\nwant %s", sql, want)
+	}
+	if ids := cursorIDs(first.Data); !reflect.DeepEqual(ids, []uint{5, 4}) {
+		t.Errorf("first page: got %v, want [5 4]", ids)
+	}
+	if first.NextCursor == nil || first.PrevCursor != nil {
+		t.Fatalf("first page: want only the next cursor, got next %v prev %v", first.NextCursor, first.PrevCursor)
+	}
+
+	// the last page, after the row 4
+	rows = []cursorRow{{ID: 3}, {ID: 2}}
+	last := paginate(t, db, "", first.NextCursor)
+	if want := `SELECT * FROM "cursor_rows" WHERE "cursor_rows"."id" < 4 ORDER BY id desc LIMIT 3`; sql != want {
+		t.Errorf("last page:\ngot  %s\nwant %s", sql, want)
+	}
+	if ids := cursorIDs(last.Data); !reflect.DeepEqual(ids, []uint{3, 2}) {
+		t.Errorf("last page: got %v, want [3 2]", ids)
+	}
+	if last.NextCursor != nil || last.PrevCursor == nil {
+		t.Fatalf("last page: want only the previous cursor, got next %v prev %v", last.NextCursor, last.PrevCursor)
+	}
+
+	// back to the first page, the rows before the row 3 are read backward and reversed
+	rows = []cursorRow{{ID: 4}, {ID: 5}}
+	back := paginate(t, db, "", last.PrevCursor)
+	if want := `SELECT * FROM "cursor_rows" WHERE "cursor_rows"."id" > 3 ORDER BY id asc LIMIT 3`; sql != want {
+		t.Errorf("previous page:\ngot  %s\nwant %s", sql, want)
+	}
+	if ids := cursorIDs(back.Data); !reflect.DeepEqual(ids, []uint{5, 4}) {
+		t.Errorf("previous page: got %v, want [5 4]", ids)
+	}
+	if back.NextCursor == nil || back.PrevCursor != nil {
+		t.Fatalf("previous page: want only the next cursor, got next %v prev %v", back.NextCursor, back.PrevCursor)
+	}
+}
+
+func TestPaginateCursorSortColumns(t *testing.T) {
+	var rows []cursorRow
+	var sql string
+	db := fakeCursorDB(t, &rows, &sql)
+
+	// the primary key break the ties in the direction of the last column
+	rows = []cursorRow{{ID: 7, Price: 900, Title: "b"}, {ID: 3, Price: 500, Title: "a"}, {ID: 9, Price: 500, Title: "a"}}
+	first := paginate(t, db, "price desc, title asc", nil)
+	if first.Sort != "price desc, title asc, id asc" {
+		t.Errorf("got the sort %q", first.Sort)
+	}
+	if first.NextCursor == nil {
+		t.Fatal("want the next cursor")
+	}
+
+	paginate(t, db, "price desc, title asc", first.NextCursor)
+	want := `SELECT * FROM "cursor_rows" WHERE ("cursor_rows"."price" < 500 OR ("cursor_rows"."price" = 500 AND "cursor_rows"."title" > 'a') OR ("cursor_rows"."price" = 500 AND "cursor_rows"."title" = 'a' AND "cursor_rows"."id" > 3)) ORDER BY price desc, title asc, id asc LIMIT 3`
+	if sql != want {
+		t.Errorf("got  %s\nwant %s", sql, want)
+	}
+}
+
+func TestPaginateCursorRejected(t *testing.T) {
+	var rows []cursorRow
+	var sql string
+	db := fakeCursorDB(t, &rows, &sql)
+
+	rows = []cursorRow{{ID: 5}, {ID: 4}, {ID: 3}}
+	first := paginate(t, db, "", nil)
+	next := *first.NextCursor
+	payload, signature, _ := strings.Cut(next, ".")
+
+	tests := map[string]struct {
+		sort, cursor string
+	}{
+		"tampered payload":   {"", "e30." + signature},
+		"tampered signature": {"", payload + ".AAAA"},
+		"not a cursor":       {"", "page-2"},
+		"another sort":       {"price asc", next},
+		"nullable column":    {"deleted_at desc", ""},
+		"unknown column":     {"secret desc", ""},
+	}
+	for name, tt := range tests {
+		var data []cursorRow
+		page := Pagination{Limit: 2, Sort: tt.sort}
+		err := PaginateCursor(db.Model(&cursorRow{}), &data, &page, ParamsPagination{Cursor: tt.cursor, CursorMode: true})
+		if err == nil || err.Code != 400 {
+			t.Errorf("%s: got %v, want a 400 error", name, err)
+		}
+	}
+}
+
+func TestCursorSecret(t *testing.T) {
+	defer func(secret []byte) { cursorSecret = secret }(cursorSecret)
+
+	var rows []cursorRow
+	var sql string
+	db := fakeCursorDB(t, &rows, &sql)
+
+	rows = []cursorRow{{ID: 5}, {ID: 4}, {ID: 3}}
+	first := paginate(t, db, "", nil)
+	if _, err := decodeCursor(*first.NextCursor); err != nil {
+		t.Fatalf("the cursor should be valid: %v", err)
+	}
+
+	// the cursors signed with another key are rejected
+	SetCursorSecret("another secret")
+	if _, err := decodeCursor(*first.NextCursor); err == nil {
+		t.Error("the cursor of the old key should be rejected")
+	}
+
+	// empty keep the key
+	SetCursorSecret("")
+	if string(cursorSecret) != "another secret" {
+		t.Error("the empty secret should keep the key")
+	}
+}
@@ -1,6 +1,7 @@
 package response
 
 import (
+	"encoding/json"
 	_ "log"
 
 	"gorm.io/gorm"
@@ -91,6 +92,35 @@ type Pagination struct {
 	Limit      int         `json:"limit,omitempty;query:limit"`
 	Sort       string      `json:"sort,omitempty;query:sort"`
 	Data       interface{} `json:"data"`
+
+	// cursor pagination, see PaginateCursor
+	NextCursor     *string `json:"next_cursor"`
+	PrevCursor     *string `json:"prev_cursor"`
+	EstimatedCount *int64  `json:"estimated_count,omitempty"`
+	cursorMode     bool
+}
+
+func (p Pagination) MarshalJSON() ([]byte, error) {
+	// the page number mode
+	if !p.cursorMode {
+		return json.Marshal(struct {
+			Count      int64       `json:"count"`
+			TotalPages int         `json:"total_pages"`
+			Page       int         `json:"page"`
+			Limit      int         `json:"limit"`
+			Sort       string      `json:"sort"`
+			Data       interface{} `json:"data"`
+		}{p.Count, p.TotalPages, p.Page, p.Limit, p.Sort, p.Data})
+	}
+
+	return json.Marshal(struct {
+		Limit          int         `json:"limit"`
+		Sort           string      `json:"sort"`
+		NextCursor     *string     `json:"next_cursor"`
+		PrevCursor     *string     `json:"prev_cursor"`
+		EstimatedCount *int64      `json:"estimated_count,omitempty"`
+		Data           interface{} `json:"data"`
+	}{p.Limit, p.Sort, p.NextCursor, p.PrevCursor, p.EstimatedCount, p.Data})
 }
 
 func (p *Pagination) GetOffset() int {
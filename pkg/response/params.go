@@ -1,5 +1,7 @@
 package response
 
+import "gorm.io/gorm"
+
 type ParamsPagination struct {
 	// "page": 1,
 	// "limit": 10,
@@ -12,4 +14,28 @@ type ParamsPagination struct {
 	SortQuery string
 	Search    string
 	NoPage    string
+	// the whitelisted filters of the query, nil is no filter
+	Filter func(db *gorm.DB) *gorm.DB
+	// ?cursor= use the cursor pagination, the empty cursor is the first page
+	Cursor        string
+	CursorMode    bool
+	EstimateCount bool
+}
+
+// MaxLimit is the largest page of the listings
+const MaxLimit = 100
+
+// ClampPage bound the page and the limit parsed from the query,
+// the page starts at 1 and the limit is between 1 and MaxLimit, 10 by default
+func ClampPage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	return page, limit
 }
@@ -0,0 +1,24 @@
+package response
+
+import "testing"
+
+func TestClampPage(t *testing.T) {
+	tests := []struct {
+		page, limit         int
+		wantPage, wantLimit int
+	}{
+		{1, 10, 1, 10},
+		{3, 100, 3, 100},
+		// the invalid numbers are parsed as 0
+		{0, 0, 1, 10},
+		{-2, -1, 1, 10},
+		{1, 1000000, 1, MaxLimit},
+	}
+
+	for _, tt := range tests {
+		page, limit := ClampPage(tt.page, tt.limit)
+		if page != tt.wantPage || limit != tt.wantLimit {
+			t.Errorf("ClampPage(%d, %d) = %d, %d, want %d, %d", tt.page, tt.limit, page, limit, tt.wantPage, tt.wantLimit)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+)
+
+// Every run the job in background on each interval,
+// a panic inside the job is recovered so the next tick still runs.
+func Every(name string, interval time.Duration, job func() error) {
+	if interval <= 0 {
+		log.Printf("⏰ Job %s is disabled", name)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			run(name, job)
+		}
+	}()
+
+	log.Printf("⏰ Job %s scheduled every %s", name, interval)
+}
+
+func run(name string, job func() error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job %s panic: %v", name, r)
+		}
+	}()
+
+	if err := job(); err != nil {
+		log.Printf("Job %s error: %s", name, err.Error())
+	}
+}
@@ -0,0 +1,82 @@
+// Package spreadsheet read and write the rows of the CSV and XLSX files,
+// the XLSX is the first sheet of the workbook with the cells as text
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+// ContentType of the formats
+var ContentType = map[string]string{
+	CSV:  "text/csv; charset=utf-8",
+	XLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// Format return the format of the file name, empty for the unsupported file
+func Format(fileName string) string {
+	name := strings.ToLower(fileName)
+	switch {
+	case strings.HasSuffix(name, ".csv"):
+		return CSV
+	case strings.HasSuffix(name, ".xlsx"):
+		return XLSX
+	}
+	return ""
+}
+
+// ReadAll return the rows of the file, the short rows are not padded
+func ReadAll(r io.ReaderAt, size int64, format string) ([][]string, error) {
+	switch format {
+	case CSV:
+		reader := csv.NewReader(io.NewSectionReader(r, 0, size))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		rows, err := reader.ReadAll()
+		// the BOM of the file saved by Excel
+		if len(rows) > 0 && len(rows[0]) > 0 {
+			rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+		}
+		return rows, err
+	case XLSX:
+		return readXLSX(r, size)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// Writer write the rows one by one, Close flush the file
+type Writer interface {
+	Write(row []string) error
+	Close() error
+}
+
+// NewWriter return the writer of the format
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case CSV:
+		return &csvWriter{csv.NewWriter(w)}, nil
+	case XLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(row []string) error {
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,174 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// zipParts build the workbook from its XML parts
+func zipParts(t *testing.T, parts map[string]string) *bytes.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, body := range parts {
+		f, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(body))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestFormat(t *testing.T) {
+	tests := map[string]string{
+		"products.csv":  CSV,
+		"Products.XLSX": XLSX,
+		"products.xls":  "",
+		"products.json": "",
+		"csv":           "",
+	}
+	for name, want := range tests {
+		if got := Format(name); got != want {
+			t.Errorf("Format(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"external_id", "title", "price"},
+		{"SKU-1", `Shoe, "red" <42> & co`, "19.99"},
+		{"SKU-2", "Multi\nline", ""},
+		{"SKU-3", "  spaced  "},
+		{"SKU-4", "Café ☕", "1500"},
+	}
+
+	for _, format := range []string{CSV, XLSX} {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, row := range rows {
+			if err := w.Write(row); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := ReadAll(bytes.NewReader(buf.Bytes()), int64(buf.Len()), format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		// the CSV writer quote the leading spaces, so they aren't trimmed
+		if !reflect.DeepEqual(got, rows) {
+			t.Errorf("%s:\ngot  %q\nwant %q", format, got, rows)
+		}
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	data := "\ufefftitle, price\nShoe,19.99\nHat\n"
+	got, err := ReadAll(strings.NewReader(data), int64(len(data)), CSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{{"title", "price"}, {"Shoe", "19.99"}, {"Hat"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := ReadAll(strings.NewReader(data), int64(len(data)), "xls"); err == nil {
+		t.Error("the unsupported format should be rejected")
+	}
+}
+
+// TestReadXLSX read the parts like Excel save them: the shared and rich strings, the booleans,
+// the skipped empty cells and the sheet found through the relationships
+func TestReadXLSX(t *testing.T) {
+	r := zipParts(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Products" sheetId="1" r:id="rId3"/><sheet name="Other" sheetId="2" r:id="rId4"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId4" Target="worksheets/sheet1.xml"/><Relationship Id="rId3" Target="/xl/worksheets/products.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+			<si><t>title</t></si><si><t>price</t></si><si><r><t>Red </t></r><r><t>shoe</t></r></si></sst>`,
+		"xl/worksheets/products.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="D1" t="inlineStr"><is><t>enabled</t></is></c></row>
+			<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2"><v>19.99</v></c><c r="D2" t="b"><v>1</v></c></row>
+			<row r="3"><c r="AA3" t="b"><v>0</v></c></row>
+		</sheetData></worksheet>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+			<row r="1"><c r="A1" t="inlineStr"><is><t>the second sheet</t></is></c></row>
+		</sheetData></worksheet>`,
+	})
+
+	got, err := ReadAll(r, r.Size(), XLSX)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	third := make([]string, 27)
+	third[26] = "FALSE"
+	want := [][]string{
+		{"title", "price", "", "enabled"},
+		{"Red shoe", "19.99", "", "TRUE"},
+		third,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestReadXLSXInvalid(t *testing.T) {
+	tests := map[string]map[string]string{
+		"no sheet": {"xl/workbook.xml": `<workbook/>`},
+		"shared string out of range": {
+			"xl/sharedStrings.xml":     `<sst><si><t>title</t></si></sst>`,
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row><c r="A1" t="s"><v>5</v></c></row></sheetData></worksheet>`,
+		},
+		"malformed sheet": {"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row>`},
+	}
+	for name, parts := range tests {
+		r := zipParts(t, parts)
+		if _, err := ReadAll(r, r.Size(), XLSX); err == nil {
+			t.Errorf("%s: want an error", name)
+		}
+	}
+
+	data := "title,price\n"
+	if _, err := ReadAll(strings.NewReader(data), int64(len(data)), XLSX); err == nil {
+		t.Error("the CSV isn't a workbook")
+	}
+}
+
+func TestColumn(t *testing.T) {
+	tests := map[string]int{"A1": 0, "C7": 2, "Z1": 25, "AA10": 26, "AZ1": 51, "ZZ1": 701, "AAA1": 702}
+	for ref, index := range tests {
+		if got := columnIndex(ref); got != index {
+			t.Errorf("columnIndex(%q) = %d, want %d", ref, got, index)
+		}
+		if got := columnName(index); got != strings.TrimRight(ref, "0123456789") {
+			t.Errorf("columnName(%d) = %q, want the column of %s", index, got, ref)
+		}
+	}
+
+	for i := 0; i < 20000; i++ {
+		if got := columnIndex(columnName(i) + "1"); got != i {
+			t.Fatalf("columnIndex(columnName(%d)) = %d", i, got)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ZipArchive write json documents and local files into a zip file
+type ZipArchive struct {
+	file   *os.File
+	writer *zip.Writer
+}
+
+func NewZipArchive(filePath string) (*ZipArchive, error) {
+	// create dir
+	if err := createFolder(filepath.Dir(filePath)); err != nil {
+		return nil, errors.New(err.Error())
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ZipArchive{file: file, writer: zip.NewWriter(file)}, nil
+}
+
+// AddJSON add the data as an indented json document
+func (a *ZipArchive) AddJSON(name string, data any) error {
+	w, err := a.writer.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// AddFile copy a local file into the archive, the file url on S3 is skipped
+func (a *ZipArchive) AddFile(name string, fileUrl string) error {
+	if strings.HasPrefix(fileUrl, "http") {
+		return nil
+	}
+
+	src, err := os.Open(fileUrl)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	w, err := a.writer.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, src)
+	return err
+}
+
+// Close flush the archive and return the size of the zip file
+func (a *ZipArchive) Close() (int64, error) {
+	if err := a.writer.Close(); err != nil {
+		a.file.Close()
+		return 0, err
+	}
+
+	info, err := a.file.Stat()
+	if err != nil {
+		a.file.Close()
+		return 0, err
+	}
+
+	return info.Size(), a.file.Close()
+}
@@ -5,3 +5,4 @@ const ERR_ENDPOINT_NOT_FOUND = "Sorry, Endpoint is not found"
 const ERR_SOMETHING_WENT_WRONG = "something went wrong"
 const ERR_CURRENT_USER_NOT_FOUND = "Unable to extract user from request context for unknown reason"
 const ERR_FORBIDDEN_UPDATE = "Oops, You Are Not Allowed to Access it!"
+const ERR_ACCOUNT_LOCKED = "Your account is inactive or suspended, please contact the administrator."
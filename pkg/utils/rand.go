@@ -13,14 +13,6 @@ func GetRandInt(min, max int) int {
 	return int(n) + min
 }
 
-func GetRandFloat(min, max float64) float64 {
-	const floatPrecision = 100
-	minInt := int(min * floatPrecision)
-	maxInt := int(max * floatPrecision)
-
-	return float64(GetRandInt(minInt, maxInt)) / floatPrecision
-}
-
 func GenerateRandomNumber(numberOfDigits int) (int, error) {
 	maxLimit := int64(int(math.Pow10(numberOfDigits)) - 1)
 	lowLimit := int(math.Pow10(numberOfDigits - 1))
@@ -7,3 +7,25 @@ func OwnerThis(user_id uint) func(db *gorm.DB) *gorm.DB {
 		return db.Where("user_id = ?", user_id)
 	}
 }
+
+// TenantThis scope the data owned by the tenant,
+// the organization data is shared by the members, otherwise the personal data of the user
+func TenantThis(user_id uint, organization_id uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if organization_id != 0 {
+			return db.Where("organization_id = ?", organization_id)
+		}
+		return db.Where("user_id = ? AND organization_id IS NULL", user_id)
+	}
+}
+
+// OrganizationThis scope the data visible in the organization,
+// organization_id 0 is the public data outside of any organization
+func OrganizationThis(organization_id uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if organization_id != 0 {
+			return db.Where("organization_id = ?", organization_id)
+		}
+		return db.Where("organization_id IS NULL")
+	}
+}
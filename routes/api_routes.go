@@ -1,10 +1,15 @@
 package routes
 
 import (
+	"log"
+	"myapp/pkg/configs"
+	"myapp/pkg/helpers"
+	"myapp/pkg/scheduler"
 	_handler "myapp/src/handler"
 	_admin "myapp/src/handler/admin"
 	_repo "myapp/src/repository"
 	_useCase "myapp/src/usecase"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -19,22 +24,102 @@ func APIRoutes(a *fiber.App, db *gorm.DB) {
 	repoUser := _repo.NewUserRepository(db)
 	repoProduct := _repo.NewProductRepository(db)
 	repoMyDrive := _repo.NewMyDriveRepository(db)
+	repoPasskey := _repo.NewPasskeyRepository(db)
+	repoInvitation := _repo.NewInvitationRepository(db)
+	repoDataExport := _repo.NewDataExportRepository(db)
+	repoOrganization := _repo.NewOrganizationRepository(db)
+	repoCategory := _repo.NewCategoryRepository(db)
+	repoProductVariant := _repo.NewProductVariantRepository(db)
+	repoProductImage := _repo.NewProductImageRepository(db)
+	repoExchangeRate := _repo.NewExchangeRateRepository(db)
+	repoProductReview := _repo.NewProductReviewRepository(db)
+	repoWishlist := _repo.NewWishlistRepository(db)
+	repoCart := _repo.NewCartRepository(db)
+	repoOrder := _repo.NewOrderRepository(db)
+	repoPayment := _repo.NewPaymentRepository(db)
+	repoProductImport := _repo.NewProductImportRepository(db)
+	repoProductRevision := _repo.NewProductRevisionRepository(db)
+	repoProductReport := _repo.NewProductReportRepository(db)
+	repoProductStat := _repo.NewProductStatRepository(db)
+
+	// register WebAuthn relying party
+	envConfig, _ := configs.LoadConfig(".")
+	webAuthn, err := helpers.NewWebAuthn(&envConfig)
+	if err != nil {
+		log.Fatalln("Failed to configure WebAuthn! \n", err.Error())
+	}
+
+	// register the payment provider
+	paymentGateway, err := helpers.NewPaymentGateway(&envConfig)
+	if err != nil {
+		log.Fatalln("Failed to configure the payment provider! \n", err.Error())
+	}
 
 	// register All USECASE
-	ucUser := _useCase.NewUserUsecase(repoUser)
-	ucProduct := _useCase.NewProductUsecase(repoProduct, repoUser)
+	ucUser := _useCase.NewUserUsecase(repoUser, repoInvitation)
+	ucProduct := _useCase.NewProductUsecase(repoProduct, repoUser, repoCategory, repoProductImage, repoExchangeRate, repoWishlist, repoProductStat, envConfig.BaseCurrency(), envConfig.CatalogCacheTTL)
 	ucMyDrive := _useCase.NewMyDriveUsecase(repoMyDrive, repoUser)
+	ucPasskey := _useCase.NewPasskeyUsecase(repoPasskey, repoUser, webAuthn)
+	ucInvitation := _useCase.NewInvitationUsecase(repoInvitation, repoUser)
+	ucDataExport := _useCase.NewDataExportUsecase(repoDataExport, repoUser, envConfig.DataExportMaxWorkers)
+	ucOrganization := _useCase.NewOrganizationUsecase(repoOrganization, repoUser)
+	ucCategory := _useCase.NewCategoryUsecase(repoCategory)
+	ucProductVariant := _useCase.NewProductVariantUsecase(repoProductVariant, repoProduct)
+	ucProductImage := _useCase.NewProductImageUsecase(repoProductImage, repoProduct)
+	ucExchangeRate := _useCase.NewExchangeRateUsecase(repoExchangeRate, repoProduct, envConfig.BaseCurrency())
+	ucProductReview := _useCase.NewProductReviewUsecase(repoProductReview, repoProduct)
+	ucWishlist := _useCase.NewWishlistUsecase(repoWishlist, repoProduct, repoProductStat)
+	ucCart := _useCase.NewCartUsecase(repoCart, repoProduct, repoOrder, repoProductVariant)
+	ucOrder := _useCase.NewOrderUsecase(repoOrder)
+	ucPayment := _useCase.NewPaymentUsecase(repoPayment, repoOrder, paymentGateway)
+	ucProductImport := _useCase.NewProductImportUsecase(repoProductImport, repoProduct, repoCategory, envConfig.BaseCurrency())
+	ucProductRevision := _useCase.NewProductRevisionUsecase(repoProductRevision, repoProduct, repoCategory)
+	ucProductReport := _useCase.NewProductReportUsecase(repoProductReport, repoProduct, repoCategory)
+	ucProductStat := _useCase.NewProductStatUsecase(repoProductStat, repoProduct)
 
 	// ROUTES
 	_handler.NewAuthHandler(v1, ucUser)
 	_handler.NewAccountHandler(v1, ucUser)
+	_handler.NewProductImportHandler(v1, ucProductImport)
 	_handler.NewProductHandler(v1, ucProduct)
+	_handler.NewCatalogHandler(v1, ucProduct, envConfig.CatalogCacheTTL)
+	_handler.NewProductVariantHandler(v1, ucProductVariant)
+	_handler.NewProductImageHandler(v1, ucProductImage)
+	_handler.NewProductRevisionHandler(v1, ucProductRevision)
+	_handler.NewProductReportHandler(v1, ucProductReport)
+	_handler.NewProductStatHandler(v1, ucProductStat)
+	_handler.NewProductReviewHandler(v1, ucProductReview)
+	_handler.NewWishlistHandler(v1, ucWishlist)
+	_handler.NewCartHandler(v1, ucCart)
+	_handler.NewOrderHandler(v1, ucOrder)
+	_handler.NewPaymentHandler(v1, ucPayment)
 	_handler.NewMyDriveHandler(v1, ucMyDrive)
+	_handler.NewPasskeyHandler(v1, ucPasskey)
+	_handler.NewInvitationHandler(v1, ucInvitation)
+	_handler.NewDataExportHandler(v1, ucDataExport)
+	_handler.NewOrganizationHandler(v1, ucOrganization)
+	_handler.NewCategoryHandler(v1, ucCategory)
+	_handler.NewExchangeRateHandler(v1, ucExchangeRate)
 
 	// ADMIN Routes
 	admin := v1.Group("/admin")
 	_admin.NewAdminUserHandler(admin, ucUser)
 	_admin.NewAdminProductHandler(admin, ucProduct)
+	_admin.NewAdminInvitationHandler(admin, ucInvitation)
+	_admin.NewAdminCategoryHandler(admin, ucCategory)
+	_admin.NewAdminExchangeRateHandler(admin, ucExchangeRate)
+	_admin.NewAdminProductReviewHandler(admin, ucProductReview)
+	_admin.NewAdminProductRevisionHandler(admin, ucProductRevision)
+	_admin.NewAdminProductReportHandler(admin, ucProductReport)
+	_admin.NewAdminProductStatHandler(admin, ucProductStat)
 	// test routes
 	_handler.NewEmailHandler(a, ucUser)
+	_handler.NewProductPageHandler(a, ucProduct)
+
+	// BACKGROUND JOBS
+	scheduler.Every("purge-deleted-accounts", envConfig.AccountPurgeInterval, ucUser.PurgeDeletedAccounts)
+	scheduler.Every("purge-expired-exports", time.Hour, ucDataExport.PurgeExpiredExport)
+	scheduler.Every("publish-scheduled-products", envConfig.ProductPublishInterval, ucProduct.PublishScheduled)
+	scheduler.Every("purge-product-trash", time.Hour, ucProduct.PurgeTrash)
+	scheduler.Every("flush-product-stats", envConfig.ProductStatsInterval, ucProductStat.FlushStats)
 }
@@ -223,7 +223,7 @@ func (h *AccountHandler) RequestDeleteAccount(c *fiber.Ctx) error {
 
 // DeleteAccount
 // @Summary      Delete Account
-// @Description  Delete your account
+// @Description  Schedule your account deletion, the account is purged after the grace period
 // @Tags         Accounts
 // @Accept       json
 // @Produce      json
@@ -253,12 +253,13 @@ func (h *AccountHandler) DeleteAccount(c *fiber.Ctx) error {
 		return c.Status(errD.Code).JSON(errD)
 	}
 
-	err := h.userUsecase.DeleteAccount(c, payload.Otp)
+	obj, err := h.userUsecase.DeleteAccount(c, payload.Otp)
 	if err != nil {
 		res.Code = err.Code
 		res.Message = err.Message
 		return c.Status(res.Code).JSON(res)
 	}
 
+	res.Message = "Your account will be deleted on " + obj.ScheduledAt.UTC().Format("02 January 2006") + ". We sent an email with a link to cancel it."
 	return c.Status(res.Code).JSON(res)
 }
@@ -0,0 +1,136 @@
+package admin
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminCategoryHandler struct {
+	uCase models.CategoryUsecase
+}
+
+func NewAdminCategoryHandler(r fiber.Router, uc models.CategoryUsecase) {
+	handler := &AdminCategoryHandler{
+		uCase: uc,
+	}
+
+	// ROUTES
+	api := r.Group("/categories")
+
+	// private API
+	api.Post("", middleware.AdminAuthMiddleware(), handler.Create)
+	api.Put("/:id", middleware.AdminAuthMiddleware(), handler.Update)
+	api.Delete("/:id", middleware.AdminAuthMiddleware(), handler.Delete)
+}
+
+// Create
+// @Summary      Create Category
+// @Description  Create a category, parent_id null is the root category
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param 		 body body models.CategoryInput true "Body"
+// @Success      201  {object}  models.Category
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/categories [post]
+func (h *AdminCategoryHandler) Create(c *fiber.Ctx) error {
+	var payload models.CategoryInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validations
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Create(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Update
+// @Summary      Update Category
+// @Description  Update or move a category, it can't be moved under its own descendants
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Category ID"
+// @Param 		 body body models.CategoryInput true "Body"
+// @Success      200  {object}  models.Category
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/admin/categories/{id} [put]
+func (h *AdminCategoryHandler) Update(c *fiber.Ctx) error {
+	var payload models.CategoryInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validations
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Update(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Delete
+// @Summary      Delete Category
+// @Description  Delete an empty category without sub categories
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Category ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/categories/{id} [delete]
+func (h *AdminCategoryHandler) Delete(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.Delete(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
@@ -0,0 +1,94 @@
+package admin
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminExchangeRateHandler struct {
+	uCase models.ExchangeRateUsecase
+}
+
+func NewAdminExchangeRateHandler(r fiber.Router, uc models.ExchangeRateUsecase) {
+	handler := &AdminExchangeRateHandler{
+		uCase: uc,
+	}
+
+	// ROUTES
+	api := r.Group("/exchange-rates")
+
+	// private API
+	api.Put("/:currency", middleware.AdminAuthMiddleware(), handler.Set)
+	api.Delete("/:currency", middleware.AdminAuthMiddleware(), handler.Delete)
+}
+
+// Set
+// @Summary      Set Exchange Rate
+// @Description  Create or replace the rate of the currency, the units of the currency for one unit of the base currency (DEFAULT_CURRENCY)
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        currency   path      string  true  "ISO 4217 currency, e.g. EUR"
+// @Param 		 body body models.ExchangeRateInput true "Body"
+// @Success      200  {object}  models.ExchangeRate
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/exchange-rates/{currency} [put]
+func (h *AdminExchangeRateHandler) Set(c *fiber.Ctx) error {
+	var payload models.ExchangeRateInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validations
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Set(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Delete
+// @Summary      Delete Exchange Rate
+// @Description  Delete the rate of the currency, the prices can't be converted to it anymore
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        currency   path      string  true  "ISO 4217 currency, e.g. EUR"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/exchange-rates/{currency} [delete]
+func (h *AdminExchangeRateHandler) Delete(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.Delete(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminInvitationHandler struct {
+	uCase models.InvitationUsecase
+}
+
+func NewAdminInvitationHandler(r fiber.Router, uc models.InvitationUsecase) {
+	handler := &AdminInvitationHandler{
+		uCase: uc,
+	}
+
+	// ROUTES
+	api := r.Group("/invitations")
+
+	// private API
+	api.Get("", middleware.AdminAuthMiddleware(), handler.ListInvitation)
+	api.Post("", middleware.AdminAuthMiddleware(), handler.Create)
+	api.Delete("/:id", middleware.AdminAuthMiddleware(), handler.Revoke)
+}
+
+func (h *AdminInvitationHandler) ListInvitation(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	pagination, err := h.uCase.ListInvitation(c, true)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(&pagination)
+}
+
+func (h *AdminInvitationHandler) Create(c *fiber.Ctx) error {
+	var payload models.InvitationInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validations
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Create(c, payload, true)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+func (h *AdminInvitationHandler) Revoke(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.Revoke(c, true); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
@@ -0,0 +1,151 @@
+package admin
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminProductReportHandler struct {
+	uCase models.ProductReportUsecase
+}
+
+func NewAdminProductReportHandler(r fiber.Router, uc models.ProductReportUsecase) {
+	handler := &AdminProductReportHandler{
+		uCase: uc,
+	}
+
+	// ROUTES
+	api := r.Group("/products")
+
+	// private API
+	api.Get("/reports", middleware.AdminAuthMiddleware(), handler.ListReport)
+	api.Get("/reports/:report_id", middleware.AdminAuthMiddleware(), handler.GetReport)
+	api.Put("/reports/:report_id/resolve", middleware.AdminAuthMiddleware(), handler.Resolve)
+	api.Post("/:id/unhide", middleware.AdminAuthMiddleware(), handler.Unhide)
+}
+
+// ListReport
+// @Summary      List of Product Report
+// @Description  The moderation queue, the oldest report first. filter[status]=open&filter[reason]=spam
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Pagination
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/products/reports [get]
+func (h *AdminProductReportHandler) ListReport(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	pagination, err := h.uCase.ListReport(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(&pagination)
+}
+
+// GetReport
+// @Summary      Get Product Report
+// @Description  The report with the reported product, deleted or not
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        report_id   path      int  true  "Report ID"
+// @Success      200  {object}  models.ProductReport
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/products/reports/{report_id} [get]
+func (h *AdminProductReportHandler) GetReport(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.GetReport(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Resolve
+// @Summary      Resolve Product Report
+// @Description  Dismiss the report, or hide, delete the product or warn the seller. The seller is notified by email with the note, the action resolve all open reports of the product except the dismiss
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        report_id   path      int  true  "Report ID"
+// @Param 		 body body models.ReportResolveInput true "Body"
+// @Success      200  {object}  models.ProductReport
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/products/reports/{report_id}/resolve [put]
+func (h *AdminProductReportHandler) Resolve(c *fiber.Ctx) error {
+	var payload models.ReportResolveInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validations
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Resolve(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Unhide
+// @Summary      Unhide Product
+// @Description  Show the product hidden by the moderation in the catalog again
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/products/{id}/unhide [post]
+func (h *AdminProductReportHandler) Unhide(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.Unhide(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
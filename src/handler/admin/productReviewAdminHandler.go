@@ -0,0 +1,122 @@
+package admin
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminProductReviewHandler struct {
+	uCase models.ProductReviewUsecase
+}
+
+func NewAdminProductReviewHandler(r fiber.Router, uc models.ProductReviewUsecase) {
+	handler := &AdminProductReviewHandler{
+		uCase: uc,
+	}
+
+	// ROUTES
+	api := r.Group("/reviews")
+
+	// private API
+	api.Get("", middleware.AdminAuthMiddleware(), handler.ListReview)
+	api.Put("/:review_id/moderate", middleware.AdminAuthMiddleware(), handler.Moderate)
+	api.Delete("/:review_id", middleware.AdminAuthMiddleware(), handler.Delete)
+}
+
+// ListReview
+// @Summary      List of Review
+// @Description  The reviews of all products, filter[status]=hidden&filter[product_id]=1
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Pagination
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/reviews [get]
+func (h *AdminProductReviewHandler) ListReview(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	pagination, err := h.uCase.ListAllReview(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(&pagination)
+}
+
+// Moderate
+// @Summary      Moderate Review
+// @Description  Approve or hide the review, the hidden review isn't counted in the product rating
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        review_id   path      int  true  "Review ID"
+// @Param 		 body body models.ReviewModerationInput true "Body"
+// @Success      200  {object}  models.ProductReview
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/reviews/{review_id}/moderate [put]
+func (h *AdminProductReviewHandler) Moderate(c *fiber.Ctx) error {
+	var payload models.ReviewModerationInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validations
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Moderate(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Delete
+// @Summary      Delete Review
+// @Description  Delete the review and its photo
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        review_id   path      int  true  "Review ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/reviews/{review_id} [delete]
+func (h *AdminProductReviewHandler) Delete(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.AdminDeleteReview(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminProductRevisionHandler struct {
+	uCase models.ProductRevisionUsecase
+}
+
+func NewAdminProductRevisionHandler(r fiber.Router, uc models.ProductRevisionUsecase) {
+	handler := &AdminProductRevisionHandler{
+		uCase: uc,
+	}
+
+	// ROUTES
+	api := r.Group("/products/:id")
+
+	// private API
+	api.Get("/history", middleware.AdminAuthMiddleware(), handler.History)
+	api.Post("/revert/:revision", middleware.AdminAuthMiddleware(), handler.Revert)
+}
+
+// History
+// @Summary      History of Product
+// @Description  The revisions of any product, the deleted product included
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Success      200  {object}  response.Pagination
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/products/{id}/history [get]
+func (h *AdminProductRevisionHandler) History(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	pagination, err := h.uCase.AdminHistory(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(&pagination)
+}
+
+// Revert
+// @Summary      Revert Product
+// @Description  Restore the fields of any product as they were after the revision, the deleted product can't be reverted
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int  true  "Product ID"
+// @Param        revision   path      int  true  "Revision ID"
+// @Success      200  {object}  models.Product
+// @Failure      404  {object}  models.ResponseError
+// @Failure      409  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/products/{id}/revert/{revision} [post]
+func (h *AdminProductRevisionHandler) Revert(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.AdminRevert(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
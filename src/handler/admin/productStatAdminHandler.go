@@ -0,0 +1,55 @@
+package admin
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminProductStatHandler struct {
+	uCase models.ProductStatUsecase
+}
+
+func NewAdminProductStatHandler(r fiber.Router, uc models.ProductStatUsecase) {
+	handler := &AdminProductStatHandler{
+		uCase: uc,
+	}
+
+	// ROUTES
+	api := r.Group("/stats")
+
+	// private API
+	api.Get("/products", middleware.AdminAuthMiddleware(), handler.TopProducts)
+}
+
+// TopProducts
+// @Summary      Top Products
+// @Description  The products with the most views, search impressions or favorites over the period, deleted or not
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        from    query     string  false  "First day, YYYY-MM-DD, 29 days before to by default"
+// @Param        to      query     string  false  "Last day, YYYY-MM-DD, today by default"
+// @Param        sort    query     string  false  "views (default), impressions or favorites"
+// @Param        limit   query     int     false  "1 to 100, 10 by default"
+// @Success      200  {array}   models.TopProduct
+// @Failure      400  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/stats/products [get]
+func (h *AdminProductStatHandler) TopProducts(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	data, err := h.uCase.TopProducts(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
@@ -23,12 +23,30 @@ func NewAdminUserHandler(r fiber.Router, uc models.UserUsecase) {
 	users := r.Group("/users")
 
 	users.Get("", middleware.AdminAuthMiddleware(), handler.ListUser)
+	users.Post("", middleware.AdminAuthMiddleware(), handler.CreateUser)
+	users.Get("/deletions", middleware.AdminAuthMiddleware(), handler.ListPendingDeletion)
+	users.Post("/restore", middleware.AdminAuthMiddleware(), handler.RestoreUser)
 
+	users.Get("/:id", middleware.AdminAuthMiddleware(), handler.GetUser)
+	users.Put("/:id", middleware.AdminAuthMiddleware(), handler.UpdateUser)
+	users.Put("/:id/status", middleware.AdminAuthMiddleware(), handler.UpdateUserStatus)
+	users.Put("/:id/staff", middleware.AdminAuthMiddleware(), handler.UpdateUserStaff)
+	users.Post("/:id/verify", middleware.AdminAuthMiddleware(), handler.VerifyUser)
+	users.Post("/:id/reset-password", middleware.AdminAuthMiddleware(), handler.ResetUserPassword)
 	users.Delete("/:id", middleware.AdminAuthMiddleware(), handler.DeleteUser)
 	users.Delete("/:id/unscoped", middleware.AdminAuthMiddleware(), handler.PermanentDeleteUser)
-	users.Post("/restore", middleware.AdminAuthMiddleware(), handler.RestoreUser)
 }
 
+// GetMe
+// @Summary      Admin GetMe
+// @Description  Get the current admin data
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  models.User
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/me [get]
 func (h *AdminUserHandler) GetMe(c *fiber.Ctx) error {
 	user, errLocal := c.Locals("user").(models.User)
 	if !errLocal {
@@ -42,6 +60,21 @@ func (h *AdminUserHandler) GetMe(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(user)
 }
 
+// ListUser
+// @Summary      List User
+// @Description  List of users with search, sort and pagination
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        search    query     string  false  "Search by first name, last name or email"
+// @Param        sort      query     string  false  "Sort field|direction, i.e. id|desc"
+// @Param        page      query     int     false  "Page"
+// @Param        per_page  query     int     false  "Per page"
+// @Param        no_page   query     string  false  "Without pagination"
+// @Success      200  {object}  response.Pagination
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/users [get]
 func (h *AdminUserHandler) ListUser(c *fiber.Ctx) error {
 	res := models.ResponseHTTP{
 		Code:    fiber.StatusOK,
@@ -63,6 +96,48 @@ func (h *AdminUserHandler) ListUser(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(&pagination)
 }
 
+// ListPendingDeletion
+// @Summary      List Pending Deletion
+// @Description  List of accounts waiting to be purged after the grace period
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        search    query     string  false  "Search by email"
+// @Param        page      query     int     false  "Page"
+// @Param        per_page  query     int     false  "Per page"
+// @Success      200  {object}  response.Pagination
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/users/deletions [get]
+func (h *AdminUserHandler) ListPendingDeletion(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	pagination, err := h.userUsecase.ListPendingDeletion(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(&pagination)
+}
+
+// DeleteUser
+// @Summary      Delete User
+// @Description  Soft delete the user, it can be restored
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      403  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/users/{id} [delete]
 func (h *AdminUserHandler) DeleteUser(c *fiber.Ctx) error {
 	res := models.ResponseHTTP{
 		Code:    fiber.StatusOK,
@@ -80,6 +155,19 @@ func (h *AdminUserHandler) DeleteUser(c *fiber.Ctx) error {
 	return c.Status(res.Code).JSON(res)
 }
 
+// PermanentDeleteUser
+// @Summary      Permanent Delete User
+// @Description  Delete the user with all of their data and files, the data of the organizations is given to the owner
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      403  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/users/{id}/unscoped [delete]
 func (h *AdminUserHandler) PermanentDeleteUser(c *fiber.Ctx) error {
 	res := models.ResponseHTTP{
 		Code:    fiber.StatusOK,
@@ -97,6 +185,18 @@ func (h *AdminUserHandler) PermanentDeleteUser(c *fiber.Ctx) error {
 	return c.Status(res.Code).JSON(res)
 }
 
+// RestoreUser
+// @Summary      Restore User
+// @Description  Restore the deleted user by email
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param 		 body body models.EmailInput true "Body"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/admin/users/restore [post]
 func (h *AdminUserHandler) RestoreUser(c *fiber.Ctx) error {
 	var payload models.EmailInput
 	res := models.ResponseHTTP{
@@ -125,3 +225,268 @@ func (h *AdminUserHandler) RestoreUser(c *fiber.Ctx) error {
 
 	return c.Status(res.Code).JSON(res)
 }
+
+// GetUser
+// @Summary      Get User
+// @Description  Get the user detail
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  models.User
+// @Failure      422  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/users/{id} [get]
+func (h *AdminUserHandler) GetUser(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	id := utils.StringToUint(c.Params("id"))
+
+	user, err := h.userUsecase.GetUser(c, id)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(user)
+}
+
+// CreateUser
+// @Summary      Create User
+// @Description  Create a user, a verification email is sent when the user is not verified
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param 		 body body models.AdminCreateUserInput true "Body"
+// @Success      201  {object}  models.User
+// @Failure      400  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/users [post]
+func (h *AdminUserHandler) CreateUser(c *fiber.Ctx) error {
+	var payload models.AdminCreateUserInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+	payload.Sanitize()
+
+	// form POST validations
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	user, err := h.userUsecase.CreateUser(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(user)
+}
+
+// UpdateUser
+// @Summary      Update User
+// @Description  Update the user data and profile
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Param 		 body body models.AdminUpdateUserInput true "Body"
+// @Success      200  {object}  models.User
+// @Failure      400  {object}  models.ResponseError
+// @Failure      403  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/users/{id} [put]
+func (h *AdminUserHandler) UpdateUser(c *fiber.Ctx) error {
+	var payload models.AdminUpdateUserInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+	payload.Sanitize()
+
+	// form POST validations
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	id := utils.StringToUint(c.Params("id"))
+
+	user, err := h.userUsecase.UpdateUser(c, id, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(user)
+}
+
+// UpdateUserStatus
+// @Summary      Update User Status
+// @Description  Change the user status (1 Active, 2 Inactive, 3 Pending, 4 Suspended), the inactive and suspended users are signed out and can't sign in
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Param 		 body body models.AdminUserStatusInput true "Body"
+// @Success      200  {object}  models.User
+// @Failure      403  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/users/{id}/status [put]
+func (h *AdminUserHandler) UpdateUserStatus(c *fiber.Ctx) error {
+	var payload models.AdminUserStatusInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validations
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	id := utils.StringToUint(c.Params("id"))
+
+	user, err := h.userUsecase.UpdateUserStatus(c, id, payload.StatusID)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(user)
+}
+
+// UpdateUserStaff
+// @Summary      Update User Staff
+// @Description  Grant or revoke the staff access
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Param 		 body body models.AdminUserStaffInput true "Body"
+// @Success      200  {object}  models.User
+// @Failure      403  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/users/{id}/staff [put]
+func (h *AdminUserHandler) UpdateUserStaff(c *fiber.Ctx) error {
+	var payload models.AdminUserStaffInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	id := utils.StringToUint(c.Params("id"))
+
+	user, err := h.userUsecase.UpdateUserStaff(c, id, payload.IsStaff)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(user)
+}
+
+// VerifyUser
+// @Summary      Verify User
+// @Description  Force verify the user email
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  models.User
+// @Failure      403  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/users/{id}/verify [post]
+func (h *AdminUserHandler) VerifyUser(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	id := utils.StringToUint(c.Params("id"))
+
+	user, err := h.userUsecase.VerifyUser(c, id)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(user)
+}
+
+// ResetUserPassword
+// @Summary      Reset User Password
+// @Description  Send the reset password OTP to the user email
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      403  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/admin/users/{id}/reset-password [post]
+func (h *AdminUserHandler) ResetUserPassword(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	id := utils.StringToUint(c.Params("id"))
+
+	if err := h.userUsecase.ResetUserPassword(c, id); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	res.Message = "We sent an email with a OTP code to the user email."
+	return c.Status(res.Code).JSON(res)
+}
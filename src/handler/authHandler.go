@@ -83,7 +83,7 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 		payload.Phone = phone_number_validated
 	}
 
-	err := h.userUsecase.Register(c.Context(), payload)
+	user, err := h.userUsecase.Register(c.Context(), payload)
 	if err != nil {
 		res.Code = err.Code
 		res.Message = err.Message
@@ -92,6 +92,9 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 
 	res.Code = fiber.StatusCreated
 	res.Message = "We sent an email with a verification link to " + payload.Email + ". Check your inbox."
+	if user.Verified {
+		res.Message = "Your account has been created. You can login now."
+	}
 	return c.Status(res.Code).JSON(res)
 }
 
@@ -146,6 +149,7 @@ func (h *AuthHandler) RequestVerifyCode(c *fiber.Ctx) error {
 // @Param 		 body body models.LoginInput true "Body"
 // @Success      200  {object}  models.Token
 // @Failure      400  {object}  models.ResponseError
+// @Failure      403  {object}  models.ResponseError
 // @Failure      422  {object}  models.ResponseHTTP
 // @Failure      500  {object}  models.ResponseError
 // @Router       /v1/auth/login [post]
@@ -193,6 +197,7 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 // @Param 		 body body models.RefreshTokenInput true "Body"
 // @Success      200  {object}  models.Token
 // @Failure      400  {object}  models.ResponseError
+// @Failure      403  {object}  models.ResponseError
 // @Failure      422  {object}  models.ResponseHTTP
 // @Failure      500  {object}  models.ResponseError
 // @Router       /v1/auth/refresh [post]
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CartHandler struct {
+	uCase models.CartUsecase
+}
+
+func NewCartHandler(r fiber.Router, uc models.CartUsecase) {
+	handler := &CartHandler{
+		uCase: uc,
+	}
+
+	cart := r.Group("/cart", middleware.JWTAuthMiddleware())
+
+	cart.Get("", handler.GetCart)
+	cart.Delete("", handler.Clear)
+	cart.Post("/items", handler.AddItem)
+	cart.Put("/items/:item_id", handler.UpdateItem)
+	cart.Delete("/items/:item_id", handler.RemoveItem)
+	cart.Post("/checkout", handler.Checkout)
+}
+
+// GetCart
+// @Summary      Get Cart
+// @Description  My cart with the current prices, the problem of each item is filled when it can't be bought now
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  models.Cart
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/cart [get]
+func (h *CartHandler) GetCart(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.GetCart(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// AddItem
+// @Summary      Add to Cart
+// @Description  Add the product to my cart, the quantity is added to the same product and variant. The quantity is checked against the stock
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Param 		 body body models.CartItemInput true "Body"
+// @Success      200  {object}  models.Cart
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/cart/items [post]
+func (h *CartHandler) AddItem(c *fiber.Ctx) error {
+	var payload models.CartItemInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validations
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.AddItem(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// UpdateItem
+// @Summary      Update Cart Item
+// @Description  Change the quantity of the item in my cart
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Param        item_id   path      int  true  "Cart item ID"
+// @Param 		 body body models.CartQuantityInput true "Body"
+// @Success      200  {object}  models.Cart
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/cart/items/{item_id} [put]
+func (h *CartHandler) UpdateItem(c *fiber.Ctx) error {
+	var payload models.CartQuantityInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validations
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.UpdateItem(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// RemoveItem
+// @Summary      Remove Cart Item
+// @Description  Remove the item from my cart
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Param        item_id   path      int  true  "Cart item ID"
+// @Success      200  {object}  models.Cart
+// @Failure      404  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/cart/items/{item_id} [delete]
+func (h *CartHandler) RemoveItem(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.RemoveItem(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Clear
+// @Summary      Clear Cart
+// @Description  Remove all items from my cart
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/cart [delete]
+func (h *CartHandler) Clear(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.Clear(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
+
+// Checkout
+// @Summary      Checkout
+// @Description  Create the pending orders of my cart, one order per seller. The items keep the title and price of the checkout, the stock is taken and the cart is emptied
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Param 		 body body models.CheckoutInput true "Body"
+// @Success      201  {array}   models.Order
+// @Failure      409  {object}  models.ResponseHTTP
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/cart/checkout [post]
+func (h *CartHandler) Checkout(c *fiber.Ctx) error {
+	var payload models.CheckoutInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validations
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	data, err := h.uCase.Checkout(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
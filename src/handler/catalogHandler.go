@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"myapp/src/models"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CatalogHandler struct {
+	uCase models.ProductUsecase
+	// the max-age of the responses, 0 make the clients revalidate each time
+	maxAge time.Duration
+}
+
+// NewCatalogHandler serve the public catalog without authentication, for the website
+func NewCatalogHandler(r fiber.Router, uc models.ProductUsecase, maxAge time.Duration) {
+	handler := &CatalogHandler{
+		uCase:  uc,
+		maxAge: maxAge,
+	}
+
+	catalog := r.Group("/catalog")
+
+	catalog.Get("/products", handler.ListProduct)
+	catalog.Get("/products/:id", handler.GetProduct)
+}
+
+// ListProduct
+// @Summary      Public List of Product
+// @Description  The live products outside of any organization, without authentication and without the private fields of the seller. Same query as the product list, the first pages without search are cached until a change of a product, its reviews, its favorites or the rates. The response has ETag and Last-Modified, the conditional request get 304
+// @Tags         Catalog
+// @Accept       json
+// @Produce      json
+// @Param        category_id  query     int     false  "Filter by category and its sub categories"
+// @Param        tags         query     string  false  "Filter by tags, comma separated, match all"
+// @Param        currency     query     string  false  "Convert the prices to the currency"
+// @Success      200  {object}  response.Pagination
+// @Success      304  {string}  string  "Not modified since If-None-Match or If-Modified-Since"
+// @Failure      400  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Router       /v1/catalog/products [get]
+func (h *CatalogHandler) ListProduct(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	page, err := h.uCase.PublicListProduct(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return h.sendCacheable(c, page.Body, page.ModifiedAt)
+}
+
+// GetProduct
+// @Summary      Public Product
+// @Description  The live product outside of any organization, without authentication and without the private fields of the seller. The response has ETag and Last-Modified, the later of the product change and the catalog change (rating, favorites, rates), the conditional request get 304
+// @Tags         Catalog
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Success      200  {object}  models.PublicProduct
+// @Success      304  {string}  string  "Not modified since If-None-Match or If-Modified-Since"
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Router       /v1/catalog/products/{id} [get]
+func (h *CatalogHandler) GetProduct(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, modified, err := h.uCase.PublicProduct(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	body, errJSON := json.Marshal(obj)
+	if errJSON != nil {
+		res.Code = fiber.StatusInternalServerError
+		res.Message = errJSON.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return h.sendCacheable(c, body, modified)
+}
+
+// sendCacheable send the JSON body with its validators, or 304 when the client already has it
+func (h *CatalogHandler) sendCacheable(c *fiber.Ctx, body []byte, modified time.Time) error {
+	// weak, the body may be compressed on the way
+	etag := fmt.Sprintf(`W/"%x"`, sha1.Sum(body))
+
+	cacheControl := "public, no-cache"
+	if seconds := int(h.maxAge.Seconds()); seconds > 0 {
+		cacheControl = fmt.Sprintf("public, max-age=%d", seconds)
+	}
+
+	c.Set(fiber.HeaderCacheControl, cacheControl)
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderLastModified, modified.UTC().Format(http.TimeFormat))
+
+	if notModified(c, etag, modified) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(fiber.StatusOK).Send(body)
+}
+
+// notModified check the conditional request, If-None-Match take precedence over If-Modified-Since
+func notModified(c *fiber.Ctx, etag string, modified time.Time) bool {
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		for _, tag := range strings.Split(noneMatch, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+	// the header has a precision of a second
+	return !modified.Truncate(time.Second).After(since)
+}
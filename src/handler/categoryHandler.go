@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CategoryHandler struct {
+	uCase models.CategoryUsecase
+}
+
+func NewCategoryHandler(r fiber.Router, uc models.CategoryUsecase) {
+	handler := &CategoryHandler{
+		uCase: uc,
+	}
+
+	categories := r.Group("/categories")
+
+	categories.Get("", middleware.JWTAuthMiddleware(), handler.ListCategory)
+	categories.Get("/:id", middleware.JWTAuthMiddleware(), handler.GetCategory)
+
+	r.Get("/tags", middleware.JWTAuthMiddleware(), handler.ListTag)
+}
+
+// ListCategory
+// @Summary      List of Category
+// @Description  Category tree with the number of products in the active organization
+// @Tags         Categories
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   models.Category
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/categories [get]
+func (h *CategoryHandler) ListCategory(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	data, err := h.uCase.ListCategory(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
+
+// GetCategory
+// @Summary      Get Category
+// @Description  Get category with its sub categories
+// @Tags         Categories
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Category ID"
+// @Success      200  {object}  models.Category
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/categories/{id} [get]
+func (h *CategoryHandler) GetCategory(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.GetCategory(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// ListTag
+// @Summary      List of Tag
+// @Description  List of product tags, use search for autocomplete
+// @Tags         Categories
+// @Accept       json
+// @Produce      json
+// @Param        search    query     string  false  "Search tag name"
+// @Param        per_page  query     int     false  "Limit, max 100"
+// @Success      200  {array}   models.Tag
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/tags [get]
+func (h *CategoryHandler) ListTag(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	data, err := h.uCase.ListTag(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
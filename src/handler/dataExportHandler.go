@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+	"path/filepath"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DataExportHandler struct {
+	uCase models.DataExportUsecase
+}
+
+func NewDataExportHandler(r fiber.Router, uc models.DataExportUsecase) {
+	handler := &DataExportHandler{
+		uCase: uc,
+	}
+
+	// ROUTES
+	export := r.Group("/accounts/export")
+	export.Get("/:code", handler.Download)
+
+	// private API
+	export.Get("", middleware.JWTAuthMiddleware(), handler.ListExport)
+	export.Post("", middleware.JWTAuthMiddleware(), handler.RequestExport)
+}
+
+// ListExport
+// @Summary      List Data Export
+// @Description  List of your personal data exports
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   models.DataExport
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/accounts/export [get]
+func (h *DataExportHandler) ListExport(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	data, err := h.uCase.ListExport(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
+
+// RequestExport
+// @Summary      Request Data Export
+// @Description  Build a ZIP archive of your personal data, the download link is sent by email
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Success      202  {object}  models.DataExport
+// @Failure      422  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/accounts/export [post]
+func (h *DataExportHandler) RequestExport(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusAccepted,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.RequestExport(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Download
+// @Summary      Download Data Export
+// @Description  Download the archive from the link in the email
+// @Tags         Accounts
+// @Produce      application/zip
+// @Param        code   path      string  true  "Download code"
+// @Success      200  {file}    file
+// @Failure      404  {object}  models.ResponseError
+// @Failure      410  {object}  models.ResponseError
+// @Router       /v1/accounts/export/{code} [get]
+func (h *DataExportHandler) Download(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.Download(c.Context(), c.Params("code"))
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Download(obj.FilePath, filepath.Base(obj.FilePath))
+}
@@ -18,11 +18,18 @@ func NewEmailHandler(r *fiber.App, uc models.UserUsecase) {
 	}
 
 	r.Get("/verify-email/:verificationCode", handler.VerifyEmail)
+	r.Get("/cancel-deletion/:code", handler.CancelDeletion)
 
 	// test view email
 	r.Get("/view/register-email", handler.ViewRegisterEmail)
 	r.Get("/view/verify-success", handler.ViewVerifySuccess)
 	r.Get("/view/otp", handler.ViewOtpEmail)
+	r.Get("/view/invitation", handler.ViewInvitationEmail)
+	r.Get("/view/account-deletion", handler.ViewAccountDeletionEmail)
+	r.Get("/view/deletion-cancelled", handler.ViewDeletionCancelled)
+	r.Get("/view/data-export", handler.ViewDataExportEmail)
+	r.Get("/view/organization-invitation", handler.ViewOrganizationInvitationEmail)
+	r.Get("/view/low-stock", handler.ViewLowStockEmail)
 
 }
 
@@ -43,6 +50,23 @@ func (h *EmailHandler) VerifyEmail(c *fiber.Ctx) error {
 	return c.Render("emails/verification_done", emailData)
 }
 
+func (h *EmailHandler) CancelDeletion(c *fiber.Ctx) error {
+	code := c.Params("code")
+
+	if err := h.userUsecase.CancelDeleteAccountByCode(c.Context(), code); err != nil {
+		errD := fiber.NewError(err.Code, err.Message)
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	siteData, _ := configs.GetSiteData(".")
+	emailData := helpers.EmailData{
+		Subject:  "Account deletion cancelled",
+		SiteData: siteData,
+	}
+
+	return c.Render("emails/deletion_cancelled", emailData)
+}
+
 func (h *EmailHandler) ViewRegisterEmail(c *fiber.Ctx) error {
 	siteData, _ := configs.GetSiteData(".")
 
@@ -81,3 +105,89 @@ func (h *EmailHandler) ViewOtpEmail(c *fiber.Ctx) error {
 
 	return c.Render("emails/otp_code", emailData)
 }
+
+func (h *EmailHandler) ViewInvitationEmail(c *fiber.Ctx) error {
+	siteData, _ := configs.GetSiteData(".")
+
+	emailData := helpers.EmailData{
+		URL:          siteData.ClientOrigin + "/register?invitation_code=" + "QdkGUPVhjqu7sy7hGQqsGmg2YOOx9OIcyZQveNPljRpmWuE9NKMQ1pz6x49mEGfm",
+		FirstName:    "farrid@example.com",
+		Subject:      "You are invited to join " + siteData.AppName,
+		Message:      "This invitation is valid until 01 January 2030 00:00 UTC.",
+		TypeOfAction: "Invitation",
+		SiteData:     siteData,
+	}
+
+	return c.Render("emails/invitation", emailData)
+}
+
+func (h *EmailHandler) ViewAccountDeletionEmail(c *fiber.Ctx) error {
+	siteData, _ := configs.GetSiteData(".")
+
+	emailData := helpers.EmailData{
+		URL:          siteData.ClientOrigin + "/cancel-deletion/" + "QdkGUPVhjqu7sy7hGQqsGmg2YOOx9OIcyZQveNPljRpmWuE9NKMQ1pz6x49mEGfm",
+		FirstName:    "farrid",
+		Subject:      "Your account will be deleted",
+		Message:      "Your account and all of your data will be deleted permanently on 01 January 2030 00:00 UTC.",
+		TypeOfAction: "Delete Account",
+		SiteData:     siteData,
+	}
+
+	return c.Render("emails/account_deletion", emailData)
+}
+
+func (h *EmailHandler) ViewDeletionCancelled(c *fiber.Ctx) error {
+	siteData, _ := configs.GetSiteData(".")
+
+	emailData := helpers.EmailData{
+		Subject:  "Account deletion cancelled",
+		SiteData: siteData,
+	}
+
+	return c.Render("emails/deletion_cancelled", emailData)
+}
+
+func (h *EmailHandler) ViewDataExportEmail(c *fiber.Ctx) error {
+	siteData, _ := configs.GetSiteData(".")
+
+	emailData := helpers.EmailData{
+		URL:          siteData.ClientOrigin + "/api/v1/accounts/export/" + "QdkGUPVhjqu7sy7hGQqsGmg2YOOx9OIcyZQveNPljRpmWuE9NKMQ1pz6x49mEGfm",
+		FirstName:    "farrid",
+		Subject:      "Your data export is ready",
+		Message:      "This download link is valid until 01 January 2030 00:00 UTC.",
+		TypeOfAction: "Data Export",
+		SiteData:     siteData,
+	}
+
+	return c.Render("emails/data_export", emailData)
+}
+
+func (h *EmailHandler) ViewOrganizationInvitationEmail(c *fiber.Ctx) error {
+	siteData, _ := configs.GetSiteData(".")
+
+	emailData := helpers.EmailData{
+		URL:          siteData.ClientOrigin + "/organizations/invitations/" + "QdkGUPVhjqu7sy7hGQqsGmg2YOOx9OIc",
+		FirstName:    "farrid@example.com",
+		Subject:      "You are invited to join Acme",
+		Message:      "You are invited to join Acme as member. This invitation is valid until 01 January 2030 00:00 UTC.",
+		TypeOfAction: "Organization Invitation",
+		SiteData:     siteData,
+	}
+
+	return c.Render("emails/organization_invitation", emailData)
+}
+
+func (h *EmailHandler) ViewLowStockEmail(c *fiber.Ctx) error {
+	siteData, _ := configs.GetSiteData(".")
+
+	emailData := helpers.EmailData{
+		URL:          siteData.ClientOrigin + "/products/1",
+		FirstName:    "farrid",
+		Subject:      "Low stock: T-Shirt (TSHIRT-RED-M)",
+		Message:      "T-Shirt (TSHIRT-RED-M) has 3 item(s) left in stock.",
+		TypeOfAction: "Low Stock",
+		SiteData:     siteData,
+	}
+
+	return c.Render("emails/low_stock", emailData)
+}
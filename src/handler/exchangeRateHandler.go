@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExchangeRateHandler struct {
+	uCase models.ExchangeRateUsecase
+}
+
+func NewExchangeRateHandler(r fiber.Router, uc models.ExchangeRateUsecase) {
+	handler := &ExchangeRateHandler{
+		uCase: uc,
+	}
+
+	r.Get("/exchange-rates", middleware.JWTAuthMiddleware(), handler.ListRate)
+}
+
+// ListRate
+// @Summary      List of Exchange Rate
+// @Description  The units of each currency for one unit of the base currency, the base currency is first with the rate 1
+// @Tags         Exchange Rates
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   models.ExchangeRate
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/exchange-rates [get]
+func (h *ExchangeRateHandler) ListRate(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	data, err := h.uCase.ListRate(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type InvitationHandler struct {
+	uCase models.InvitationUsecase
+}
+
+func NewInvitationHandler(r fiber.Router, uc models.InvitationUsecase) {
+	handler := &InvitationHandler{
+		uCase: uc,
+	}
+
+	// ROUTES
+	r.Get("/auth/invitations/:code", handler.CheckInvitation)
+
+	// private API
+	invitations := r.Group("/accounts/invitations")
+	invitations.Get("", middleware.JWTAuthMiddleware(), handler.MyInvitation)
+	invitations.Post("", middleware.JWTAuthMiddleware(), handler.Create)
+	invitations.Delete("/:id", middleware.JWTAuthMiddleware(), handler.Revoke)
+}
+
+// CheckInvitation
+// @Summary      Check Invitation
+// @Description  Check the invitation code before register
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        code   path      string  true  "Invitation code"
+// @Success      200  {object}  models.Invitation
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Router       /v1/auth/invitations/{code} [get]
+func (h *InvitationHandler) CheckInvitation(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.CheckInvitation(c, c.Params("code"))
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// MyInvitation
+// @Summary      My Invitation
+// @Description  List of invitations you have sent
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Pagination
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/accounts/invitations [get]
+func (h *InvitationHandler) MyInvitation(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	pagination, err := h.uCase.ListInvitation(c, false)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(&pagination)
+}
+
+// Create
+// @Summary      Invite User
+// @Description  Send an invitation email, only when invitation by user is enabled
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Param 		 body body models.InvitationInput true "Body"
+// @Success      201  {object}  models.Invitation
+// @Failure      403  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/accounts/invitations [post]
+func (h *InvitationHandler) Create(c *fiber.Ctx) error {
+	var payload models.InvitationInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Create(c, payload, false)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Revoke
+// @Summary      Revoke Invitation
+// @Description  Revoke your pending invitation
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Invitation ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/accounts/invitations/{id} [delete]
+func (h *InvitationHandler) Revoke(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.Revoke(c, false); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OrderHandler struct {
+	uCase models.OrderUsecase
+}
+
+func NewOrderHandler(r fiber.Router, uc models.OrderUsecase) {
+	handler := &OrderHandler{
+		uCase: uc,
+	}
+
+	orders := r.Group("/orders", middleware.JWTAuthMiddleware())
+
+	orders.Get("", handler.MyOrder)
+	orders.Get("/sales", handler.MySale)
+	orders.Get("/:id", handler.GetOrder)
+	orders.Post("/:id/cancel", handler.Cancel)
+	orders.Put("/:id/status", handler.ChangeStatus)
+}
+
+// MyOrder
+// @Summary      List of My Order
+// @Description  My purchases with their items. filter[status]=pending&sort=-total
+// @Tags         Orders
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Pagination
+// @Failure      400  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/orders [get]
+func (h *OrderHandler) MyOrder(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	data, err := h.uCase.MyOrder(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
+
+// MySale
+// @Summary      List of My Sale
+// @Description  The orders of my products in the active workspace, the owner and admin see all sales of the organization
+// @Tags         Orders
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Pagination
+// @Failure      400  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/orders/sales [get]
+func (h *OrderHandler) MySale(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	data, err := h.uCase.MySale(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
+
+// GetOrder
+// @Summary      Get Order
+// @Description  Get the order of the buyer or the seller
+// @Tags         Orders
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {object}  models.Order
+// @Failure      404  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/orders/{id} [get]
+func (h *OrderHandler) GetOrder(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.GetOrder(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Cancel
+// @Summary      Cancel Order
+// @Description  The buyer cancel the pending order, the items are put back in stock
+// @Tags         Orders
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {object}  models.Order
+// @Failure      404  {object}  models.ResponseError
+// @Failure      409  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/orders/{id}/cancel [post]
+func (h *OrderHandler) Cancel(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.Cancel(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// ChangeStatus
+// @Summary      Change Order Status
+// @Description  The seller move the order: pending -> cancelled, paid -> shipped, shipped -> completed. Paid and refunded are only set by the payment and its refund. The buyer is notified by email
+// @Tags         Orders
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Order ID"
+// @Param 		 body body models.OrderStatusInput true "Body"
+// @Success      200  {object}  models.Order
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      409  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/orders/{id}/status [put]
+func (h *OrderHandler) ChangeStatus(c *fiber.Ctx) error {
+	var payload models.OrderStatusInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validations
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.ChangeStatus(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
@@ -0,0 +1,467 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OrganizationHandler struct {
+	uCase models.OrganizationUsecase
+}
+
+func NewOrganizationHandler(r fiber.Router, uc models.OrganizationUsecase) {
+	handler := &OrganizationHandler{
+		uCase: uc,
+	}
+
+	orgs := r.Group("/organizations", middleware.JWTAuthMiddleware())
+
+	orgs.Get("", handler.MyOrganization)
+	orgs.Post("", handler.CreateOrganization)
+	orgs.Post("/switch", handler.SwitchOrganization)
+	orgs.Post("/invitations/:code/accept", handler.AcceptInvitation)
+	orgs.Get("/:id", handler.GetOrganization)
+	orgs.Put("/:id", handler.UpdateOrganization)
+	orgs.Delete("/:id", handler.DeleteOrganization)
+
+	// members
+	orgs.Get("/:id/members", handler.ListMember)
+	orgs.Put("/:id/members/:user_id", handler.UpdateMember)
+	orgs.Delete("/:id/members/:user_id", handler.RemoveMember)
+
+	// invitations
+	orgs.Get("/:id/invitations", handler.ListInvitation)
+	orgs.Post("/:id/invitations", handler.Invite)
+	orgs.Delete("/:id/invitations/:invitation_id", handler.RevokeInvitation)
+}
+
+// MyOrganization
+// @Summary      My Organization
+// @Description  List of organizations the current user is a member of
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   models.OrganizationMember
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/organizations [get]
+func (h *OrganizationHandler) MyOrganization(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	data, err := h.uCase.MyOrganization(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
+
+// GetOrganization
+// @Summary      Get Organization
+// @Description  Get organization's data
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Organization ID"
+// @Success      200  {object}  models.Organization
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/organizations/{id} [get]
+func (h *OrganizationHandler) GetOrganization(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.Get(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// CreateOrganization
+// @Summary      Create Organization
+// @Description  Create a new organization, the current user is the owner
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param 		 body body models.OrganizationInput true "Body"
+// @Success      201  {object}  models.Organization
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/organizations [post]
+func (h *OrganizationHandler) CreateOrganization(c *fiber.Ctx) error {
+	var payload models.OrganizationInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Create(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// UpdateOrganization
+// @Summary      Update Organization
+// @Description  Update organization's data, only for the owner and admin
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Organization ID"
+// @Param 		 body body models.OrganizationInput true "Body"
+// @Success      200  {object}  models.Organization
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/organizations/{id} [put]
+func (h *OrganizationHandler) UpdateOrganization(c *fiber.Ctx) error {
+	var payload models.OrganizationInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Update(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// DeleteOrganization
+// @Summary      Delete Organization
+// @Description  Delete the organization, only for the owner
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Organization ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/organizations/{id} [delete]
+func (h *OrganizationHandler) DeleteOrganization(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.Delete(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
+
+// SwitchOrganization
+// @Summary      Switch Organization
+// @Description  Switch the active organization, return a new pair of token. Use organization_id 0 for the personal workspace
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param 		 body body models.OrganizationSwitchInput true "Body"
+// @Success      200  {object}  models.Token
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/organizations/switch [post]
+func (h *OrganizationHandler) SwitchOrganization(c *fiber.Ctx) error {
+	var payload models.OrganizationSwitchInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	token, err := h.uCase.Switch(c, payload.OrganizationID)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(token)
+}
+
+// ListMember
+// @Summary      List Member
+// @Description  List of organization's members
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Organization ID"
+// @Success      200  {object}  response.Pagination
+// @Failure      404  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/organizations/{id}/members [get]
+func (h *OrganizationHandler) ListMember(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	pagination, err := h.uCase.ListMember(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(&pagination)
+}
+
+// UpdateMember
+// @Summary      Update Member
+// @Description  Change the role of a member, only the owner can grant or revoke the owner role
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Organization ID"
+// @Param        user_id   path      int  true  "User ID"
+// @Param 		 body body models.OrganizationMemberInput true "Body"
+// @Success      200  {object}  models.OrganizationMember
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/organizations/{id}/members/{user_id} [put]
+func (h *OrganizationHandler) UpdateMember(c *fiber.Ctx) error {
+	var payload models.OrganizationMemberInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.UpdateMember(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// RemoveMember
+// @Summary      Remove Member
+// @Description  Remove a member from the organization, use your own user ID to leave
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Organization ID"
+// @Param        user_id   path      int  true  "User ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/organizations/{id}/members/{user_id} [delete]
+func (h *OrganizationHandler) RemoveMember(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.RemoveMember(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
+
+// ListInvitation
+// @Summary      List Invitation
+// @Description  List of organization's invitations, only for the owner and admin
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Organization ID"
+// @Success      200  {object}  response.Pagination
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/organizations/{id}/invitations [get]
+func (h *OrganizationHandler) ListInvitation(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	pagination, err := h.uCase.ListInvitation(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(&pagination)
+}
+
+// Invite
+// @Summary      Invite Member
+// @Description  Invite someone by email to join the organization
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Organization ID"
+// @Param 		 body body models.OrganizationInviteInput true "Body"
+// @Success      201  {object}  models.OrganizationInvitation
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/organizations/{id}/invitations [post]
+func (h *OrganizationHandler) Invite(c *fiber.Ctx) error {
+	var payload models.OrganizationInviteInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Invite(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// RevokeInvitation
+// @Summary      Revoke Invitation
+// @Description  Revoke a pending invitation
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Organization ID"
+// @Param        invitation_id   path      int  true  "Invitation ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/organizations/{id}/invitations/{invitation_id} [delete]
+func (h *OrganizationHandler) RevokeInvitation(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.RevokeInvitation(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
+
+// AcceptInvitation
+// @Summary      Accept Invitation
+// @Description  Join the organization with the code from the invitation email
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param        code   path      string  true  "Invitation code"
+// @Success      200  {object}  models.OrganizationMember
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/organizations/invitations/{code}/accept [post]
+func (h *OrganizationHandler) AcceptInvitation(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.AcceptInvitation(c, c.Params("code"))
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PasskeyHandler struct {
+	uCase models.PasskeyUsecase
+}
+
+func NewPasskeyHandler(r fiber.Router, uc models.PasskeyUsecase) {
+	handler := &PasskeyHandler{
+		uCase: uc,
+	}
+
+	// ROUTES
+	auth := r.Group("/auth/passkeys")
+	auth.Post("/login/begin", handler.BeginLogin)
+	auth.Post("/login/finish", handler.FinishLogin)
+
+	// private API
+	passkeys := r.Group("/accounts/passkeys")
+	passkeys.Get("", middleware.JWTAuthMiddleware(), handler.ListPasskey)
+	passkeys.Post("/register/begin", middleware.JWTAuthMiddleware(), handler.BeginRegistration)
+	passkeys.Post("/register/finish", middleware.JWTAuthMiddleware(), handler.FinishRegistration)
+	passkeys.Put("/:id", middleware.JWTAuthMiddleware(), handler.Rename)
+	passkeys.Delete("/:id", middleware.JWTAuthMiddleware(), handler.Delete)
+}
+
+// BeginLogin
+// @Summary      Begin Passkey Login
+// @Description  Get the WebAuthn assertion options, leave email empty for discoverable login
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param 		 body body models.PasskeyLoginInput false "Body"
+// @Success      200  {object}  models.PasskeyLoginOptions
+// @Failure      400  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Router       /v1/auth/passkeys/login/begin [post]
+func (h *PasskeyHandler) BeginLogin(c *fiber.Ctx) error {
+	var payload models.PasskeyLoginInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&payload); err != nil {
+			res.Code = fiber.StatusBadRequest
+			res.Message = err.Error()
+			return c.Status(res.Code).JSON(res)
+		}
+	}
+
+	options, err := h.uCase.BeginLogin(c.Context(), payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(options)
+}
+
+// FinishLogin
+// @Summary      Finish Passkey Login
+// @Description  Verify the WebAuthn assertion and get your token
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        session_id query string true "Session ID from login begin"
+// @Param 		 body body object true "PublicKeyCredential from navigator.credentials.get()"
+// @Success      200  {object}  models.Token
+// @Failure      400  {object}  models.ResponseError
+// @Failure      401  {object}  models.ResponseError
+// @Failure      403  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Router       /v1/auth/passkeys/login/finish [post]
+func (h *PasskeyHandler) FinishLogin(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	sessionID := c.Query("session_id")
+	if sessionID == "" {
+		res.Code = fiber.StatusBadRequest
+		res.Message = "session_id is required"
+		return c.Status(res.Code).JSON(res)
+	}
+
+	token, err := h.uCase.FinishLogin(c.Context(), sessionID, c.Body())
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(&token)
+}
+
+// ListPasskey
+// @Summary      List of Passkey
+// @Description  List of all your passkeys
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  []models.Passkey
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/accounts/passkeys [get]
+func (h *PasskeyHandler) ListPasskey(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	data, err := h.uCase.ListPasskey(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
+
+// BeginRegistration
+// @Summary      Begin Passkey Registration
+// @Description  Get the WebAuthn creation options for a new passkey
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  object
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/accounts/passkeys/register/begin [post]
+func (h *PasskeyHandler) BeginRegistration(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	options, err := h.uCase.BeginRegistration(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(options)
+}
+
+// FinishRegistration
+// @Summary      Finish Passkey Registration
+// @Description  Verify the WebAuthn attestation and save the passkey
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Param        name query string false "Passkey name"
+// @Param 		 body body object true "PublicKeyCredential from navigator.credentials.create()"
+// @Success      201  {object}  models.Passkey
+// @Failure      400  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/accounts/passkeys/register/finish [post]
+func (h *PasskeyHandler) FinishRegistration(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.FinishRegistration(c, c.Query("name"))
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Rename
+// @Summary      Rename Passkey
+// @Description  Rename your passkey
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Passkey ID"
+// @Param 		 body body models.PasskeyRenameInput true "Body"
+// @Success      200  {object}  models.Passkey
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/accounts/passkeys/{id} [put]
+func (h *PasskeyHandler) Rename(c *fiber.Ctx) error {
+	var payload models.PasskeyRenameInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Rename(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Delete
+// @Summary      Delete Passkey
+// @Description  Remove your passkey
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Passkey ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/accounts/passkeys/{id} [delete]
+func (h *PasskeyHandler) Delete(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.Delete(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PaymentHandler struct {
+	uCase models.PaymentUsecase
+}
+
+func NewPaymentHandler(r fiber.Router, uc models.PaymentUsecase) {
+	handler := &PaymentHandler{
+		uCase: uc,
+	}
+
+	// the provider sign the webhook, there is no login
+	r.Post("/payments/webhook/:provider", handler.Webhook)
+
+	payments := r.Group("/orders/:id/payment", middleware.JWTAuthMiddleware())
+
+	payments.Get("", handler.GetPayment)
+	payments.Post("", handler.CreatePayment)
+	payments.Post("/confirm", handler.ConfirmPayment)
+	payments.Post("/refund", handler.Refund)
+}
+
+// GetPayment
+// @Summary      Get Payment
+// @Description  The last payment of the order, for the buyer and the seller
+// @Tags         Payments
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {object}  models.Payment
+// @Failure      404  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/orders/{id}/payment [get]
+func (h *PaymentHandler) GetPayment(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.GetPayment(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// CreatePayment
+// @Summary      Pay Order
+// @Description  The buyer start the payment of the pending order, the client confirm it with the client_secret. The pending payment is returned again
+// @Tags         Payments
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Order ID"
+// @Success      201  {object}  models.Payment
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Failure      502  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/orders/{id}/payment [post]
+func (h *PaymentHandler) CreatePayment(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.CreatePayment(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// ConfirmPayment
+// @Summary      Confirm Payment
+// @Description  Confirm the payment at the provider from the server, the fake provider succeed without any card. The paid order is moved to paid
+// @Tags         Payments
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {object}  models.Payment
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Failure      502  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/orders/{id}/payment/confirm [post]
+func (h *PaymentHandler) ConfirmPayment(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.ConfirmPayment(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Refund
+// @Summary      Refund Payment
+// @Description  The seller refund the full payment at the provider, the order is moved to refunded
+// @Tags         Payments
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {object}  models.Payment
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Failure      502  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/orders/{id}/payment/refund [post]
+func (h *PaymentHandler) Refund(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.Refund(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Webhook
+// @Summary      Payment Webhook
+// @Description  The signed event of the provider, the same event is applied once and the late events are ignored. The fake provider sign the body with HMAC-SHA256 hex in X-Payment-Signature
+// @Tags         Payments
+// @Accept       json
+// @Produce      json
+// @Param        provider   path      string  true  "Provider (fake, stripe)"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      400  {object}  models.ResponseError
+// @Failure      401  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Router       /v1/payments/webhook/{provider} [post]
+func (h *PaymentHandler) Webhook(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.Webhook(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
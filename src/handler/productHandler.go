@@ -20,9 +20,14 @@ func NewProductHandler(r fiber.Router, uc models.ProductUsecase) {
 
 	products.Get("/my-products", middleware.JWTAuthMiddleware(), handler.MyProduct)
 	products.Get("", middleware.JWTAuthMiddleware(), handler.ListProduct)
+	products.Get("/by-slug/:slug", middleware.JWTAuthMiddleware(), handler.GetBySlug)
+	products.Get("/trash", middleware.JWTAuthMiddleware(), handler.ListTrash)
+	products.Post("/trash/:id/restore", middleware.JWTAuthMiddleware(), handler.Restore)
+	products.Delete("/trash/:id", middleware.JWTAuthMiddleware(), handler.DeletePermanently)
 	products.Get("/:id", middleware.JWTAuthMiddleware(), handler.GetProduct)
 	products.Post("", middleware.JWTAuthMiddleware(), handler.CreateProduct)
 	products.Put("/:id", middleware.JWTAuthMiddleware(), handler.UpdateProduct)
+	products.Put("/:id/status", middleware.JWTAuthMiddleware(), handler.ChangeStatus)
 	products.Delete("/:id", middleware.JWTAuthMiddleware(), handler.DeleteProduct)
 }
 
@@ -32,6 +37,8 @@ func NewProductHandler(r fiber.Router, uc models.ProductUsecase) {
 // @Tags         Products
 // @Accept       json
 // @Produce      json
+// @Param        category_id  query     int     false  "Filter by category and its sub categories"
+// @Param        tags         query     string  false  "Filter by tags, comma separated, match all"
 // @Success      200  {object}  response.Pagination
 // @Failure      500  {object}  models.ResponseError
 // @Security 	 BearerAuth
@@ -106,6 +113,39 @@ func (h *ProductHandler) GetProduct(c *fiber.Ctx) error {
 	return c.Status(res.Code).JSON(obj)
 }
 
+// GetBySlug
+// @Summary      Get Product by Slug
+// @Description  Get details of product by its slug, a previous slug of the product redirect to the current one
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        slug   path      string  true  "Product slug"
+// @Success      200  {object}  models.Product
+// @Success      301  {string}  string  "Location of the current slug"
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/by-slug/{slug} [get]
+func (h *ProductHandler) GetBySlug(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, redirect, err := h.uCase.GetBySlug(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	if redirect != "" {
+		return c.Redirect("/api/v1/products/by-slug/"+redirect, fiber.StatusMovedPermanently)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
 // CreateProduct
 // @Summary      Create Product
 // @Description  Create new product
@@ -114,7 +154,7 @@ func (h *ProductHandler) GetProduct(c *fiber.Ctx) error {
 // @Accept       multipart/form-data
 // @Produce      json
 // @Param 		 body formData models.ProductInput true "Body"
-// @Param 		 file formData file false "File to upload" format(multipart/form-data)
+// @Param 		 image formData file false "Images to add to the gallery" format(multipart/form-data)
 // @Success      200  {object}  models.Product
 // @Failure      400  {object}  models.ResponseError
 // @Failure      422  {object}  models.ResponseHTTP
@@ -159,7 +199,7 @@ func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        id   path      int  true  "Product ID"
 // @Param 		 body formData models.ProductInput true "Body"
-// @Param 		 file formData file false "File to upload" format(multipart/form-data)
+// @Param 		 image formData file false "Images to add to the gallery" format(multipart/form-data)
 // @Success      200  {object}  models.Product
 // @Failure      404  {object}  models.ResponseError
 // @Failure      422  {object}  models.ResponseHTTP
@@ -195,9 +235,53 @@ func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 	return c.Status(res.Code).JSON(obj)
 }
 
+// ChangeStatus
+// @Summary      Change Product Status
+// @Description  Move the product in the publishing workflow: draft, scheduled (publish_at), published or archived. The product is archived on unpublish_at.
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param 		 body body models.ProductStatusInput true "Body"
+// @Success      200  {object}  models.Product
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/status [put]
+func (h *ProductHandler) ChangeStatus(c *fiber.Ctx) error {
+	var payload models.ProductStatusInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.ChangeStatus(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
 // DeleteProduct
 // @Summary      Delete Product
-// @Description  Delete product's data
+// @Description  Move the product to the trash, see /v1/products/trash
 // @Tags         Products
 // @Accept       json
 // @Produce      json
@@ -221,3 +305,85 @@ func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
 
 	return c.Status(res.Code).JSON(res)
 }
+
+// ListTrash
+// @Summary      List of Deleted Product
+// @Description  The deleted products the user can manage, they are purged with their images after PRODUCT_TRASH_DAYS days. sort=-deleted_at
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Pagination
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/trash [get]
+func (h *ProductHandler) ListTrash(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	pagination, err := h.uCase.ListTrash(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(&pagination)
+}
+
+// Restore
+// @Summary      Restore Product
+// @Description  Move the deleted product out of the trash
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Success      200  {object}  models.Product
+// @Failure      404  {object}  models.ResponseError
+// @Failure      409  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/trash/{id}/restore [post]
+func (h *ProductHandler) Restore(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.Restore(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// DeletePermanently
+// @Summary      Delete Product Permanently
+// @Description  Delete the product of the trash with its variants, images, reviews and history, the orders keep their snapshot
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/trash/{id} [delete]
+func (h *ProductHandler) DeletePermanently(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.DeletePermanently(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
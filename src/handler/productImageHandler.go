@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductImageHandler struct {
+	uCase models.ProductImageUsecase
+}
+
+func NewProductImageHandler(r fiber.Router, uc models.ProductImageUsecase) {
+	handler := &ProductImageHandler{
+		uCase: uc,
+	}
+
+	images := r.Group("/products/:id/images", middleware.JWTAuthMiddleware())
+
+	images.Get("", handler.ListImage)
+	images.Post("", handler.AddImage)
+	images.Put("/order", handler.Reorder)
+	images.Put("/:image_id", handler.UpdateImage)
+	images.Delete("/:image_id", handler.DeleteImage)
+}
+
+// ListImage
+// @Summary      Product Gallery
+// @Description  List of product's images in the display order
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Success      200  {array}   models.ProductImage
+// @Failure      404  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/images [get]
+func (h *ProductImageHandler) ListImage(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	data, err := h.uCase.ListImage(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
+
+// AddImage
+// @Summary      Add Images
+// @Description  Upload one or more images to the end of the gallery
+// @Tags         Products
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param 		 body formData models.ProductImageInput false "Body"
+// @Param 		 image formData file true "Images to upload" format(multipart/form-data)
+// @Success      201  {array}   models.ProductImage
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/images [post]
+func (h *ProductImageHandler) AddImage(c *fiber.Ctx) error {
+	var payload models.ProductImageInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	data, err := h.uCase.AddImage(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
+
+// UpdateImage
+// @Summary      Update Image
+// @Description  Change the alt text or make the image the cover
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param        image_id   path      int  true  "Image ID"
+// @Param 		 body body models.ProductImageInput true "Body"
+// @Success      200  {object}  models.ProductImage
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/images/{image_id} [put]
+func (h *ProductImageHandler) UpdateImage(c *fiber.Ctx) error {
+	var payload models.ProductImageInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.UpdateImage(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Reorder
+// @Summary      Reorder Images
+// @Description  Change the order of the gallery, image_ids must contain all images of the product
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param 		 body body models.ProductImageOrderInput true "Body"
+// @Success      200  {array}   models.ProductImage
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/images/order [put]
+func (h *ProductImageHandler) Reorder(c *fiber.Ctx) error {
+	var payload models.ProductImageOrderInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	data, err := h.uCase.Reorder(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
+
+// DeleteImage
+// @Summary      Delete Image
+// @Description  Delete the image and its file, the next image become the cover
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param        image_id   path      int  true  "Image ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/images/{image_id} [delete]
+func (h *ProductImageHandler) DeleteImage(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.DeleteImage(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
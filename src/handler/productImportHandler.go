@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"fmt"
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductImportHandler struct {
+	uCase models.ProductImportUsecase
+}
+
+func NewProductImportHandler(r fiber.Router, uc models.ProductImportUsecase) {
+	handler := &ProductImportHandler{
+		uCase: uc,
+	}
+
+	// registered before the product routes, /products/:id would match them
+	products := r.Group("/products")
+
+	products.Get("/export", middleware.JWTAuthMiddleware(), handler.Export)
+	products.Post("/import", middleware.JWTAuthMiddleware(), handler.Import)
+	products.Get("/imports", middleware.JWTAuthMiddleware(), handler.ListImport)
+	products.Get("/imports/:import_id", middleware.JWTAuthMiddleware(), handler.GetImport)
+	products.Get("/imports/:import_id/report", middleware.JWTAuthMiddleware(), handler.Report)
+}
+
+// Import
+// @Summary      Import Products
+// @Description  Upsert my products from a CSV or XLSX file (max 4 MB, 10000 rows) by external_id in background. Columns: external_id, title, price (required), description, currency, category_id, tags (comma separated). The new products are drafts
+// @Tags         Products
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param 		 file formData file true "CSV or XLSX file" format(multipart/form-data)
+// @Success      202  {object}  models.ProductImport
+// @Failure      422  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/import [post]
+func (h *ProductImportHandler) Import(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusAccepted,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.Import(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// ListImport
+// @Summary      List Product Import
+// @Description  My last 50 imports in the active workspace
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   models.ProductImport
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/imports [get]
+func (h *ProductImportHandler) ListImport(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	data, err := h.uCase.ListImport(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
+
+// GetImport
+// @Summary      Get Product Import
+// @Description  The progress of the import, processed_rows of total_rows
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        import_id   path      int  true  "Import ID"
+// @Success      200  {object}  models.ProductImport
+// @Failure      404  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/imports/{import_id} [get]
+func (h *ProductImportHandler) GetImport(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.GetImport(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// Report
+// @Summary      Download Import Errors
+// @Description  The rejected rows with their errors, in the format of the imported file
+// @Tags         Products
+// @Produce      text/csv
+// @Param        import_id   path      int  true  "Import ID"
+// @Success      200  {file}    file
+// @Failure      404  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/imports/{import_id}/report [get]
+func (h *ProductImportHandler) Report(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.Report(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Download(obj.ReportPath, fmt.Sprintf("import-%d-errors.%s", obj.ID, obj.Format))
+}
+
+// Export
+// @Summary      Export Products
+// @Description  Stream my products of the active workspace in the import format
+// @Tags         Products
+// @Produce      text/csv
+// @Param        format  query     string  false  "csv (default) or xlsx"
+// @Success      200  {file}    file
+// @Failure      400  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/export [get]
+func (h *ProductImportHandler) Export(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.Export(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return nil
+}
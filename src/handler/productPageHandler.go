@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"myapp/pkg/configs"
+	"myapp/src/models"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// the length of the meta description, the longer description is cut at a word
+const pageDescriptionLength = 160
+
+type ProductPageHandler struct {
+	uCase models.ProductUsecase
+}
+
+// NewProductPageHandler serve the server-rendered product page for the link previews and the search engines
+func NewProductPageHandler(r *fiber.App, uc models.ProductUsecase) {
+	handler := &ProductPageHandler{
+		uCase: uc,
+	}
+
+	r.Get("/products/:slug", handler.ProductPage)
+}
+
+// productPage is the data of the templates/products/show.html
+type productPage struct {
+	SiteData    configs.SiteData
+	Product     models.Product
+	Title       string
+	Description string
+	URL         string
+	Image       string
+	Images      []string
+	Price       string
+	InStock     bool
+	// the schema.org Product, the JSON encoder escape the HTML characters
+	JSONLD template.JS
+}
+
+// ProductPage render the live product of the public catalog, the previous slug redirect to the current one
+func (h *ProductPageHandler) ProductPage(c *fiber.Ctx) error {
+	obj, redirect, err := h.uCase.PublicBySlug(c.Params("slug"))
+	if err != nil {
+		return err
+	}
+
+	if redirect != "" {
+		return c.Redirect("/products/"+redirect, fiber.StatusMovedPermanently)
+	}
+
+	siteData, _ := configs.GetSiteData(".")
+	page, errPage := newProductPage(siteData, obj)
+	if errPage != nil {
+		return errPage
+	}
+
+	return c.Render("products/show", page)
+}
+
+func newProductPage(siteData configs.SiteData, obj models.Product) (productPage, error) {
+	page := productPage{
+		SiteData:    siteData,
+		Product:     obj,
+		Title:       obj.Title,
+		Description: pageDescription(obj.Description),
+		URL:         fmt.Sprintf("%s/products/%s", siteData.ClientOrigin, obj.Slug),
+		Price:       obj.Price.Format(),
+		InStock:     len(obj.Variants) == 0,
+	}
+
+	// the cover first, then the gallery
+	if obj.Image != nil && *obj.Image != "" {
+		image := *obj.Image
+		if !strings.HasPrefix(image, "http") {
+			image = fmt.Sprintf("%s/%s", siteData.ClientOrigin, image)
+		}
+		page.Images = append(page.Images, image)
+	}
+	for _, image := range obj.Images {
+		if url := image.AbsoluteURL(); !image.IsCover || len(page.Images) == 0 {
+			page.Images = append(page.Images, url)
+		}
+	}
+	if len(page.Images) > 0 {
+		page.Image = page.Images[0]
+	}
+
+	// the product with variants is in stock when a variant is
+	for _, variant := range obj.Variants {
+		if variant.IsEnable && variant.Stock > 0 {
+			page.InStock = true
+		}
+	}
+
+	availability := "https://schema.org/OutOfStock"
+	if page.InStock {
+		availability = "https://schema.org/InStock"
+	}
+	product := fiber.Map{
+		"@context":    "https://schema.org",
+		"@type":       "Product",
+		"name":        obj.Title,
+		"description": page.Description,
+		"url":         page.URL,
+		"offers": fiber.Map{
+			"@type":         "Offer",
+			"url":           page.URL,
+			"price":         obj.Price.String(),
+			"priceCurrency": obj.Price.Currency,
+			"availability":  availability,
+		},
+	}
+	if len(page.Images) > 0 {
+		product["image"] = page.Images
+	}
+	if obj.ExternalID != nil {
+		product["sku"] = *obj.ExternalID
+	}
+	if obj.Category != nil {
+		product["category"] = obj.Category.Name
+	}
+	if obj.RatingCount > 0 {
+		product["aggregateRating"] = fiber.Map{
+			"@type":       "AggregateRating",
+			"ratingValue": obj.RatingAverage,
+			"reviewCount": obj.RatingCount,
+		}
+	}
+
+	data, err := json.Marshal(product)
+	if err != nil {
+		return page, err
+	}
+	page.JSONLD = template.JS(data)
+
+	return page, nil
+}
+
+// pageDescription is the description on one line, cut at a word
+func pageDescription(description string) string {
+	description = strings.Join(strings.Fields(description), " ")
+	if utf8.RuneCountInString(description) <= pageDescriptionLength {
+		return description
+	}
+
+	cut := string([]rune(description)[:pageDescriptionLength])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
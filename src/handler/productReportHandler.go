@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductReportHandler struct {
+	uCase models.ProductReportUsecase
+}
+
+func NewProductReportHandler(r fiber.Router, uc models.ProductReportUsecase) {
+	handler := &ProductReportHandler{
+		uCase: uc,
+	}
+
+	r.Post("/products/:id/reports", middleware.JWTAuthMiddleware(), handler.Report)
+}
+
+// Report
+// @Summary      Report Product
+// @Description  Report the product to the moderators, once per product until the report is resolved. The details are required by the other reason
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param 		 body body models.ProductReportInput true "Body"
+// @Success      201  {object}  models.ProductReport
+// @Failure      404  {object}  models.ResponseError
+// @Failure      409  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/reports [post]
+func (h *ProductReportHandler) Report(c *fiber.Ctx) error {
+	var payload models.ProductReportInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+	payload.Sanitize()
+
+	// form POST validations
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Report(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
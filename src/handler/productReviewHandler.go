@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductReviewHandler struct {
+	uCase models.ProductReviewUsecase
+}
+
+func NewProductReviewHandler(r fiber.Router, uc models.ProductReviewUsecase) {
+	handler := &ProductReviewHandler{
+		uCase: uc,
+	}
+
+	reviews := r.Group("/products/:id/reviews", middleware.JWTAuthMiddleware())
+
+	reviews.Get("", handler.ListReview)
+	reviews.Post("", handler.CreateReview)
+	reviews.Put("/:review_id", handler.UpdateReview)
+	reviews.Delete("/:review_id", handler.DeleteReview)
+}
+
+// ListReview
+// @Summary      List of Review
+// @Description  The approved reviews of the product, and the own review whatever its status. filter[rating][gte]=4&sort=-rating
+// @Tags         Reviews
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Success      200  {object}  response.Pagination
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/reviews [get]
+func (h *ProductReviewHandler) ListReview(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	pagination, err := h.uCase.ListReview(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(&pagination)
+}
+
+// CreateReview
+// @Summary      Create Review
+// @Description  Rate the product from 1 to 5 stars, once per product. The seller can't review its own product
+// @Tags         Reviews
+// @Accept       json
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param 		 body formData models.ProductReviewInput true "Body"
+// @Param 		 photo formData file false "Photo of the review" format(multipart/form-data)
+// @Success      201  {object}  models.ProductReview
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      409  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/reviews [post]
+func (h *ProductReviewHandler) CreateReview(c *fiber.Ctx) error {
+	var payload models.ProductReviewInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.CreateReview(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// UpdateReview
+// @Summary      Update Review
+// @Description  Update the own review, a new photo replace the current one
+// @Tags         Reviews
+// @Accept       json
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param        review_id   path      int  true  "Review ID"
+// @Param 		 body formData models.ProductReviewInput true "Body"
+// @Param 		 photo formData file false "Photo of the review" format(multipart/form-data)
+// @Success      200  {object}  models.ProductReview
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/reviews/{review_id} [put]
+func (h *ProductReviewHandler) UpdateReview(c *fiber.Ctx) error {
+	var payload models.ProductReviewInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.UpdateReview(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// DeleteReview
+// @Summary      Delete Review
+// @Description  Delete the own review
+// @Tags         Reviews
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param        review_id   path      int  true  "Review ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/reviews/{review_id} [delete]
+func (h *ProductReviewHandler) DeleteReview(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.DeleteReview(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
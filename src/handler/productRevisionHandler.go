@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductRevisionHandler struct {
+	uCase models.ProductRevisionUsecase
+}
+
+func NewProductRevisionHandler(r fiber.Router, uc models.ProductRevisionUsecase) {
+	handler := &ProductRevisionHandler{
+		uCase: uc,
+	}
+
+	products := r.Group("/products/:id", middleware.JWTAuthMiddleware())
+
+	products.Get("/history", handler.History)
+	products.Post("/revert/:revision", handler.Revert)
+}
+
+// History
+// @Summary      History of Product
+// @Description  The revisions of the product with the changed fields, the actor and the time. filter[action]=update&sort=-id
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Success      200  {object}  response.Pagination
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/history [get]
+func (h *ProductRevisionHandler) History(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	pagination, err := h.uCase.History(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(&pagination)
+}
+
+// Revert
+// @Summary      Revert Product
+// @Description  Restore the fields of the product as they were after the revision, the revert is recorded as a new revision
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int  true  "Product ID"
+// @Param        revision   path      int  true  "Revision ID"
+// @Success      200  {object}  models.Product
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      409  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/revert/{revision} [post]
+func (h *ProductRevisionHandler) Revert(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.Revert(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductStatHandler struct {
+	uCase models.ProductStatUsecase
+}
+
+func NewProductStatHandler(r fiber.Router, uc models.ProductStatUsecase) {
+	handler := &ProductStatHandler{
+		uCase: uc,
+	}
+
+	products := r.Group("/products/:id", middleware.JWTAuthMiddleware())
+
+	products.Get("/stats", handler.Stats)
+}
+
+// Stats
+// @Summary      Stats of Product
+// @Description  The daily views, search impressions and favorites of the product, the days are UTC. The counts are flushed every few minutes
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int     true   "Product ID"
+// @Param        from   query     string  false  "First day, YYYY-MM-DD, 29 days before to by default"
+// @Param        to     query     string  false  "Last day, YYYY-MM-DD, today by default"
+// @Success      200  {object}  models.ProductStats
+// @Failure      400  {object}  models.ResponseError
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/stats [get]
+func (h *ProductStatHandler) Stats(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.Stats(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
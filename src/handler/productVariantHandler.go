@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductVariantHandler struct {
+	uCase models.ProductVariantUsecase
+}
+
+func NewProductVariantHandler(r fiber.Router, uc models.ProductVariantUsecase) {
+	handler := &ProductVariantHandler{
+		uCase: uc,
+	}
+
+	variants := r.Group("/products/:id/variants", middleware.JWTAuthMiddleware())
+
+	variants.Get("", handler.ListVariant)
+	variants.Post("", handler.CreateVariant)
+	variants.Get("/:variant_id", handler.GetVariant)
+	variants.Put("/:variant_id", handler.UpdateVariant)
+	variants.Delete("/:variant_id", handler.DeleteVariant)
+
+	// stock
+	variants.Get("/:variant_id/stock", handler.ListStockMovement)
+	variants.Post("/:variant_id/stock", handler.AdjustStock)
+}
+
+// ListVariant
+// @Summary      List of Variant
+// @Description  List of product's variants
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Success      200  {array}   models.ProductVariant
+// @Failure      404  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/variants [get]
+func (h *ProductVariantHandler) ListVariant(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	data, err := h.uCase.ListVariant(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
+
+// GetVariant
+// @Summary      Get Variant
+// @Description  Get variant's data
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param        variant_id   path      int  true  "Variant ID"
+// @Success      200  {object}  models.ProductVariant
+// @Failure      404  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/variants/{variant_id} [get]
+func (h *ProductVariantHandler) GetVariant(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.GetVariant(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// CreateVariant
+// @Summary      Create Variant
+// @Description  Create a variant with its SKU, options, price override and initial stock
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param 		 body body models.ProductVariantInput true "Body"
+// @Success      201  {object}  models.ProductVariant
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/variants [post]
+func (h *ProductVariantHandler) CreateVariant(c *fiber.Ctx) error {
+	var payload models.ProductVariantInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Create(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// UpdateVariant
+// @Summary      Update Variant
+// @Description  Update variant's data, the stock is changed with the stock adjustment
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param        variant_id   path      int  true  "Variant ID"
+// @Param 		 body body models.ProductVariantInput true "Body"
+// @Success      200  {object}  models.ProductVariant
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/variants/{variant_id} [put]
+func (h *ProductVariantHandler) UpdateVariant(c *fiber.Ctx) error {
+	var payload models.ProductVariantInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Update(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// DeleteVariant
+// @Summary      Delete Variant
+// @Description  Delete variant's data
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param        variant_id   path      int  true  "Variant ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/variants/{variant_id} [delete]
+func (h *ProductVariantHandler) DeleteVariant(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.Delete(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
+
+// AdjustStock
+// @Summary      Adjust Stock
+// @Description  Add or remove the stock of variant, the adjustment is recorded in the history
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param        variant_id   path      int  true  "Variant ID"
+// @Param 		 body body models.StockAdjustmentInput true "Body"
+// @Success      201  {object}  models.StockMovement
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/variants/{variant_id}/stock [post]
+func (h *ProductVariantHandler) AdjustStock(c *fiber.Ctx) error {
+	var payload models.StockAdjustmentInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.AdjustStock(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// ListStockMovement
+// @Summary      Stock History
+// @Description  History of the stock adjustments of variant
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Param        variant_id   path      int  true  "Variant ID"
+// @Success      200  {object}  response.Pagination
+// @Failure      403  {object}  models.ResponseError
+// @Failure      404  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/variants/{variant_id}/stock [get]
+func (h *ProductVariantHandler) ListStockMovement(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	pagination, err := h.uCase.ListStockMovement(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(&pagination)
+}
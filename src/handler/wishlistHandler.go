@@ -0,0 +1,354 @@
+package handler
+
+import (
+	"myapp/pkg/middleware"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WishlistHandler struct {
+	uCase models.WishlistUsecase
+}
+
+func NewWishlistHandler(r fiber.Router, uc models.WishlistUsecase) {
+	handler := &WishlistHandler{
+		uCase: uc,
+	}
+
+	// the public link don't need the login
+	r.Get("/wishlists/shared/:token", handler.GetShared)
+
+	wishlists := r.Group("/wishlists", middleware.JWTAuthMiddleware())
+
+	wishlists.Get("", handler.ListWishlist)
+	wishlists.Post("", handler.CreateWishlist)
+	wishlists.Get("/:id", handler.GetWishlist)
+	wishlists.Put("/:id", handler.UpdateWishlist)
+	wishlists.Delete("/:id", handler.DeleteWishlist)
+	wishlists.Post("/:id/items", handler.AddItem)
+	wishlists.Delete("/:id/items/:product_id", handler.RemoveItem)
+
+	// the favorites are the default wishlist
+	r.Post("/products/:id/favorite", middleware.JWTAuthMiddleware(), handler.Favorite)
+	r.Delete("/products/:id/favorite", middleware.JWTAuthMiddleware(), handler.Unfavorite)
+}
+
+// ListWishlist
+// @Summary      List of Wishlist
+// @Description  My wishlists with the number of products, the favorites first
+// @Tags         Wishlists
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   models.Wishlist
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/wishlists [get]
+func (h *WishlistHandler) ListWishlist(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	data, err := h.uCase.ListWishlist(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(data)
+}
+
+// GetWishlist
+// @Summary      Get Wishlist
+// @Description  Get my wishlist with its products
+// @Tags         Wishlists
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Wishlist ID"
+// @Success      200  {object}  models.Wishlist
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/wishlists/{id} [get]
+func (h *WishlistHandler) GetWishlist(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.GetWishlist(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// GetShared
+// @Summary      Shared Wishlist
+// @Description  Get the public wishlist by its link, only the live products are listed
+// @Tags         Wishlists
+// @Accept       json
+// @Produce      json
+// @Param        token   path      string  true  "Share token"
+// @Success      200  {object}  models.Wishlist
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Router       /v1/wishlists/shared/{token} [get]
+func (h *WishlistHandler) GetShared(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	obj, err := h.uCase.GetShared(c)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// CreateWishlist
+// @Summary      Create Wishlist
+// @Description  Create a named wishlist, is_public create the public link
+// @Tags         Wishlists
+// @Accept       json
+// @Produce      json
+// @Param 		 body body models.WishlistInput true "Body"
+// @Success      201  {object}  models.Wishlist
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/wishlists [post]
+func (h *WishlistHandler) CreateWishlist(c *fiber.Ctx) error {
+	var payload models.WishlistInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusCreated,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Create(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// UpdateWishlist
+// @Summary      Update Wishlist
+// @Description  Rename the wishlist, is_public false revoke the public link
+// @Tags         Wishlists
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Wishlist ID"
+// @Param 		 body body models.WishlistInput true "Body"
+// @Success      200  {object}  models.Wishlist
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/wishlists/{id} [put]
+func (h *WishlistHandler) UpdateWishlist(c *fiber.Ctx) error {
+	var payload models.WishlistInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.Update(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// DeleteWishlist
+// @Summary      Delete Wishlist
+// @Description  Delete the wishlist, the favorites can't be deleted
+// @Tags         Wishlists
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Wishlist ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/wishlists/{id} [delete]
+func (h *WishlistHandler) DeleteWishlist(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.Delete(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
+
+// AddItem
+// @Summary      Add to Wishlist
+// @Description  Save the product in the wishlist, the saved product is kept as is
+// @Tags         Wishlists
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Wishlist ID"
+// @Param 		 body body models.WishlistItemInput true "Body"
+// @Success      200  {object}  models.Wishlist
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseHTTP
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/wishlists/{id}/items [post]
+func (h *WishlistHandler) AddItem(c *fiber.Ctx) error {
+	var payload models.WishlistItemInput
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := c.BodyParser(&payload); err != nil {
+		res.Code = fiber.StatusBadRequest
+		res.Message = err.Error()
+		return c.Status(res.Code).JSON(res)
+	}
+
+	// form POST validation
+	errD := models.ValidateStruct(payload)
+	if errD.Errors != nil {
+		return c.Status(errD.Code).JSON(errD)
+	}
+
+	obj, err := h.uCase.AddItem(c, payload)
+	if err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(obj)
+}
+
+// RemoveItem
+// @Summary      Remove from Wishlist
+// @Description  Remove the product from the wishlist
+// @Tags         Wishlists
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Wishlist ID"
+// @Param        product_id   path      int  true  "Product ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/wishlists/{id}/items/{product_id} [delete]
+func (h *WishlistHandler) RemoveItem(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.RemoveItem(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
+
+// Favorite
+// @Summary      Favorite Product
+// @Description  Add the product to my favorites
+// @Tags         Wishlists
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      404  {object}  models.ResponseError
+// @Failure      422  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/favorite [post]
+func (h *WishlistHandler) Favorite(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.Favorite(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
+
+// Unfavorite
+// @Summary      Unfavorite Product
+// @Description  Remove the product from my favorites
+// @Tags         Wishlists
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Success      200  {object}  models.ResponseSuccess
+// @Failure      404  {object}  models.ResponseError
+// @Failure      500  {object}  models.ResponseError
+// @Security 	 BearerAuth
+// @Router       /v1/products/{id}/favorite [delete]
+func (h *WishlistHandler) Unfavorite(c *fiber.Ctx) error {
+	res := models.ResponseHTTP{
+		Code:    fiber.StatusOK,
+		Message: "Request has been processed successfully",
+	}
+
+	if err := h.uCase.Unfavorite(c); err != nil {
+		res.Code = err.Code
+		res.Message = err.Message
+		return c.Status(res.Code).JSON(res)
+	}
+
+	return c.Status(res.Code).JSON(res)
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DeletionStatus string
+
+const (
+	DeletionPending   DeletionStatus = "pending"
+	DeletionCancelled DeletionStatus = "cancelled"
+	DeletionPurged    DeletionStatus = "purged"
+)
+
+// AccountDeletion track the account deletion request during the grace period
+type AccountDeletion struct {
+	gorm.Model
+	Email       string         `json:"email"`
+	Code        string         `json:"-" gorm:"index"`
+	Status      DeletionStatus `json:"status" gorm:"size:20;default:pending;index"`
+	ScheduledAt time.Time      `json:"scheduled_at" gorm:"index"`
+	CancelledAt *time.Time     `json:"cancelled_at"`
+	PurgedAt    *time.Time     `json:"purged_at"`
+	// foreignkey User, keep the record after the user is purged
+	UserID *uint `json:"user_id"`
+	User   *User `gorm:"foreignkey:UserID;constraint:OnDelete:SET NULL;" json:"user,omitempty"`
+}
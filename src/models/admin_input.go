@@ -0,0 +1,57 @@
+package models
+
+import "strings"
+
+// user status ID, see the initial status in configs.MigrateDB
+const (
+	StatusActive    uint = 1
+	StatusInactive  uint = 2
+	StatusPending   uint = 3
+	StatusSuspended uint = 4
+)
+
+type AdminCreateUserInput struct {
+	Username  string `json:"username" validate:"required,gte=4"`
+	Password  string `json:"password" validate:"required,gte=4"`
+	FirstName string `json:"first_name" validate:"required"`
+	LastName  string `json:"last_name" validate:"required"`
+	Email     string `json:"email" validate:"required,email,gte=4"`
+	Phone     string `json:"phone"`
+	IsStaff   bool   `json:"is_staff"`
+	Verified  bool   `json:"verified"`
+	StatusID  uint   `json:"status_id" validate:"omitempty,oneof=1 2 3 4"`
+}
+
+func (f *AdminCreateUserInput) Sanitize() {
+	f.Username = strings.TrimSpace(f.Username)
+	f.FirstName = strings.TrimSpace(f.FirstName)
+	f.LastName = strings.TrimSpace(f.LastName)
+	f.Email = strings.ToLower(strings.TrimSpace(f.Email))
+	f.Password = strings.TrimSpace(f.Password)
+	f.Phone = strings.TrimSpace(f.Phone)
+}
+
+type AdminUpdateUserInput struct {
+	Username  string `json:"username" validate:"required,gte=4"`
+	FirstName string `json:"first_name" validate:"required"`
+	LastName  string `json:"last_name" validate:"required"`
+	Email     string `json:"email" validate:"required,email,gte=4"`
+	Phone     string `json:"phone"`
+	Birthday  string `json:"birthday"`
+}
+
+func (f *AdminUpdateUserInput) Sanitize() {
+	f.Username = strings.TrimSpace(f.Username)
+	f.FirstName = strings.TrimSpace(f.FirstName)
+	f.LastName = strings.TrimSpace(f.LastName)
+	f.Email = strings.ToLower(strings.TrimSpace(f.Email))
+	f.Phone = strings.TrimSpace(f.Phone)
+}
+
+type AdminUserStatusInput struct {
+	StatusID uint `json:"status_id" validate:"required,oneof=1 2 3 4"`
+}
+
+type AdminUserStaffInput struct {
+	IsStaff bool `json:"is_staff"`
+}
@@ -10,6 +10,7 @@ type RegisterInput struct {
 	LastName        string `json:"last_name" validate:"required"`
 	Email           string `json:"email" validate:"required,email,gte=4"`
 	Phone           string `json:"phone" validate:"required"`
+	InvitationCode  string `json:"invitation_code"`
 }
 
 func (f *RegisterInput) Sanitize() {
@@ -21,6 +22,7 @@ func (f *RegisterInput) Sanitize() {
 	f.Password = strings.TrimSpace(f.Password)
 	f.PasswordConfirm = strings.TrimSpace(f.PasswordConfirm)
 	f.Phone = strings.TrimSpace(f.Phone)
+	f.InvitationCode = strings.TrimSpace(f.InvitationCode)
 }
 
 type LoginInput struct {
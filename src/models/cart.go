@@ -0,0 +1,81 @@
+package models
+
+import (
+	"myapp/pkg/money"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// the maximum quantity of a cart item and the number of items in the cart
+const (
+	MaxCartQuantity = 99
+	MaxCartItems    = 50
+)
+
+// CartItem is a product in the cart of the user, the product with variants need the variant
+type CartItem struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Quantity  int       `json:"quantity" gorm:"not null"`
+	// foreignkey User, the buyer
+	UserID uint  `json:"-" gorm:"index"`
+	User   *User `gorm:"foreignkey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+	// foreignkey Product
+	ProductID uint     `json:"product_id" gorm:"index"`
+	Product   *Product `gorm:"foreignkey:ProductID;constraint:OnDelete:CASCADE;" json:"product,omitempty"`
+	// foreignkey ProductVariant, null for the product without variants
+	ProductVariantID *uint           `json:"variant_id"`
+	ProductVariant   *ProductVariant `gorm:"foreignkey:ProductVariantID;constraint:OnDelete:CASCADE;" json:"variant,omitempty"`
+	// filled by the cart, the current price of the item
+	UnitPrice *money.Money `gorm:"-" json:"unit_price,omitempty"`
+	Subtotal  *money.Money `gorm:"-" json:"subtotal,omitempty"`
+	// filled by the cart, the reason the item can't be bought now
+	Problem string `gorm:"-" json:"problem,omitempty"`
+}
+
+// Cart is the items of the user with the current prices
+type Cart struct {
+	Items []*CartItem `json:"items"`
+	// nil when the items have different currencies
+	Total *money.Money `json:"total"`
+	// all items can be bought now
+	IsValid bool `json:"is_valid"`
+}
+
+type CartItemInput struct {
+	ProductID uint  `json:"product_id" validate:"required"`
+	VariantID *uint `json:"variant_id"`
+	Quantity  int   `json:"quantity" validate:"required,min=1,max=99"`
+}
+
+type CartQuantityInput struct {
+	Quantity int `json:"quantity" validate:"required,min=1,max=99"`
+}
+
+type CheckoutInput struct {
+	ShippingAddress string `json:"shipping_address" validate:"required,max=1000"`
+	Note            string `json:"note" validate:"max=1000"`
+}
+
+type CartUsecase interface {
+	// USECASE
+	GetCart(c *fiber.Ctx) (Cart, *fiber.Error)
+	AddItem(c *fiber.Ctx, payload CartItemInput) (Cart, *fiber.Error)
+	UpdateItem(c *fiber.Ctx, payload CartQuantityInput) (Cart, *fiber.Error)
+	RemoveItem(c *fiber.Ctx) (Cart, *fiber.Error)
+	Clear(c *fiber.Ctx) *fiber.Error
+	Checkout(c *fiber.Ctx, payload CheckoutInput) ([]*Order, *fiber.Error)
+}
+
+type CartRepository interface {
+	// REPOS
+	ListItem(userID uint) ([]*CartItem, *fiber.Error)
+	GetItem(userID uint, id uint) (CartItem, *fiber.Error)
+	// add the quantity to the same product and variant, or create the item
+	AddItem(obj CartItem) (CartItem, *fiber.Error)
+	UpdateItem(obj CartItem) (CartItem, *fiber.Error)
+	RemoveItem(obj CartItem) *fiber.Error
+	Clear(userID uint) *fiber.Error
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"myapp/pkg/money"
+	"os"
+	"strings"
+	"time"
+)
+
+// PublicUser is the owner of the product in the public catalog, the private fields of User are left out
+type PublicUser struct {
+	ID       uint    `json:"id"`
+	Username string  `json:"username"`
+	Name     string  `json:"name"`
+	Photo    *string `json:"photo"`
+}
+
+// PublicVariant is the enabled variant of the product in the public catalog, without the stock
+type PublicVariant struct {
+	ID      uint              `json:"id"`
+	SKU     string            `json:"sku"`
+	Options map[string]string `json:"options"`
+	Price   money.Money       `json:"price"`
+	InStock bool              `json:"in_stock"`
+}
+
+// PublicProduct is the live product in the public catalog, only the fields safe to show to anyone
+type PublicProduct struct {
+	ID            uint              `json:"id"`
+	Slug          string            `json:"slug"`
+	Title         string            `json:"title"`
+	Description   string            `json:"description"`
+	Image         *string           `json:"image"`
+	Price         money.Money       `json:"price"`
+	RatingAverage float64           `json:"rating_average"`
+	RatingCount   int64             `json:"rating_count"`
+	FavoriteCount int64             `json:"favorite_count"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	CategoryID    *uint             `json:"category_id"`
+	Category      *Category         `json:"category,omitempty"`
+	Tags          []string          `json:"tags"`
+	Images        []*ProductImage   `json:"images"`
+	Variants      []*PublicVariant  `json:"variants,omitempty"`
+	User          PublicUser        `json:"user"`
+	Rank          *float64          `json:"rank,omitempty"`
+	Highlight     *ProductHighlight `json:"highlight,omitempty"`
+	// filled by the list with ?currency=, the price converted with the exchange rates
+	ConvertedPrice *money.Money `json:"converted_price,omitempty"`
+}
+
+// Public take the fields of the product shown in the public catalog
+func (md Product) Public() PublicProduct {
+	obj := PublicProduct{
+		ID:             md.ID,
+		Slug:           md.Slug,
+		Title:          md.Title,
+		Description:    md.Description,
+		Price:          md.Price,
+		RatingAverage:  md.RatingAverage,
+		RatingCount:    md.RatingCount,
+		FavoriteCount:  md.FavoriteCount,
+		CreatedAt:      md.CreatedAt,
+		UpdatedAt:      md.UpdatedAt,
+		CategoryID:     md.CategoryID,
+		Category:       md.Category,
+		Tags:           make([]string, 0, len(md.Tags)),
+		Images:         md.Images,
+		Rank:           md.SearchRank,
+		ConvertedPrice: md.ConvertedPrice,
+		User: PublicUser{
+			ID:       md.User.ID,
+			Username: md.User.Username,
+			Name:     strings.TrimSpace(md.User.FirstName + " " + md.User.LastName),
+			Photo:    absoluteURL(md.User.UserProfile.Photo),
+		},
+	}
+	obj.Image = absoluteURL(md.Image)
+
+	if md.TitleHighlight != "" || md.DescriptionHighlight != "" {
+		obj.Highlight = &ProductHighlight{Title: md.TitleHighlight, Description: md.DescriptionHighlight}
+	}
+
+	for _, tag := range md.Tags {
+		obj.Tags = append(obj.Tags, tag.Name)
+	}
+
+	for _, variant := range md.Variants {
+		if !variant.IsEnable {
+			continue
+		}
+		obj.Variants = append(obj.Variants, &PublicVariant{
+			ID:      variant.ID,
+			SKU:     variant.SKU,
+			Options: variant.Options,
+			Price:   variant.EffectivePrice(md),
+			InStock: variant.Stock > 0,
+		})
+	}
+
+	return obj
+}
+
+// LastModified is the latest change of the product, its images and its variants
+func (md Product) LastModified() time.Time {
+	modified := md.UpdatedAt
+	for _, image := range md.Images {
+		if image.UpdatedAt.After(modified) {
+			modified = image.UpdatedAt
+		}
+	}
+	for _, variant := range md.Variants {
+		if variant.UpdatedAt.After(modified) {
+			modified = variant.UpdatedAt
+		}
+	}
+	return modified
+}
+
+// absoluteURL return the URL of the file, the local file is served by the server
+func absoluteURL(path *string) *string {
+	if path == nil || *path == "" {
+		return nil
+	}
+	if strings.HasPrefix(*path, "http") {
+		url := *path
+		return &url
+	}
+	url := fmt.Sprintf("%s/%s", os.Getenv("CLIENT_ORIGIN"), *path)
+	return &url
+}
+
+// CatalogPage is the JSON response of a public catalog list, cached in redis
+type CatalogPage struct {
+	Body json.RawMessage `json:"body"`
+	// the products of the page, counted as impressions on each response
+	ProductIDs []uint `json:"product_ids"`
+	// the version of the catalog the page was built from, see ProductRepository.CatalogModifiedAt
+	ModifiedAt time.Time `json:"modified_at"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Category is a node of the category tree, ParentID nil is the root
+type Category struct {
+	gorm.Model
+	Name        string `json:"name" gorm:"size:100;not null"`
+	Description string `json:"description" gorm:"default:null"`
+	// foreignkey Category
+	ParentID *uint       `json:"parent_id" gorm:"index"`
+	Parent   *Category   `gorm:"foreignkey:ParentID;constraint:OnDelete:SET NULL;" json:"-"`
+	Children []*Category `gorm:"foreignkey:ParentID" json:"children,omitempty"`
+	// number of products in the category and its descendants
+	ProductCount int64 `gorm:"-" json:"product_count"`
+}
+
+// Tag is a free-form label of the products
+type Tag struct {
+	gorm.Model
+	Name string `json:"name" gorm:"size:50;not null;uniqueIndex"`
+}
+
+// ProductFilter filter the product list by the category tree and the tags
+type ProductFilter struct {
+	// the category and all of its descendants
+	CategoryIDs []uint
+	// the product must have all of the tags
+	Tags []string
+}
+
+type CategoryInput struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Description string `json:"description"`
+	ParentID    *uint  `json:"parent_id"`
+}
+
+func (f *CategoryInput) Sanitize() {
+	f.Name = strings.TrimSpace(f.Name)
+	f.Description = strings.TrimSpace(f.Description)
+}
+
+// NormalizeTags trim, lowercase and remove the duplicate tags
+func NormalizeTags(tags []string) []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		// a form value can contain comma separated tags
+		for _, name := range strings.Split(tag, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+type CategoryUsecase interface {
+	// USECASE
+	ListCategory(c *fiber.Ctx) ([]*Category, *fiber.Error)
+	GetCategory(c *fiber.Ctx) (Category, *fiber.Error)
+	ListTag(c *fiber.Ctx) ([]*Tag, *fiber.Error)
+
+	// ADMIN ROLE
+	Create(c *fiber.Ctx, payload CategoryInput) (Category, *fiber.Error)
+	Update(c *fiber.Ctx, payload CategoryInput) (Category, *fiber.Error)
+	Delete(c *fiber.Ctx) *fiber.Error
+}
+
+type CategoryRepository interface {
+	// FUNTIONS
+	CountProduct(organizationID uint) (map[uint]int64, *fiber.Error)
+	ClearCountProduct(organizationID *uint)
+
+	// REPOS
+	ListCategory() ([]*Category, *fiber.Error)
+	Get(id uint) (Category, *fiber.Error)
+	DescendantIDs(id uint) ([]uint, *fiber.Error)
+	Create(obj Category) (Category, *fiber.Error)
+	Update(obj Category) (Category, *fiber.Error)
+	Delete(obj Category) *fiber.Error
+	HasProduct(id uint) (bool, *fiber.Error)
+
+	ListTag(search string, limit int) ([]*Tag, *fiber.Error)
+	FirstOrCreateTag(names []string) ([]*Tag, *fiber.Error)
+}
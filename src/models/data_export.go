@@ -0,0 +1,74 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type ExportStatus string
+
+const (
+	ExportPending    ExportStatus = "pending"
+	ExportProcessing ExportStatus = "processing"
+	ExportReady      ExportStatus = "ready"
+	ExportFailed     ExportStatus = "failed"
+	ExportExpired    ExportStatus = "expired"
+)
+
+// DataExport is a personal data archive requested by the user
+type DataExport struct {
+	gorm.Model
+	Status    ExportStatus `json:"status" gorm:"size:20;default:pending;index"`
+	FilePath  string       `json:"-"`
+	Size      int64        `json:"size"`
+	Code      string       `json:"-" gorm:"index"`
+	Error     string       `json:"error,omitempty"`
+	ExpiredAt *time.Time   `json:"expired_at"`
+	// foreignkey User
+	UserID uint `json:"user_id"`
+	User   User `gorm:"foreignkey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+// ExportActivity is the login and security history of the user
+type ExportActivity struct {
+	LastLoginAt *time.Time         `json:"last_login_at"`
+	LastLoginIp string             `json:"last_login_ip"`
+	VerifiedAt  *time.Time         `json:"verified_at"`
+	Logins      []*LoginHistory    `json:"logins"`
+	Passkeys    []*Passkey         `json:"passkeys"`
+	Invitations []*Invitation      `json:"invitations"`
+	Deletions   []*AccountDeletion `json:"account_deletions"`
+}
+
+// ExportData is all the data that belongs to the user
+type ExportData struct {
+	User     User           `json:"user"`
+	Products []*Product     `json:"products"`
+	Drives   []*MyDrive     `json:"drives"`
+	Activity ExportActivity `json:"activity"`
+}
+
+type DataExportUsecase interface {
+	// USECASE
+	ListExport(c *fiber.Ctx) ([]*DataExport, *fiber.Error)
+	RequestExport(c *fiber.Ctx) (DataExport, *fiber.Error)
+	Download(ctx context.Context, code string) (DataExport, *fiber.Error)
+	PurgeExpiredExport() error
+}
+
+type DataExportRepository interface {
+	// FUNTIONS
+	SendExportEmail(user User, obj DataExport, code string) error
+
+	// REPOS
+	ListExport(userID uint) ([]*DataExport, *fiber.Error)
+	FindActive(userID uint) (DataExport, *fiber.Error)
+	FindByCode(code string) (DataExport, *fiber.Error)
+	ListExpired(now time.Time) ([]*DataExport, *fiber.Error)
+	Create(obj DataExport) (DataExport, *fiber.Error)
+	Update(obj DataExport) (DataExport, *fiber.Error)
+	CollectData(userID uint) (ExportData, *fiber.Error)
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"errors"
+	"math/big"
+	"myapp/pkg/money"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ExchangeRate is the units of the currency for one unit of the base currency (DEFAULT_CURRENCY)
+type ExchangeRate struct {
+	gorm.Model
+	Currency string `json:"currency" gorm:"size:3;not null;uniqueIndex:idx_exchange_rate_currency,where:deleted_at IS NULL"`
+	// exact decimal, e.g. 0.92 EUR for 1 USD
+	Rate string `json:"rate" gorm:"type:numeric(20,10);not null"`
+}
+
+type ExchangeRateInput struct {
+	// decimal string, e.g. "0.92"
+	Rate string `json:"rate" validate:"required,max=32"`
+}
+
+// Rates convert the money between the currencies through the base currency
+type Rates map[string]*big.Rat
+
+// NewRates parse the exchange rates, the base currency is 1
+func NewRates(base string, rates []*ExchangeRate) Rates {
+	result := Rates{base: big.NewRat(1, 1)}
+	for _, rate := range rates {
+		if value, err := money.ParseRate(rate.Rate); err == nil {
+			result[rate.Currency] = value
+		}
+	}
+	return result
+}
+
+// Convert return the amount in the currency
+func (r Rates) Convert(amount money.Money, currency string) (money.Money, error) {
+	currency = strings.ToUpper(currency)
+	from, okFrom := r[amount.Currency]
+	to, okTo := r[currency]
+	if !okFrom || !okTo {
+		return amount, errors.New("no exchange rate between " + amount.Currency + " and " + currency)
+	}
+
+	return amount.Convert(currency, new(big.Rat).Quo(to, from))
+}
+
+type ExchangeRateUsecase interface {
+	// USECASE
+	ListRate(c *fiber.Ctx) ([]*ExchangeRate, *fiber.Error)
+
+	// ADMIN ROLE
+	Set(c *fiber.Ctx, payload ExchangeRateInput) (ExchangeRate, *fiber.Error)
+	Delete(c *fiber.Ctx) *fiber.Error
+}
+
+type ExchangeRateRepository interface {
+	// REPOS
+	ListRate() ([]*ExchangeRate, *fiber.Error)
+	Get(currency string) (ExchangeRate, *fiber.Error)
+	Save(obj ExchangeRate) (ExchangeRate, *fiber.Error)
+	Delete(obj ExchangeRate) *fiber.Error
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"encoding/json"
+	"myapp/pkg/response"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type RegistrationMode string
+
+const (
+	RegistrationOpen   RegistrationMode = "open"
+	RegistrationInvite RegistrationMode = "invite"
+	RegistrationClosed RegistrationMode = "closed"
+)
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationExpired  InvitationStatus = "expired"
+	InvitationRevoked  InvitationStatus = "revoked"
+)
+
+const (
+	InvitationRoleUser  = "user"
+	InvitationRoleStaff = "staff"
+)
+
+// Invitation allow someone to register when registration mode is invite-only
+type Invitation struct {
+	gorm.Model
+	Email     string           `json:"email" gorm:"not null;index"`
+	Code      string           `json:"-" gorm:"not null;uniqueIndex"`
+	Role      string           `json:"role" gorm:"size:100;default:user"`
+	Status    InvitationStatus `json:"status" gorm:"size:20;default:pending"`
+	MaxUses   int              `json:"max_uses" gorm:"default:1"`
+	UsedCount int              `json:"used_count" gorm:"default:0"`
+	ExpiredAt time.Time        `json:"expired_at"`
+	// foreignkey User
+	InvitedByID uint `json:"invited_by_id"`
+	InvitedBy   User `gorm:"foreignkey:InvitedByID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+// CurrentStatus return the status, a pending invitation after its expiry time is expired
+func (md Invitation) CurrentStatus() InvitationStatus {
+	if md.Status == InvitationPending && time.Now().After(md.ExpiredAt) {
+		return InvitationExpired
+	}
+	return md.Status
+}
+
+// Usable check the invitation is still able to be used to register
+func (md Invitation) Usable() bool {
+	return md.CurrentStatus() == InvitationPending && md.UsedCount < md.MaxUses
+}
+
+// MatchEmail check the registered email is the invited email
+func (md Invitation) MatchEmail(email string) bool {
+	return strings.EqualFold(strings.TrimSpace(md.Email), strings.TrimSpace(email))
+}
+
+func (md Invitation) MarshalJSON() ([]byte, error) {
+	type Alias Invitation
+	aux := struct {
+		Alias
+		Status InvitationStatus `json:"status"`
+	}{
+		Alias:  (Alias)(md),
+		Status: md.CurrentStatus(),
+	}
+	return json.Marshal(aux)
+}
+
+type InvitationInput struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"omitempty,oneof=user staff"`
+	// expiry in hours, default 72 hours
+	ExpiresIn int `json:"expires_in" validate:"omitempty,min=1,max=720"`
+	MaxUses   int `json:"max_uses" validate:"omitempty,min=1,max=100"`
+}
+
+type InvitationUsecase interface {
+	// USECASE
+	ListInvitation(c *fiber.Ctx, all bool) (*response.Pagination, *fiber.Error)
+	Create(c *fiber.Ctx, payload InvitationInput, asAdmin bool) (Invitation, *fiber.Error)
+	Revoke(c *fiber.Ctx, asAdmin bool) *fiber.Error
+	CheckInvitation(c *fiber.Ctx, code string) (Invitation, *fiber.Error)
+}
+
+type InvitationRepository interface {
+	// FUNTIONS
+	SendInvitationEmail(obj Invitation, code string) error
+
+	// REPOS
+	ListInvitation(invitedByID uint, param response.ParamsPagination) (*response.Pagination, *fiber.Error)
+	Get(id uint) (Invitation, *fiber.Error)
+	FindByCode(code string) (Invitation, *fiber.Error)
+	Create(obj Invitation) (Invitation, *fiber.Error)
+	Update(obj Invitation) (Invitation, *fiber.Error)
+	MarkUsed(obj Invitation) *fiber.Error
+}
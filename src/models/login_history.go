@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// LoginHistory is a session of the user, saved with the last login at most once per 15 minutes of activity
+type LoginHistory struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Ip        string    `json:"ip" gorm:"size:45"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+	// foreignkey User, removed with the account
+	UserID uint  `json:"user_id" gorm:"index"`
+	User   *User `gorm:"foreignkey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+}
@@ -30,6 +30,9 @@ type MyDrive struct {
 	// foreignkey User
 	UserID uint
 	// User   User `gorm:"foreignkey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+	// foreignkey Organization, null is the personal drive
+	OrganizationID *uint         `json:"organization_id" gorm:"index"`
+	Organization   *Organization `gorm:"foreignkey:OrganizationID;constraint:OnDelete:CASCADE;" json:"-"`
 }
 
 type MyDriveRenameInput struct {
@@ -93,8 +96,8 @@ type MyDriveRepository interface {
 	// FUNTIONS
 
 	// REPOS
-	MyDrive(user User, param response.ParamsPagination) (*response.Pagination, *fiber.Error)
-	Get(id string) (MyDrive, *fiber.Error)
+	MyDrive(tenant Tenant, param response.ParamsPagination) (*response.Pagination, *fiber.Error)
+	Get(tenant Tenant, id string) (MyDrive, *fiber.Error)
 	Create(obj MyDrive) (MyDrive, *fiber.Error)
 	Update(obj MyDrive) (MyDrive, *fiber.Error)
 	Delete(obj MyDrive) *fiber.Error
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"myapp/pkg/money"
+	"myapp/pkg/response"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// OrderStatus is the state of the order
+type OrderStatus string
+
+const (
+	OrderPending   OrderStatus = "pending"
+	OrderPaid      OrderStatus = "paid"
+	OrderShipped   OrderStatus = "shipped"
+	OrderCompleted OrderStatus = "completed"
+	OrderCancelled OrderStatus = "cancelled"
+	OrderRefunded  OrderStatus = "refunded"
+)
+
+// orderTransitions is the state machine, the allowed next statuses of each status
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderPending:   {OrderPaid, OrderCancelled},
+	OrderPaid:      {OrderShipped, OrderRefunded},
+	OrderShipped:   {OrderCompleted, OrderRefunded},
+	OrderCompleted: {OrderRefunded},
+}
+
+// CanTransition check the order can move from the status to the next one
+func (s OrderStatus) CanTransition(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Order is the purchase of the buyer from one seller, the checkout create an order per seller
+type Order struct {
+	gorm.Model
+	Status OrderStatus `json:"status" gorm:"size:20;not null;default:pending;index"`
+	// the sum of the items
+	Total           money.Money `json:"total" gorm:"embedded;embeddedPrefix:total_"`
+	ShippingAddress string      `json:"shipping_address" gorm:"not null"`
+	Note            string      `json:"note" gorm:"default:null"`
+	TrackingNumber  string      `json:"tracking_number" gorm:"size:100;default:null"`
+	// the time of each transition
+	PaidAt      *time.Time `json:"paid_at"`
+	ShippedAt   *time.Time `json:"shipped_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CancelledAt *time.Time `json:"cancelled_at"`
+	RefundedAt  *time.Time `json:"refunded_at"`
+	// foreignkey User, who buy, null once the account is purged so the order and its payments are kept
+	BuyerID *uint `json:"buyer_id" gorm:"index"`
+	Buyer   *User `gorm:"foreignkey:BuyerID;constraint:OnDelete:SET NULL;" json:"buyer,omitempty"`
+	// foreignkey User, the owner of the products, null once the account is purged
+	SellerID *uint `json:"seller_id" gorm:"index"`
+	Seller   *User `gorm:"foreignkey:SellerID;constraint:OnDelete:SET NULL;" json:"seller,omitempty"`
+	// foreignkey Organization of the products, null is the personal products
+	OrganizationID *uint         `json:"organization_id" gorm:"index"`
+	Organization   *Organization `gorm:"foreignkey:OrganizationID;constraint:OnDelete:SET NULL;" json:"-"`
+	Items          []*OrderItem  `gorm:"foreignkey:OrderID" json:"items,omitempty"`
+}
+
+// IsBuyer check the user bought the order, the order of a purged account has no buyer
+func (md Order) IsBuyer(userID uint) bool {
+	return md.BuyerID != nil && *md.BuyerID == userID
+}
+
+// OrderItem is the snapshot of the product at the checkout, it doesn't change with the product
+type OrderItem struct {
+	ID      uint              `json:"id" gorm:"primarykey"`
+	Title   string            `json:"title" gorm:"not null"`
+	SKU     string            `json:"sku" gorm:"size:64;default:null"`
+	Options map[string]string `json:"options" gorm:"serializer:json"`
+	// the price of one item at the checkout
+	UnitPrice money.Money `json:"unit_price" gorm:"embedded;embeddedPrefix:unit_price_"`
+	Quantity  int         `json:"quantity" gorm:"not null"`
+	Subtotal  money.Money `json:"subtotal" gorm:"embedded;embeddedPrefix:subtotal_"`
+	// foreignkey Order
+	OrderID uint   `json:"order_id" gorm:"index"`
+	Order   *Order `gorm:"foreignkey:OrderID;constraint:OnDelete:CASCADE;" json:"-"`
+	// the product can be deleted later, the snapshot is kept
+	ProductID        *uint           `json:"product_id" gorm:"index"`
+	Product          *Product        `gorm:"foreignkey:ProductID;constraint:OnDelete:SET NULL;" json:"-"`
+	ProductVariantID *uint           `json:"variant_id"`
+	ProductVariant   *ProductVariant `gorm:"foreignkey:ProductVariantID;constraint:OnDelete:SET NULL;" json:"-"`
+}
+
+type OrderStatusInput struct {
+	// paid and refunded are only set by the payment, see PaymentRepository.ApplyEvent
+	Status OrderStatus `json:"status" validate:"required,oneof=shipped completed cancelled"`
+	// the tracking number of the shipped order
+	TrackingNumber string `json:"tracking_number" validate:"max=100"`
+}
+
+type OrderUsecase interface {
+	// USECASE
+	MyOrder(c *fiber.Ctx) (*response.Pagination, *fiber.Error)
+	MySale(c *fiber.Ctx) (*response.Pagination, *fiber.Error)
+	GetOrder(c *fiber.Ctx) (Order, *fiber.Error)
+	Cancel(c *fiber.Ctx) (Order, *fiber.Error)
+	ChangeStatus(c *fiber.Ctx, payload OrderStatusInput) (Order, *fiber.Error)
+}
+
+type OrderRepository interface {
+	// FUNTIONS
+	SendOrderEmail(obj Order, to User) error
+
+	// REPOS
+	ListOrder(scope func(db *gorm.DB) *gorm.DB, param response.ParamsPagination) (*response.Pagination, *fiber.Error)
+	GetOrder(id uint) (Order, *fiber.Error)
+	// create the orders, take the stock and empty the cart in one transaction,
+	// return the variants the purchase brought to their low-stock threshold
+	Checkout(buyerID uint, orders []*Order, cart []*CartItem) ([]*Order, []*ProductVariant, *fiber.Error)
+	// move the order from the status to the next one, the cancelled order put back the stock
+	Transition(obj Order, from OrderStatus, userID uint) (Order, *fiber.Error)
+}
@@ -0,0 +1,55 @@
+package models
+
+import "testing"
+
+func TestOrderStatusCanTransition(t *testing.T) {
+	statuses := []OrderStatus{OrderPending, OrderPaid, OrderShipped, OrderCompleted, OrderCancelled, OrderRefunded}
+
+	allowed := map[OrderStatus]map[OrderStatus]bool{
+		OrderPending:   {OrderPaid: true, OrderCancelled: true},
+		OrderPaid:      {OrderShipped: true, OrderRefunded: true},
+		OrderShipped:   {OrderCompleted: true, OrderRefunded: true},
+		OrderCompleted: {OrderRefunded: true},
+		// the cancelled and the refunded orders are final
+		OrderCancelled: {},
+		OrderRefunded:  {},
+	}
+
+	for _, from := range statuses {
+		for _, next := range statuses {
+			if got, want := from.CanTransition(next), allowed[from][next]; got != want {
+				t.Errorf("%s to %s: got %v, want %v", from, next, got, want)
+			}
+		}
+	}
+
+	if OrderStatus("unknown").CanTransition(OrderPaid) || OrderPending.CanTransition("unknown") {
+		t.Error("the unknown status can't transition")
+	}
+}
+
+func TestProductVariantCrossedLowStock(t *testing.T) {
+	tests := []struct {
+		stock, threshold, change int
+		want                     bool
+	}{
+		// 6 to 5 reach the threshold
+		{5, 5, -1, true},
+		{2, 5, -4, true},
+		// already low before the change
+		{4, 5, -1, false},
+		// still above the threshold
+		{6, 5, -1, false},
+		// the restock
+		{10, 5, 8, false},
+		// no threshold
+		{0, 0, -3, false},
+	}
+
+	for _, tt := range tests {
+		variant := ProductVariant{Stock: tt.stock, LowStockThreshold: tt.threshold}
+		if got := variant.CrossedLowStock(tt.change); got != tt.want {
+			t.Errorf("stock %d, threshold %d, change %d: got %v, want %v", tt.stock, tt.threshold, tt.change, got, tt.want)
+		}
+	}
+}
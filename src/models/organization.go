@@ -0,0 +1,163 @@
+package models
+
+import (
+	"myapp/pkg/response"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type OrganizationRole string
+
+const (
+	OrgRoleOwner  OrganizationRole = "owner"
+	OrgRoleAdmin  OrganizationRole = "admin"
+	OrgRoleMember OrganizationRole = "member"
+)
+
+// Organization is a tenant, the products and drives are shared by the members
+type Organization struct {
+	gorm.Model
+	Name    string                `json:"name" gorm:"size:150;not null"`
+	Members []*OrganizationMember `gorm:"foreignkey:OrganizationID;constraint:OnDelete:CASCADE;" json:"members,omitempty"`
+}
+
+type OrganizationMember struct {
+	gorm.Model
+	Role OrganizationRole `json:"role" gorm:"size:20;default:member"`
+	// foreignkey Organization
+	OrganizationID uint          `json:"organization_id" gorm:"uniqueIndex:idx_organization_member"`
+	Organization   *Organization `gorm:"foreignkey:OrganizationID;constraint:OnDelete:CASCADE;" json:"organization,omitempty"`
+	// foreignkey User
+	UserID uint  `json:"user_id" gorm:"uniqueIndex:idx_organization_member"`
+	User   *User `gorm:"foreignkey:UserID;constraint:OnDelete:CASCADE;" json:"user,omitempty"`
+}
+
+// CanManage check the member is allowed to manage the organization and all of its data
+func (md OrganizationMember) CanManage() bool {
+	return md.Role == OrgRoleOwner || md.Role == OrgRoleAdmin
+}
+
+// OrganizationInvitation invite someone by email to join the organization
+type OrganizationInvitation struct {
+	gorm.Model
+	Email     string           `json:"email" gorm:"not null;index"`
+	Code      string           `json:"-" gorm:"not null;uniqueIndex"`
+	Role      OrganizationRole `json:"role" gorm:"size:20;default:member"`
+	Status    InvitationStatus `json:"status" gorm:"size:20;default:pending"`
+	ExpiredAt time.Time        `json:"expired_at"`
+	// foreignkey Organization
+	OrganizationID uint          `json:"organization_id" gorm:"index"`
+	Organization   *Organization `gorm:"foreignkey:OrganizationID;constraint:OnDelete:CASCADE;" json:"organization,omitempty"`
+	// foreignkey User
+	InvitedByID uint `json:"invited_by_id"`
+	InvitedBy   User `gorm:"foreignkey:InvitedByID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+// CurrentStatus return the status, a pending invitation after its expiry time is expired
+func (md OrganizationInvitation) CurrentStatus() InvitationStatus {
+	if md.Status == InvitationPending && time.Now().After(md.ExpiredAt) {
+		return InvitationExpired
+	}
+	return md.Status
+}
+
+// Tenant is the active workspace of the request,
+// OrganizationID 0 is the personal workspace of the user
+type Tenant struct {
+	UserID         uint
+	OrganizationID uint
+	Role           OrganizationRole
+}
+
+// IsPersonal check the tenant is the personal workspace
+func (t Tenant) IsPersonal() bool {
+	return t.OrganizationID == 0
+}
+
+// CanManage check the user is allowed to change the data owned by another user in the tenant
+func (t Tenant) CanManage(ownerID uint) bool {
+	if ownerID == t.UserID {
+		return true
+	}
+	return t.IsManager()
+}
+
+// IsManager check the user is the owner or admin of the organization, who manage all of its data
+func (t Tenant) IsManager() bool {
+	return !t.IsPersonal() && (t.Role == OrgRoleOwner || t.Role == OrgRoleAdmin)
+}
+
+// OrganizationPtr return the organization ID to be stored, nil for personal workspace
+func (t Tenant) OrganizationPtr() *uint {
+	if t.IsPersonal() {
+		return nil
+	}
+	id := t.OrganizationID
+	return &id
+}
+
+type OrganizationInput struct {
+	Name string `json:"name" validate:"required,min=3,max=150"`
+}
+
+type OrganizationInviteInput struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"omitempty,oneof=admin member"`
+	// expiration in hours, default 72 hours
+	ExpiresIn int `json:"expires_in" validate:"omitempty,min=1,max=720"`
+}
+
+type OrganizationMemberInput struct {
+	Role string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+type OrganizationSwitchInput struct {
+	// 0 to switch back to the personal workspace
+	OrganizationID uint `json:"organization_id"`
+}
+
+type OrganizationUsecase interface {
+	// USECASE
+	MyOrganization(c *fiber.Ctx) ([]*OrganizationMember, *fiber.Error)
+	Get(c *fiber.Ctx) (Organization, *fiber.Error)
+	Create(c *fiber.Ctx, payload OrganizationInput) (Organization, *fiber.Error)
+	Update(c *fiber.Ctx, payload OrganizationInput) (Organization, *fiber.Error)
+	Delete(c *fiber.Ctx) *fiber.Error
+	Switch(c *fiber.Ctx, orgID uint) (Token, *fiber.Error)
+
+	ListMember(c *fiber.Ctx) (*response.Pagination, *fiber.Error)
+	UpdateMember(c *fiber.Ctx, payload OrganizationMemberInput) (OrganizationMember, *fiber.Error)
+	RemoveMember(c *fiber.Ctx) *fiber.Error
+
+	ListInvitation(c *fiber.Ctx) (*response.Pagination, *fiber.Error)
+	Invite(c *fiber.Ctx, payload OrganizationInviteInput) (OrganizationInvitation, *fiber.Error)
+	RevokeInvitation(c *fiber.Ctx) *fiber.Error
+	AcceptInvitation(c *fiber.Ctx, code string) (OrganizationMember, *fiber.Error)
+}
+
+type OrganizationRepository interface {
+	// FUNTIONS
+	SendInvitationEmail(obj OrganizationInvitation, org Organization, code string) error
+
+	// REPOS
+	MyOrganization(userID uint) ([]*OrganizationMember, *fiber.Error)
+	Get(id uint) (Organization, *fiber.Error)
+	Create(obj Organization) (Organization, *fiber.Error)
+	Update(obj Organization) (Organization, *fiber.Error)
+	Delete(obj Organization) *fiber.Error
+
+	FindMember(orgID uint, userID uint) (OrganizationMember, *fiber.Error)
+	ListMember(orgID uint, param response.ParamsPagination) (*response.Pagination, *fiber.Error)
+	CountOwner(orgID uint) (int64, *fiber.Error)
+	CreateMember(obj OrganizationMember) (OrganizationMember, *fiber.Error)
+	UpdateMember(obj OrganizationMember) (OrganizationMember, *fiber.Error)
+	DeleteMember(obj OrganizationMember) *fiber.Error
+
+	ListInvitation(orgID uint, param response.ParamsPagination) (*response.Pagination, *fiber.Error)
+	GetInvitation(id uint) (OrganizationInvitation, *fiber.Error)
+	FindInvitationByCode(code string) (OrganizationInvitation, *fiber.Error)
+	CreateInvitation(obj OrganizationInvitation) (OrganizationInvitation, *fiber.Error)
+	UpdateInvitation(obj OrganizationInvitation) (OrganizationInvitation, *fiber.Error)
+}
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Passkey is a WebAuthn credential registered by a user
+type Passkey struct {
+	gorm.Model
+	Name            string     `json:"name" gorm:"size:100;"`
+	CredentialID    []byte     `json:"-" gorm:"not null;uniqueIndex"`
+	PublicKey       []byte     `json:"-" gorm:"not null"`
+	AttestationType string     `json:"attestation_type"`
+	Transports      string     `json:"transports"`
+	AAGUID          []byte     `json:"-"`
+	SignCount       uint32     `json:"sign_count"`
+	CloneWarning    bool       `json:"clone_warning" gorm:"default:false"`
+	BackupEligible  bool       `json:"backup_eligible" gorm:"default:false"`
+	BackupState     bool       `json:"backup_state" gorm:"default:false"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+	// foreignkey User
+	UserID uint `json:"user_id"`
+	User   User `gorm:"foreignkey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+func (md Passkey) MarshalJSON() ([]byte, error) {
+	type Alias Passkey
+	aux := struct {
+		Alias
+		CredentialID string `json:"credential_id"`
+	}{
+		Alias:        (Alias)(md),
+		CredentialID: base64.RawURLEncoding.EncodeToString(md.CredentialID),
+	}
+	return json.Marshal(aux)
+}
+
+// NewPasskey fill the passkey from a verified webauthn credential
+func NewPasskey(userID uint, name string, cred *webauthn.Credential) Passkey {
+	var transports []string
+	for _, t := range cred.Transport {
+		transports = append(transports, string(t))
+	}
+
+	return Passkey{
+		Name:            name,
+		CredentialID:    cred.ID,
+		PublicKey:       cred.PublicKey,
+		AttestationType: cred.AttestationType,
+		Transports:      strings.Join(transports, ","),
+		AAGUID:          cred.Authenticator.AAGUID,
+		SignCount:       cred.Authenticator.SignCount,
+		CloneWarning:    cred.Authenticator.CloneWarning,
+		BackupEligible:  cred.Flags.BackupEligible,
+		BackupState:     cred.Flags.BackupState,
+		UserID:          userID,
+	}
+}
+
+// Credential convert the stored passkey back to webauthn credential
+func (md Passkey) Credential() webauthn.Credential {
+	var transports []protocol.AuthenticatorTransport
+	if md.Transports != "" {
+		for _, t := range strings.Split(md.Transports, ",") {
+			transports = append(transports, protocol.AuthenticatorTransport(t))
+		}
+	}
+
+	return webauthn.Credential{
+		ID:              md.CredentialID,
+		PublicKey:       md.PublicKey,
+		AttestationType: md.AttestationType,
+		Transport:       transports,
+		Flags: webauthn.CredentialFlags{
+			BackupEligible: md.BackupEligible,
+			BackupState:    md.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:       md.AAGUID,
+			SignCount:    md.SignCount,
+			CloneWarning: md.CloneWarning,
+		},
+	}
+}
+
+// PasskeyUser wrap the User with their passkeys, implements webauthn.User
+type PasskeyUser struct {
+	User     User
+	Passkeys []*Passkey
+}
+
+func (u PasskeyUser) WebAuthnID() []byte {
+	return []byte(fmt.Sprintf("%d", u.User.ID))
+}
+
+func (u PasskeyUser) WebAuthnName() string {
+	return u.User.Email
+}
+
+func (u PasskeyUser) WebAuthnDisplayName() string {
+	name := strings.TrimSpace(fmt.Sprintf("%s %s", u.User.FirstName, u.User.LastName))
+	if name == "" {
+		return u.User.Username
+	}
+	return name
+}
+
+func (u PasskeyUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u PasskeyUser) WebAuthnCredentials() []webauthn.Credential {
+	var creds []webauthn.Credential
+	for _, pk := range u.Passkeys {
+		creds = append(creds, pk.Credential())
+	}
+	return creds
+}
+
+type PasskeyRenameInput struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+type PasskeyLoginInput struct {
+	// leave empty for discoverable login (username-less)
+	Email string `json:"email"`
+}
+
+type PasskeyLoginOptions struct {
+	SessionID string                        `json:"session_id"`
+	Options   *protocol.CredentialAssertion `json:"options"`
+}
+
+type PasskeyUsecase interface {
+	// USECASE
+	BeginRegistration(c *fiber.Ctx) (*protocol.CredentialCreation, *fiber.Error)
+	FinishRegistration(c *fiber.Ctx, name string) (Passkey, *fiber.Error)
+	BeginLogin(ctx context.Context, payload PasskeyLoginInput) (PasskeyLoginOptions, *fiber.Error)
+	FinishLogin(ctx context.Context, sessionID string, body []byte) (Token, *fiber.Error)
+	ListPasskey(c *fiber.Ctx) ([]*Passkey, *fiber.Error)
+	Rename(c *fiber.Ctx, payload PasskeyRenameInput) (Passkey, *fiber.Error)
+	Delete(c *fiber.Ctx) *fiber.Error
+}
+
+type PasskeyRepository interface {
+	// FUNTIONS
+	SaveSession(key string, session webauthn.SessionData) error
+	PopSession(key string) (webauthn.SessionData, *fiber.Error)
+
+	// REPOS
+	ListPasskey(userID uint) ([]*Passkey, *fiber.Error)
+	Get(id uint) (Passkey, *fiber.Error)
+	FindByCredentialID(credentialID []byte) (Passkey, *fiber.Error)
+	Create(obj Passkey) (Passkey, *fiber.Error)
+	Update(obj Passkey) (Passkey, *fiber.Error)
+	Delete(obj Passkey) *fiber.Error
+}
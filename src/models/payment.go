@@ -0,0 +1,77 @@
+package models
+
+import (
+	"context"
+	"myapp/pkg/money"
+	"myapp/pkg/payment"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// PaymentGateway is the payment provider, see the gateways of pkg/payment
+type PaymentGateway interface {
+	// the provider name stored with the payment and used in the webhook URL
+	Name() string
+	// create the payment of the amount, the client confirm it with the client secret
+	CreateIntent(ctx context.Context, req payment.IntentRequest) (payment.Intent, error)
+	Confirm(ctx context.Context, intentID string) (payment.Intent, error)
+	Refund(ctx context.Context, intentID string, amount money.Money) (payment.Refund, error)
+	// check the signature of the webhook and read its event, header return the request header
+	VerifyWebhook(payload []byte, header func(key string) string) (payment.Event, error)
+}
+
+// Payment is an attempt to pay the order at the provider
+type Payment struct {
+	gorm.Model
+	Provider string `json:"provider" gorm:"size:20;not null;uniqueIndex:idx_payment_intent"`
+	IntentID string `json:"intent_id" gorm:"size:255;not null;uniqueIndex:idx_payment_intent"`
+	// only returned to the buyer, the client confirm the payment with it
+	ClientSecret  string         `json:"client_secret,omitempty" gorm:"default:null"`
+	Status        payment.Status `json:"status" gorm:"size:20;not null;default:pending;index"`
+	Amount        money.Money    `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	FailureReason string         `json:"failure_reason" gorm:"default:null"`
+	// the time of the last applied event
+	LastEventAt *time.Time `json:"last_event_at"`
+	// foreignkey Order
+	OrderID uint   `json:"order_id" gorm:"index"`
+	Order   *Order `gorm:"foreignkey:OrderID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+// PaymentEvent is the processed event of the provider, the same event is never applied twice
+type PaymentEvent struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	Provider  string    `json:"provider" gorm:"size:20;not null;uniqueIndex:idx_payment_event"`
+	EventID   string    `json:"event_id" gorm:"size:255;not null;uniqueIndex:idx_payment_event"`
+	Type      string    `json:"type" gorm:"size:100"`
+	IntentID  string    `json:"intent_id" gorm:"size:255;index"`
+}
+
+// PaymentResult is the outcome of an event
+type PaymentResult struct {
+	Payment Payment
+	Order   Order
+	// the payment status has been changed, false for the duplicate and the late event
+	Applied bool
+	// the order status has been reconciled with the payment
+	OrderChanged bool
+}
+
+type PaymentUsecase interface {
+	// USECASE
+	GetPayment(c *fiber.Ctx) (Payment, *fiber.Error)
+	CreatePayment(c *fiber.Ctx) (Payment, *fiber.Error)
+	ConfirmPayment(c *fiber.Ctx) (Payment, *fiber.Error)
+	Refund(c *fiber.Ctx) (Payment, *fiber.Error)
+	Webhook(c *fiber.Ctx) *fiber.Error
+}
+
+type PaymentRepository interface {
+	// REPOS
+	LatestPayment(orderID uint) (Payment, *fiber.Error)
+	Create(obj Payment) (Payment, *fiber.Error)
+	// record the event, move the payment forward and reconcile the order in one transaction
+	ApplyEvent(provider string, event payment.Event) (PaymentResult, *fiber.Error)
+}
@@ -3,24 +3,78 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"myapp/pkg/money"
 	"myapp/pkg/response"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
+// ProductStatus is the step of the publishing workflow
+type ProductStatus string
+
+const (
+	ProductDraft ProductStatus = "draft"
+	// published automatically on PublishAt
+	ProductScheduled ProductStatus = "scheduled"
+	ProductPublished ProductStatus = "published"
+	ProductArchived  ProductStatus = "archived"
+)
+
 type Product struct {
 	gorm.Model
-	Title       string  `json:"title" validate:"required,min=4"`
-	Description string  `json:"description" gorm:"default:null"`
-	Image       *string `json:"image" gorm:"default:null"`
-	Price       float64 `json:"price" validate:"required"`
-	IsEnable    bool    `json:"is_enable" gorm:"default:true"`
+	Title string `json:"title" validate:"required,min=4"`
+	// generated from the title, unique among the products and their previous slugs, see ProductSlug
+	Slug        string `json:"slug" gorm:"size:120;default:null;uniqueIndex"`
+	Description string `json:"description" gorm:"default:null"`
+	// path of the cover image, the gallery is in Images
+	Image *string `json:"image" gorm:"default:null"`
+	// the exact price in the minor units, the columns price_amount and price_currency
+	Price    money.Money `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	IsEnable bool        `json:"is_enable" gorm:"default:false"`
+	// the publishing workflow, IsEnable mirror the published status for the older clients,
+	// the product is archived on UnpublishAt
+	Status      ProductStatus `json:"status" gorm:"size:20;default:draft;index"`
+	PublishAt   *time.Time    `json:"publish_at"`
+	UnpublishAt *time.Time    `json:"unpublish_at"`
+	// hidden by the moderation, only the owner see the product and the reason, see ProductReport
+	HiddenAt     *time.Time `json:"hidden_at"`
+	HiddenReason string     `json:"hidden_reason,omitempty" gorm:"default:null"`
+	// the rating of the approved reviews, refreshed with the review in the same transaction
+	RatingAverage float64 `json:"rating_average" gorm:"type:numeric(3,2);not null;default:0"`
+	RatingCount   int64   `json:"rating_count" gorm:"not null;default:0"`
+	// the seller reference of the product, the key of the import
+	ExternalID *string `json:"external_id" gorm:"size:100;default:null;uniqueIndex:idx_product_external_id,where:external_id IS NOT NULL AND deleted_at IS NULL"`
 	// foreignkey User
-	UserID uint
+	UserID uint `gorm:"uniqueIndex:idx_product_external_id"`
 	User   User `gorm:"foreignkey:UserID;constraint:OnDelete:CASCADE;" json:"user"`
+	// foreignkey Organization, null is the personal product
+	OrganizationID *uint         `json:"organization_id" gorm:"index"`
+	Organization   *Organization `gorm:"foreignkey:OrganizationID;constraint:OnDelete:CASCADE;" json:"-"`
+	// foreignkey Category, null is uncategorized
+	CategoryID *uint             `json:"category_id" gorm:"index"`
+	Category   *Category         `gorm:"foreignkey:CategoryID;constraint:OnDelete:SET NULL;" json:"category,omitempty"`
+	Tags       []*Tag            `gorm:"many2many:product_tags;constraint:OnDelete:CASCADE;" json:"tags"`
+	Variants   []*ProductVariant `gorm:"foreignkey:ProductID" json:"variants,omitempty"`
+	Images     []*ProductImage   `gorm:"foreignkey:ProductID" json:"images"`
+	// filled by the full-text search only
+	SearchRank           *float64 `gorm:"->;-:migration" json:"rank,omitempty"`
+	TitleHighlight       string   `gorm:"->;-:migration" json:"-"`
+	DescriptionHighlight string   `gorm:"->;-:migration" json:"-"`
+	// the favorites, filled in batch by the product usecase
+	IsFavorited   bool  `gorm:"-" json:"is_favorited"`
+	FavoriteCount int64 `gorm:"-" json:"favorite_count"`
+	// filled by the list with ?currency=, the price converted with the exchange rates
+	ConvertedPrice *money.Money `gorm:"-" json:"converted_price,omitempty"`
+}
+
+// ProductHighlight is the search snippet, the matched words are wrapped in <mark>
+type ProductHighlight struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
 }
 
 func (md Product) MarshalJSON() ([]byte, error) {
@@ -28,18 +82,26 @@ func (md Product) MarshalJSON() ([]byte, error) {
 	var image *string = md.Image
 	// check image is empty
 	if image != nil {
-		// check image startwith http/https
+		// check image startwith http/https, the copy keep the path of the product
 		if !strings.HasPrefix(*image, "http") {
-			*image = fmt.Sprintf("%s/%s", os.Getenv("CLIENT_ORIGIN"), *md.Image)
+			url := fmt.Sprintf("%s/%s", os.Getenv("CLIENT_ORIGIN"), *md.Image)
+			image = &url
 		}
 	}
 
+	var highlight *ProductHighlight
+	if md.TitleHighlight != "" || md.DescriptionHighlight != "" {
+		highlight = &ProductHighlight{Title: md.TitleHighlight, Description: md.DescriptionHighlight}
+	}
+
 	aux := struct {
 		Alias
-		Image *string `json:"image"`
+		Image     *string           `json:"image"`
+		Highlight *ProductHighlight `json:"highlight,omitempty"`
 	}{
-		Alias: (Alias)(md),
-		Image: image,
+		Alias:     (Alias)(md),
+		Image:     image,
+		Highlight: highlight,
 	}
 	return json.Marshal(aux)
 }
@@ -50,9 +112,47 @@ type ProductPopulateInput struct {
 }
 
 type ProductInput struct {
-	Title       string  `json:"title" form:"title" validate:"required,min=4"`
-	Description string  `json:"description" form:"description"`
-	Price       float64 `json:"price" form:"price" validate:"required,number"`
+	Title       string `json:"title" form:"title" validate:"required,min=4"`
+	Description string `json:"description" form:"description"`
+	// the decimal price, e.g. "19.99", with no more decimal places than the currency
+	Price money.Decimal `json:"price" form:"price" validate:"required" swaggertype:"string"`
+	// ISO 4217 code, empty keep the currency of the product or use the default currency
+	Currency string `json:"currency" form:"currency" validate:"omitempty,len=3,alpha"`
+	// 0 or empty is uncategorized
+	CategoryID uint     `json:"category_id" form:"category_id"`
+	Tags       []string `json:"tags" form:"tags" validate:"omitempty,max=20,dive,max=50"`
+	// the seller reference, unique among the products of the seller, empty keep the current one
+	ExternalID string `json:"external_id" form:"external_id" validate:"omitempty,max=100"`
+}
+
+type ProductStatusInput struct {
+	Status ProductStatus `json:"status" validate:"required,oneof=draft scheduled published archived"`
+	// required by scheduled, must be in the future
+	PublishAt *time.Time `json:"publish_at"`
+	// optional for scheduled and published
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+// LiveProduct select the products visible in the catalog at the time,
+// the scheduled product is live on time even before the scheduler flip its status, the hidden product is never live
+func LiveProduct(now time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("products.status IN ?", []ProductStatus{ProductPublished, ProductScheduled}).
+			Where("products.hidden_at IS NULL").
+			Where("products.publish_at IS NULL OR products.publish_at <= ?", now).
+			Where("products.unpublish_at IS NULL OR products.unpublish_at > ?", now)
+	}
+}
+
+// IsLive check the product is visible in the catalog at the time, see LiveProduct
+func (md Product) IsLive(now time.Time) bool {
+	if md.HiddenAt != nil || (md.Status != ProductPublished && md.Status != ProductScheduled) {
+		return false
+	}
+	if md.PublishAt != nil && md.PublishAt.After(now) {
+		return false
+	}
+	return md.UnpublishAt == nil || md.UnpublishAt.After(now)
 }
 
 type ProductUsecase interface {
@@ -60,24 +160,70 @@ type ProductUsecase interface {
 	MyProduct(c *fiber.Ctx) (*response.Pagination, *fiber.Error)
 	ListProduct(c *fiber.Ctx) (*response.Pagination, *fiber.Error)
 	GetProduct(c *fiber.Ctx) (Product, *fiber.Error)
+	// the product of the slug, or the current slug to redirect to when the slug is a previous one
+	GetBySlug(c *fiber.Ctx) (Product, string, *fiber.Error)
+	// same as GetBySlug for the public catalog, without user
+	PublicBySlug(slug string) (Product, string, *fiber.Error)
+	// the public catalog without user, the live products outside of any organization,
+	// the first pages are cached until a product change
+	PublicListProduct(c *fiber.Ctx) (CatalogPage, *fiber.Error)
+	// the product of the public catalog and the time of its last change
+	PublicProduct(c *fiber.Ctx) (PublicProduct, time.Time, *fiber.Error)
 	Create(c *fiber.Ctx, payload ProductInput) (Product, *fiber.Error)
 	Update(c *fiber.Ctx, payload ProductInput) (Product, *fiber.Error)
+	ChangeStatus(c *fiber.Ctx, payload ProductStatusInput) (Product, *fiber.Error)
 	Delete(c *fiber.Ctx) *fiber.Error
+	// the deleted products, purged after the retention days
+	ListTrash(c *fiber.Ctx) (*response.Pagination, *fiber.Error)
+	Restore(c *fiber.Ctx) (Product, *fiber.Error)
+	DeletePermanently(c *fiber.Ctx) *fiber.Error
 
 	// ADMIN ROLE
 	PopulateProducts(userID uint, n int) *fiber.Error
+
+	// BACKGROUND JOBS
+	PublishScheduled() error
+	PurgeTrash() error
 }
 
 type ProductRepository interface {
 	// FUNTIONS
+	// the time of the last change of the catalog, the version of the cached pages
+	CatalogModifiedAt() time.Time
+	GetCatalogPage(version time.Time, key string) (CatalogPage, bool)
+	// cache the page in the version it was built from
+	SetCatalogPage(key string, page CatalogPage, ttl time.Duration)
+	// expire the cached pages, called after each write of the live products
+	ClearCatalogCache()
 
 	// REPOS
-	MyProduct(user User, param response.ParamsPagination) (*response.Pagination, *fiber.Error)
-	ListProduct(param response.ParamsPagination) (*response.Pagination, *fiber.Error)
-	GetProduct(id uint) (Product, *fiber.Error)
-	Create(obj Product) (Product, *fiber.Error)
-	Update(obj Product) (Product, *fiber.Error)
-	Delete(obj Product) *fiber.Error
+	MyProduct(tenant Tenant, param response.ParamsPagination) (*response.Pagination, *fiber.Error)
+	ListProduct(tenant Tenant, param response.ParamsPagination, filter ProductFilter) (*response.Pagination, *fiber.Error)
+	GetProduct(tenant Tenant, id uint) (Product, *fiber.Error)
+	GetProductBySlug(tenant Tenant, slug string) (Product, *fiber.Error)
+	// the current slug of the product that had the slug, not found when the slug was never used
+	FindSlugRedirect(slug string) (string, *fiber.Error)
+	// the product of the seller in any workspace
+	FindByExternalID(userID uint, externalID string) (Product, *fiber.Error)
+	// call fn with the products of the seller in the tenant by batch of size
+	EachProduct(tenant Tenant, size int, fn func([]*Product) error) *fiber.Error
+	// the writes record a ProductRevision of the actor in the same transaction,
+	// the slug follow the title
+	Create(obj Product, actorID uint) (Product, *fiber.Error)
+	Update(obj Product, actorID uint) (Product, *fiber.Error)
+	UpdateStatus(obj Product, actorID uint) (Product, *fiber.Error)
+	// restore every tracked field of obj, revisionID is the restored revision
+	Revert(obj Product, revisionID uint, actorID uint) (Product, *fiber.Error)
+	Delete(obj Product, actorID uint) *fiber.Error
+	ListTrash(tenant Tenant, param response.ParamsPagination) (*response.Pagination, *fiber.Error)
+	GetTrashed(tenant Tenant, id uint) (Product, *fiber.Error)
+	// the products deleted before the time, the oldest first
+	ListTrashDue(before time.Time, limit int) ([]*Product, *fiber.Error)
+	Restore(obj Product, actorID uint) (Product, *fiber.Error)
+	// delete the trashed product with its rows, then remove its image files
+	PermanentDelete(obj Product) *fiber.Error
+	PublishDue(now time.Time) ([]*Product, *fiber.Error)
+	ArchiveDue(now time.Time) ([]*Product, *fiber.Error)
 
 	// ADMIN ROLE
 	PopulateProducts(userID uint, n int) *fiber.Error
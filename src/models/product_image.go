@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"myapp/pkg/utils"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ProductImage is an image of the product gallery, ordered by position
+type ProductImage struct {
+	gorm.Model
+	Path     string `json:"-" gorm:"not null"`
+	AltText  string `json:"alt_text" gorm:"size:255;default:null"`
+	Position int    `json:"position" gorm:"not null;default:0"`
+	// the cover is also stored in Product.Image
+	IsCover bool `json:"is_cover" gorm:"not null;default:false"`
+	// foreignkey Product
+	ProductID uint     `json:"product_id" gorm:"index"`
+	Product   *Product `gorm:"foreignkey:ProductID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+// IsLocal check the image is stored on the server
+func (md ProductImage) IsLocal() bool {
+	return !strings.HasPrefix(md.Path, "http")
+}
+
+// AbsoluteURL return the URL of the image, the local image is served by the server
+func (md ProductImage) AbsoluteURL() string {
+	if md.IsLocal() {
+		return fmt.Sprintf("%s/%s", os.Getenv("CLIENT_ORIGIN"), md.Path)
+	}
+	return md.Path
+}
+
+func (md ProductImage) MarshalJSON() ([]byte, error) {
+	type Alias ProductImage
+	url := md.AbsoluteURL()
+	thumbnail := md.Path
+
+	// the external image has no thumbnail
+	if md.IsLocal() {
+		thumbnail = fmt.Sprintf("%s/%s", os.Getenv("CLIENT_ORIGIN"), *utils.GetThumbnail(md.Path))
+	}
+
+	aux := struct {
+		Alias
+		URL       string `json:"url"`
+		Thumbnail string `json:"thumbnail"`
+	}{
+		Alias:     (Alias)(md),
+		URL:       url,
+		Thumbnail: thumbnail,
+	}
+	return json.Marshal(aux)
+}
+
+type ProductImageInput struct {
+	AltText string `json:"alt_text" form:"alt_text" validate:"max=255"`
+	IsCover bool   `json:"is_cover" form:"is_cover"`
+}
+
+type ProductImageOrderInput struct {
+	// all image IDs of the product in the new order
+	ImageIDs []uint `json:"image_ids" validate:"required,min=1"`
+}
+
+type ProductImageUsecase interface {
+	// USECASE
+	ListImage(c *fiber.Ctx) ([]*ProductImage, *fiber.Error)
+	AddImage(c *fiber.Ctx, payload ProductImageInput) ([]*ProductImage, *fiber.Error)
+	UpdateImage(c *fiber.Ctx, payload ProductImageInput) (ProductImage, *fiber.Error)
+	Reorder(c *fiber.Ctx, payload ProductImageOrderInput) ([]*ProductImage, *fiber.Error)
+	DeleteImage(c *fiber.Ctx) *fiber.Error
+}
+
+type ProductImageRepository interface {
+	// REPOS
+	ListImage(productID uint) ([]*ProductImage, *fiber.Error)
+	GetImage(productID uint, id uint) (ProductImage, *fiber.Error)
+	AddImages(product Product, images []*ProductImage) ([]*ProductImage, *fiber.Error)
+	UpdateImage(obj ProductImage) (ProductImage, *fiber.Error)
+	DeleteImage(obj ProductImage) *fiber.Error
+	Reorder(productID uint, ids []uint) *fiber.Error
+	SetCover(productID uint, id uint) *fiber.Error
+}
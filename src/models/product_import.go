@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// the limits of the imported file, the size is under the body limit of fiber
+const (
+	MaxImportSize = 4 << 20
+	MaxImportRows = 10000
+)
+
+// ProductColumns is the header of the import and the export, the columns id and status
+// are exported only, the unknown columns are ignored
+var ProductColumns = []string{"external_id", "title", "description", "price", "currency", "category_id", "tags", "status", "id"}
+
+type ImportStatus string
+
+const (
+	ImportPending    ImportStatus = "pending"
+	ImportProcessing ImportStatus = "processing"
+	ImportDone       ImportStatus = "done"
+	ImportFailed     ImportStatus = "failed"
+)
+
+// ProductImport is the background job of a CSV or XLSX file, the products are upserted by external_id
+type ProductImport struct {
+	gorm.Model
+	Status   ImportStatus `json:"status" gorm:"size:20;default:pending;index"`
+	Format   string       `json:"format" gorm:"size:10"`
+	FileName string       `json:"file_name"`
+	FilePath string       `json:"-"`
+	// the rows rejected with their errors, in the format of the file
+	ReportPath    string     `json:"-"`
+	TotalRows     int        `json:"total_rows"`
+	ProcessedRows int        `json:"processed_rows"`
+	CreatedRows   int        `json:"created_rows"`
+	UpdatedRows   int        `json:"updated_rows"`
+	FailedRows    int        `json:"failed_rows"`
+	Error         string     `json:"error,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at"`
+	// foreignkey User
+	UserID uint `json:"user_id" gorm:"index"`
+	User   User `gorm:"foreignkey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+	// foreignkey Organization, the workspace of the imported products
+	OrganizationID *uint         `json:"organization_id" gorm:"index"`
+	Organization   *Organization `gorm:"foreignkey:OrganizationID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+func (md ProductImport) MarshalJSON() ([]byte, error) {
+	type Alias ProductImport
+	return json.Marshal(struct {
+		Alias
+		HasReport bool `json:"has_report"`
+	}{
+		Alias:     (Alias)(md),
+		HasReport: md.ReportPath != "",
+	})
+}
+
+type ProductImportUsecase interface {
+	// USECASE
+	Import(c *fiber.Ctx) (ProductImport, *fiber.Error)
+	ListImport(c *fiber.Ctx) ([]*ProductImport, *fiber.Error)
+	GetImport(c *fiber.Ctx) (ProductImport, *fiber.Error)
+	Report(c *fiber.Ctx) (ProductImport, *fiber.Error)
+	Export(c *fiber.Ctx) *fiber.Error
+}
+
+type ProductImportRepository interface {
+	// REPOS
+	ListImport(tenant Tenant) ([]*ProductImport, *fiber.Error)
+	GetImport(tenant Tenant, id uint) (ProductImport, *fiber.Error)
+	Create(obj ProductImport) (ProductImport, *fiber.Error)
+	Update(obj ProductImport) (ProductImport, *fiber.Error)
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"myapp/pkg/response"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ReportReason is the kind of abuse reported
+type ReportReason string
+
+const (
+	ReportSpam        ReportReason = "spam"
+	ReportProhibited  ReportReason = "prohibited"
+	ReportCounterfeit ReportReason = "counterfeit"
+	ReportOffensive   ReportReason = "offensive"
+	ReportMisleading  ReportReason = "misleading"
+	ReportOther       ReportReason = "other"
+)
+
+// ReportStatus is the step of the moderation queue
+type ReportStatus string
+
+const (
+	ReportOpen     ReportStatus = "open"
+	ReportResolved ReportStatus = "resolved"
+)
+
+// ModerationAction is the decision of the moderator on the reported product
+type ModerationAction string
+
+const (
+	// the report is unfounded, the other reports stay open
+	ModerationDismiss ModerationAction = "dismiss"
+	// the product disappear from the catalog, the owner see the reason
+	ModerationHide ModerationAction = "hide"
+	// the product is hidden and moved to the trash
+	ModerationDelete ModerationAction = "delete"
+	// the seller is warned, the product is unchanged
+	ModerationWarn ModerationAction = "warn"
+)
+
+// ProductReport is the report of a product by a user, one open report per user and product
+type ProductReport struct {
+	gorm.Model
+	Reason  ReportReason `json:"reason" gorm:"size:20;not null;index"`
+	Details string       `json:"details" gorm:"default:null"`
+	Status  ReportStatus `json:"status" gorm:"size:20;not null;default:open;index"`
+	// the decision, the note is sent to the seller
+	Action       *ModerationAction `json:"action" gorm:"size:20"`
+	Note         string            `json:"note,omitempty" gorm:"default:null"`
+	ResolvedAt   *time.Time        `json:"resolved_at"`
+	ResolvedByID *uint             `json:"resolved_by_id"`
+	ResolvedBy   *User             `gorm:"foreignkey:ResolvedByID;constraint:OnDelete:SET NULL;" json:"-"`
+	// foreignkey Product
+	ProductID uint     `json:"product_id" gorm:"uniqueIndex:idx_product_report_open,where:status = 'open' AND deleted_at IS NULL;index"`
+	Product   *Product `gorm:"foreignkey:ProductID;constraint:OnDelete:CASCADE;" json:"product,omitempty"`
+	// foreignkey User, the reporter
+	UserID uint  `json:"user_id" gorm:"uniqueIndex:idx_product_report_open,where:status = 'open' AND deleted_at IS NULL;index"`
+	User   *User `gorm:"foreignkey:UserID;constraint:OnDelete:CASCADE;" json:"user,omitempty"`
+}
+
+type ProductReportInput struct {
+	Reason ReportReason `json:"reason" validate:"required,oneof=spam prohibited counterfeit offensive misleading other"`
+	// required by the other reason
+	Details string `json:"details" validate:"required_if=Reason other,max=1000"`
+}
+
+func (f *ProductReportInput) Sanitize() {
+	f.Details = strings.TrimSpace(f.Details)
+}
+
+type ReportResolveInput struct {
+	Action ModerationAction `json:"action" validate:"required,oneof=dismiss hide delete warn"`
+	// the reason sent to the seller, required unless dismissed
+	Note string `json:"note" validate:"required_unless=Action dismiss,max=1000"`
+}
+
+type ProductReportUsecase interface {
+	// USECASE
+	Report(c *fiber.Ctx, payload ProductReportInput) (ProductReport, *fiber.Error)
+
+	// ADMIN ROLE
+	ListReport(c *fiber.Ctx) (*response.Pagination, *fiber.Error)
+	GetReport(c *fiber.Ctx) (ProductReport, *fiber.Error)
+	Resolve(c *fiber.Ctx, payload ReportResolveInput) (ProductReport, *fiber.Error)
+	// show the hidden product in the catalog again
+	Unhide(c *fiber.Ctx) *fiber.Error
+}
+
+type ProductReportRepository interface {
+	// FUNTIONS
+	SendModerationEmail(product Product, action ModerationAction, note string) error
+
+	// REPOS
+	ListReport(param response.ParamsPagination) (*response.Pagination, *fiber.Error)
+	// the report with its product, deleted or not
+	GetReport(id uint) (ProductReport, *fiber.Error)
+	HasOpenReport(productID uint, userID uint) (bool, *fiber.Error)
+	Create(obj ProductReport) (ProductReport, *fiber.Error)
+	// resolve the report, the other open reports of the product too unless it's dismissed,
+	// the hide and delete actions hide the product in the same transaction
+	Resolve(obj ProductReport) (ProductReport, *fiber.Error)
+	Unhide(productID uint) (Product, *fiber.Error)
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"myapp/pkg/response"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ReviewStatus is the moderation state of the review
+type ReviewStatus string
+
+const (
+	// visible and counted in the product rating
+	ReviewApproved ReviewStatus = "approved"
+	// hidden by the admin, only the author can see it
+	ReviewHidden ReviewStatus = "hidden"
+)
+
+// ProductReview is the rating of the product by a user, one review per user and product
+type ProductReview struct {
+	gorm.Model
+	Rating int    `json:"rating" gorm:"not null"`
+	Title  string `json:"title" gorm:"size:150;not null"`
+	Body   string `json:"body" gorm:"default:null"`
+	// path of the uploaded photo
+	Photo  *string      `json:"photo" gorm:"default:null"`
+	Status ReviewStatus `json:"status" gorm:"size:20;not null;default:approved;index"`
+	// the reason of the moderation, shown to the author
+	ModerationNote string `json:"moderation_note,omitempty" gorm:"default:null"`
+	// foreignkey Product
+	ProductID uint     `json:"product_id" gorm:"uniqueIndex:idx_product_review_user,where:deleted_at IS NULL"`
+	Product   *Product `gorm:"foreignkey:ProductID;constraint:OnDelete:CASCADE;" json:"-"`
+	// foreignkey User, the author
+	UserID uint  `json:"user_id" gorm:"uniqueIndex:idx_product_review_user,where:deleted_at IS NULL;index"`
+	User   *User `gorm:"foreignkey:UserID;constraint:OnDelete:CASCADE;" json:"user,omitempty"`
+}
+
+func (md ProductReview) MarshalJSON() ([]byte, error) {
+	type Alias ProductReview
+	var photo *string
+	if md.Photo != nil {
+		url := *md.Photo
+		if !strings.HasPrefix(url, "http") {
+			url = fmt.Sprintf("%s/%s", os.Getenv("CLIENT_ORIGIN"), url)
+		}
+		photo = &url
+	}
+
+	aux := struct {
+		Alias
+		Photo *string `json:"photo"`
+	}{
+		Alias: (Alias)(md),
+		Photo: photo,
+	}
+	return json.Marshal(aux)
+}
+
+type ProductReviewInput struct {
+	Rating int    `json:"rating" form:"rating" validate:"required,min=1,max=5"`
+	Title  string `json:"title" form:"title" validate:"required,min=2,max=150"`
+	Body   string `json:"body" form:"body" validate:"max=5000"`
+	// remove the current photo, a new "photo" file replace it anyway
+	RemovePhoto bool `json:"remove_photo" form:"remove_photo"`
+}
+
+func (f *ProductReviewInput) Sanitize() {
+	f.Title = strings.TrimSpace(f.Title)
+	f.Body = strings.TrimSpace(f.Body)
+}
+
+type ReviewModerationInput struct {
+	Status ReviewStatus `json:"status" validate:"required,oneof=approved hidden"`
+	Note   string       `json:"note" validate:"max=500"`
+}
+
+// ReviewFilter select the reviews of the list
+type ReviewFilter struct {
+	// 0 is all of the products
+	ProductID uint
+	// empty is all of the statuses
+	Status ReviewStatus
+	// the review of the author is listed whatever its status
+	AuthorID uint
+}
+
+type ProductReviewUsecase interface {
+	// USECASE
+	ListReview(c *fiber.Ctx) (*response.Pagination, *fiber.Error)
+	CreateReview(c *fiber.Ctx, payload ProductReviewInput) (ProductReview, *fiber.Error)
+	UpdateReview(c *fiber.Ctx, payload ProductReviewInput) (ProductReview, *fiber.Error)
+	DeleteReview(c *fiber.Ctx) *fiber.Error
+
+	// ADMIN ROLE
+	ListAllReview(c *fiber.Ctx) (*response.Pagination, *fiber.Error)
+	Moderate(c *fiber.Ctx, payload ReviewModerationInput) (ProductReview, *fiber.Error)
+	AdminDeleteReview(c *fiber.Ctx) *fiber.Error
+}
+
+type ProductReviewRepository interface {
+	// REPOS
+	ListReview(filter ReviewFilter, param response.ParamsPagination) (*response.Pagination, *fiber.Error)
+	GetReview(id uint) (ProductReview, *fiber.Error)
+	HasReviewed(productID uint, userID uint) (bool, *fiber.Error)
+	// the rating of the product is refreshed in the same transaction
+	Create(obj ProductReview) (ProductReview, *fiber.Error)
+	Update(obj ProductReview) (ProductReview, *fiber.Error)
+	Delete(obj ProductReview) *fiber.Error
+}
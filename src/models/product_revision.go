@@ -0,0 +1,156 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"myapp/pkg/response"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RevisionAction is the kind of change recorded by the revision
+type RevisionAction string
+
+const (
+	RevisionCreate RevisionAction = "create"
+	RevisionUpdate RevisionAction = "update"
+	RevisionStatus RevisionAction = "status"
+	// by the scheduler, on PublishAt and UnpublishAt
+	RevisionPublish RevisionAction = "publish"
+	RevisionArchive RevisionAction = "archive"
+	RevisionRevert  RevisionAction = "revert"
+	RevisionDelete  RevisionAction = "delete"
+	// out of the trash
+	RevisionRestore RevisionAction = "restore"
+)
+
+// FieldChange is the JSON value of the field before and after the change
+type FieldChange struct {
+	From json.RawMessage `json:"from" swaggertype:"object"`
+	To   json.RawMessage `json:"to" swaggertype:"object"`
+}
+
+// ProductRevision is a change of the product, recorded in the transaction of the change
+type ProductRevision struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	Action    RevisionAction `json:"action" gorm:"size:20;not null"`
+	// the changed fields of ProductSnapshot by the JSON name
+	Changes map[string]FieldChange `json:"changes" gorm:"serializer:json"`
+	// the revision restored by the revert
+	RevertedFrom *uint `json:"reverted_from"`
+	// foreignkey Product, the history of the deleted product is kept
+	ProductID uint    `json:"product_id" gorm:"index"`
+	Product   Product `gorm:"foreignkey:ProductID;constraint:OnDelete:CASCADE;" json:"-"`
+	// foreignkey User, the actor of the change, null is the scheduler
+	UserID *uint `json:"user_id" gorm:"index"`
+	User   *User `gorm:"foreignkey:UserID;constraint:OnDelete:SET NULL;" json:"user,omitempty"`
+}
+
+// ProductSnapshot is the fields of the product tracked by the revisions
+type ProductSnapshot struct {
+	Title         string        `json:"title"`
+	Description   string        `json:"description"`
+	PriceAmount   int64         `json:"price_amount"`
+	PriceCurrency string        `json:"price_currency"`
+	CategoryID    *uint         `json:"category_id"`
+	Tags          []string      `json:"tags"`
+	Status        ProductStatus `json:"status"`
+	PublishAt     *time.Time    `json:"publish_at"`
+	UnpublishAt   *time.Time    `json:"unpublish_at"`
+	ExternalID    *string       `json:"external_id"`
+}
+
+// Snapshot take the tracked fields of the product, the tags must be loaded
+func (md Product) Snapshot() ProductSnapshot {
+	tags := make([]string, 0, len(md.Tags))
+	for _, tag := range md.Tags {
+		tags = append(tags, tag.Name)
+	}
+	sort.Strings(tags)
+
+	return ProductSnapshot{
+		Title:         md.Title,
+		Description:   md.Description,
+		PriceAmount:   md.Price.Amount,
+		PriceCurrency: md.Price.Currency,
+		CategoryID:    md.CategoryID,
+		Tags:          tags,
+		Status:        md.Status,
+		PublishAt:     utcTime(md.PublishAt),
+		UnpublishAt:   utcTime(md.UnpublishAt),
+		ExternalID:    md.ExternalID,
+	}
+}
+
+// Fields is the JSON value of each tracked field
+func (md ProductSnapshot) Fields() map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	data, _ := json.Marshal(md)
+	json.Unmarshal(data, &fields)
+	return fields
+}
+
+// Diff is the fields changed from the snapshot to the other one
+func (md ProductSnapshot) Diff(other ProductSnapshot) map[string]FieldChange {
+	before, after := md.Fields(), other.Fields()
+	changes := map[string]FieldChange{}
+	for field, to := range after {
+		if from := before[field]; !bytes.Equal(from, to) {
+			changes[field] = FieldChange{From: from, To: to}
+		}
+	}
+	return changes
+}
+
+// Rewind undo the changes of the revisions, the newest first, from the snapshot
+func (md ProductSnapshot) Rewind(revisions []*ProductRevision) (ProductSnapshot, error) {
+	fields := md.Fields()
+	for _, revision := range revisions {
+		for field, change := range revision.Changes {
+			if _, ok := fields[field]; ok {
+				fields[field] = change.From
+			}
+		}
+	}
+
+	var snapshot ProductSnapshot
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return snapshot, err
+	}
+	err = json.Unmarshal(data, &snapshot)
+	return snapshot, err
+}
+
+// utcTime compare the times in the same location
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+type ProductRevisionUsecase interface {
+	// USECASE
+	History(c *fiber.Ctx) (*response.Pagination, *fiber.Error)
+	Revert(c *fiber.Ctx) (Product, *fiber.Error)
+
+	// ADMIN ROLE
+	// the history of the product, deleted or not
+	AdminHistory(c *fiber.Ctx) (*response.Pagination, *fiber.Error)
+	AdminRevert(c *fiber.Ctx) (Product, *fiber.Error)
+}
+
+type ProductRevisionRepository interface {
+	// REPOS
+	ListRevision(productID uint, param response.ParamsPagination) (*response.Pagination, *fiber.Error)
+	GetRevision(productID uint, id uint) (ProductRevision, *fiber.Error)
+	// the revisions of the product after the revision, the newest first
+	RevisionsAfter(productID uint, id uint) ([]*ProductRevision, *fiber.Error)
+	// the product with the deleted one
+	GetProductUnscoped(id uint) (Product, *fiber.Error)
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// ProductSlug is a previous slug of the product, the product page of the slug redirect to the current one
+type ProductSlug struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Slug      string    `json:"slug" gorm:"size:120;not null;uniqueIndex"`
+	// foreignkey Product
+	ProductID uint     `json:"product_id" gorm:"index"`
+	Product   *Product `gorm:"foreignkey:ProductID;constraint:OnDelete:CASCADE;" json:"-"`
+}
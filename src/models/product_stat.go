@@ -0,0 +1,130 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StatMetric is the engagement counted on the products
+type StatMetric string
+
+const (
+	// the product detail opened by a user other than the owner
+	StatView StatMetric = "views"
+	// the product shown in a page of the catalog
+	StatImpression StatMetric = "impressions"
+	// the product saved to a wishlist
+	StatFavorite StatMetric = "favorites"
+)
+
+// the longest period of the stats, in days
+const MaxStatDays = 366
+
+// ProductStat is the daily aggregate of the counts, flushed from the redis buffer
+type ProductStat struct {
+	ID uint `gorm:"primarykey" json:"-"`
+	// the UTC day
+	Day         time.Time `json:"day" gorm:"type:date;not null;uniqueIndex:idx_product_stat_day,priority:2;index"`
+	Views       int64     `json:"views" gorm:"not null;default:0"`
+	Impressions int64     `json:"impressions" gorm:"not null;default:0"`
+	Favorites   int64     `json:"favorites" gorm:"not null;default:0"`
+	// foreignkey Product, the stats of the deleted product are kept until it's purged
+	ProductID uint    `json:"-" gorm:"uniqueIndex:idx_product_stat_day,priority:1"`
+	Product   Product `gorm:"foreignkey:ProductID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+// StatTotals is the sum of the counts over the period
+type StatTotals struct {
+	Views       int64 `json:"views"`
+	Impressions int64 `json:"impressions"`
+	Favorites   int64 `json:"favorites"`
+}
+
+func (md *StatTotals) Add(stat ProductStat) {
+	md.Views += stat.Views
+	md.Impressions += stat.Impressions
+	md.Favorites += stat.Favorites
+}
+
+// ProductStats is the time series of the product, one point a day, the days without count are zero
+type ProductStats struct {
+	ProductID uint           `json:"product_id"`
+	From      string         `json:"from"`
+	To        string         `json:"to"`
+	Totals    StatTotals     `json:"totals"`
+	Series    []*ProductStat `json:"series"`
+}
+
+// TopProduct is the product with its totals over the period
+type TopProduct struct {
+	StatTotals
+	ProductID uint     `json:"product_id"`
+	Product   *Product `json:"product,omitempty" gorm:"-"`
+}
+
+// StatPeriod is the days of the stats, from and to included
+type StatPeriod struct {
+	From time.Time
+	To   time.Time
+}
+
+// Days is the number of days of the period
+func (md StatPeriod) Days() int {
+	return int(md.To.Sub(md.From).Hours()/24) + 1
+}
+
+// ParseStatPeriod read the from and to query, YYYY-MM-DD in UTC, the last 30 days by default
+func ParseStatPeriod(c *fiber.Ctx) (StatPeriod, *fiber.Error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	period := StatPeriod{From: today.AddDate(0, 0, -29), To: today}
+
+	if to := c.Query("to"); to != "" {
+		day, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			return period, fiber.NewError(fiber.StatusBadRequest, "The to date must be YYYY-MM-DD.")
+		}
+		period.To = day
+		period.From = day.AddDate(0, 0, -29)
+	}
+	if from := c.Query("from"); from != "" {
+		day, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			return period, fiber.NewError(fiber.StatusBadRequest, "The from date must be YYYY-MM-DD.")
+		}
+		period.From = day
+	}
+
+	if period.From.After(period.To) {
+		return period, fiber.NewError(fiber.StatusBadRequest, "The from date must be before the to date.")
+	}
+	if period.Days() > MaxStatDays {
+		return period, fiber.NewError(fiber.StatusBadRequest, "The period can't be longer than 366 days.")
+	}
+
+	return period, nil
+}
+
+type ProductStatUsecase interface {
+	// USECASE
+	// the daily stats of the product the user can manage
+	Stats(c *fiber.Ctx) (ProductStats, *fiber.Error)
+
+	// ADMIN ROLE
+	TopProducts(c *fiber.Ctx) ([]*TopProduct, *fiber.Error)
+
+	// BACKGROUND JOBS
+	FlushStats() error
+}
+
+type ProductStatRepository interface {
+	// FUNTIONS
+	// count the metric of the products in the redis buffer, the error is only logged
+	Track(metric StatMetric, productIDs ...uint)
+	// move the buffer to the daily aggregates, return the number of rows written
+	Flush() (int, error)
+
+	// REPOS
+	ListStat(productID uint, period StatPeriod) ([]*ProductStat, *fiber.Error)
+	TopProducts(period StatPeriod, metric StatMetric, limit int) ([]*TopProduct, *fiber.Error)
+}
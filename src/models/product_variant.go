@@ -0,0 +1,131 @@
+package models
+
+import (
+	"myapp/pkg/money"
+	"myapp/pkg/response"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type StockReason string
+
+const (
+	StockInitial    StockReason = "initial"
+	StockRestock    StockReason = "restock"
+	StockSale       StockReason = "sale"
+	StockReturn     StockReason = "return"
+	StockDamage     StockReason = "damage"
+	StockCorrection StockReason = "correction"
+)
+
+// ProductVariant is a sellable option of the product, e.g. size M and color red
+type ProductVariant struct {
+	gorm.Model
+	SKU string `json:"sku" gorm:"size:64;not null;uniqueIndex:idx_product_variant_sku,where:deleted_at IS NULL"`
+	// option name and value, e.g. {"size": "M", "color": "red"}
+	Options map[string]string `json:"options" gorm:"serializer:json"`
+	// the price override in the minor units of the product currency, nil use the price of product
+	PriceAmount *int64 `json:"price_amount"`
+	Stock       int    `json:"stock" gorm:"not null;default:0"`
+	// notify the owner when the stock reach the threshold, 0 is disabled
+	LowStockThreshold int  `json:"low_stock_threshold" gorm:"not null;default:0"`
+	IsEnable          bool `json:"is_enable" gorm:"default:true"`
+	// foreignkey Product
+	ProductID uint     `json:"product_id" gorm:"index"`
+	Product   *Product `gorm:"foreignkey:ProductID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+// EffectivePrice return the price of variant, fallback to the product price
+func (md ProductVariant) EffectivePrice(product Product) money.Money {
+	if md.PriceAmount != nil {
+		return money.New(*md.PriceAmount, product.Price.Currency)
+	}
+	return product.Price
+}
+
+// IsLowStock check the stock has reached the threshold
+func (md ProductVariant) IsLowStock() bool {
+	return md.LowStockThreshold > 0 && md.Stock <= md.LowStockThreshold
+}
+
+// CrossedLowStock check the change of the stock has just reached the threshold, so the owner is notified once
+func (md ProductVariant) CrossedLowStock(change int) bool {
+	wasLow := md.LowStockThreshold > 0 && md.Stock-change <= md.LowStockThreshold
+	return md.IsLowStock() && !wasLow
+}
+
+// StockMovement is the history of stock adjustments
+type StockMovement struct {
+	gorm.Model
+	Change     int         `json:"change"`
+	StockAfter int         `json:"stock_after"`
+	Reason     StockReason `json:"reason" gorm:"size:20"`
+	Note       string      `json:"note" gorm:"default:null"`
+	// foreignkey ProductVariant
+	ProductVariantID uint            `json:"product_variant_id" gorm:"index"`
+	ProductVariant   *ProductVariant `gorm:"foreignkey:ProductVariantID;constraint:OnDelete:CASCADE;" json:"-"`
+	// foreignkey User, who made the adjustment
+	UserID *uint `json:"user_id"`
+	User   *User `gorm:"foreignkey:UserID;constraint:OnDelete:SET NULL;" json:"-"`
+}
+
+type ProductVariantInput struct {
+	SKU     string            `json:"sku" validate:"required,max=64"`
+	Options map[string]string `json:"options"`
+	// the decimal price in the product currency, null use the price of product
+	Price *money.Decimal `json:"price" swaggertype:"string"`
+	// initial stock, only for create. use the stock adjustment to change it
+	Stock             int   `json:"stock" validate:"gte=0"`
+	LowStockThreshold int   `json:"low_stock_threshold" validate:"gte=0"`
+	IsEnable          *bool `json:"is_enable"`
+}
+
+func (f *ProductVariantInput) Sanitize() {
+	f.SKU = strings.ToUpper(strings.TrimSpace(f.SKU))
+
+	options := make(map[string]string, len(f.Options))
+	for key, value := range f.Options {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		options[key] = strings.TrimSpace(value)
+	}
+	f.Options = options
+}
+
+type StockAdjustmentInput struct {
+	// positive to add, negative to remove
+	Change int    `json:"change" validate:"required"`
+	Reason string `json:"reason" validate:"required,oneof=restock sale return damage correction"`
+	Note   string `json:"note" validate:"max=255"`
+}
+
+type ProductVariantUsecase interface {
+	// USECASE
+	ListVariant(c *fiber.Ctx) ([]*ProductVariant, *fiber.Error)
+	GetVariant(c *fiber.Ctx) (ProductVariant, *fiber.Error)
+	Create(c *fiber.Ctx, payload ProductVariantInput) (ProductVariant, *fiber.Error)
+	Update(c *fiber.Ctx, payload ProductVariantInput) (ProductVariant, *fiber.Error)
+	Delete(c *fiber.Ctx) *fiber.Error
+
+	AdjustStock(c *fiber.Ctx, payload StockAdjustmentInput) (StockMovement, *fiber.Error)
+	ListStockMovement(c *fiber.Ctx) (*response.Pagination, *fiber.Error)
+}
+
+type ProductVariantRepository interface {
+	// FUNTIONS
+	SendLowStockEmail(owner User, product Product, obj ProductVariant) error
+
+	// REPOS
+	ListVariant(productID uint) ([]*ProductVariant, *fiber.Error)
+	GetVariant(productID uint, id uint) (ProductVariant, *fiber.Error)
+	Create(obj ProductVariant, userID uint) (ProductVariant, *fiber.Error)
+	Update(obj ProductVariant) (ProductVariant, *fiber.Error)
+	Delete(obj ProductVariant) *fiber.Error
+
+	AdjustStock(obj ProductVariant, movement StockMovement) (ProductVariant, StockMovement, *fiber.Error)
+	ListStockMovement(variantID uint, param response.ParamsPagination) (*response.Pagination, *fiber.Error)
+}
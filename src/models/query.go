@@ -0,0 +1,165 @@
+package models
+
+import (
+	qs "myapp/pkg/queryspec"
+)
+
+// the whitelist of the filter and sort query of the list endpoints,
+// a field missing here can't be used in ?filter[...] or ?sort=
+
+var ProductQuery = qs.Spec{
+	Fields: map[string]qs.Field{
+		"id":          {Kind: qs.Int, Operators: qs.Equality, Sortable: true},
+		"title":       {Kind: qs.String, Operators: qs.Text, Sortable: true},
+		"price":       {Column: "price_amount", Kind: qs.Int, Operators: qs.Comparable, Sortable: true}, // minor units
+		"currency":    {Column: "price_currency", Kind: qs.String, Operators: qs.Equality},
+		"rating":      {Column: "rating_average", Kind: qs.Number, Operators: qs.Comparable, Sortable: true},
+		"reviews":     {Column: "rating_count", Kind: qs.Int, Operators: qs.Comparable, Sortable: true},
+		"is_enable":   {Kind: qs.Bool, Operators: []qs.Operator{qs.Eq}},
+		"status":      {Kind: qs.String, Operators: qs.Equality},
+		"publish_at":  {Kind: qs.Time, Operators: []qs.Operator{qs.Gt, qs.Gte, qs.Lt, qs.Lte, qs.Null}},
+		"hidden_at":   {Kind: qs.Time, Operators: []qs.Operator{qs.Gt, qs.Gte, qs.Lt, qs.Lte, qs.Null}},
+		"user_id":     {Kind: qs.Int, Operators: qs.Equality},
+		"category_id": {Kind: qs.Int, Operators: []qs.Operator{qs.Eq, qs.Ne, qs.In, qs.Null}},
+		"created_at":  {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+		"updated_at":  {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+		// the full-text search rank, see ProductRepository
+		"rank": {Sortable: true},
+	},
+	DefaultSort: "-id",
+}
+
+var UserQuery = qs.Spec{
+	Fields: map[string]qs.Field{
+		"id":            {Kind: qs.Int, Operators: qs.Equality, Sortable: true},
+		"first_name":    {Kind: qs.String, Operators: qs.Text, Sortable: true},
+		"last_name":     {Kind: qs.String, Operators: qs.Text, Sortable: true},
+		"username":      {Kind: qs.String, Operators: qs.Text, Sortable: true},
+		"email":         {Kind: qs.String, Operators: qs.Text, Sortable: true},
+		"verified":      {Kind: qs.Bool, Operators: []qs.Operator{qs.Eq}},
+		"is_staff":      {Kind: qs.Bool, Operators: []qs.Operator{qs.Eq}},
+		"is_superuser":  {Kind: qs.Bool, Operators: []qs.Operator{qs.Eq}},
+		"created_at":    {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+		"last_login_at": {Kind: qs.Time, Operators: []qs.Operator{qs.Gt, qs.Gte, qs.Lt, qs.Lte, qs.Null}, Sortable: true},
+	},
+	DefaultSort: "-id",
+}
+
+var MyDriveQuery = qs.Spec{
+	Fields: map[string]qs.Field{
+		"name":       {Kind: qs.String, Operators: qs.Text, Sortable: true},
+		"file_type":  {Kind: qs.String, Operators: qs.Equality, Sortable: true},
+		"created_at": {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+		"updated_at": {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+	},
+	DefaultSort: "-created_at",
+}
+
+var AccountDeletionQuery = qs.Spec{
+	Fields: map[string]qs.Field{
+		"id":           {Kind: qs.Int, Sortable: true},
+		"email":        {Kind: qs.String, Operators: qs.Text, Sortable: true},
+		"scheduled_at": {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+		"created_at":   {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+	},
+	DefaultSort: "scheduled_at",
+}
+
+var InvitationQuery = qs.Spec{
+	Fields: map[string]qs.Field{
+		"id":         {Kind: qs.Int, Sortable: true},
+		"email":      {Kind: qs.String, Operators: qs.Text, Sortable: true},
+		"role":       {Kind: qs.String, Operators: qs.Equality},
+		"status":     {Kind: qs.String, Operators: qs.Equality, Sortable: true},
+		"expired_at": {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+		"created_at": {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+	},
+	DefaultSort: "-id",
+}
+
+var OrganizationMemberQuery = qs.Spec{
+	Fields: map[string]qs.Field{
+		"id":         {Kind: qs.Int, Sortable: true},
+		"role":       {Kind: qs.String, Operators: qs.Equality, Sortable: true},
+		"created_at": {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+	},
+	DefaultSort: "-id",
+}
+
+var OrganizationInvitationQuery = qs.Spec{
+	Fields: map[string]qs.Field{
+		"id":         {Kind: qs.Int, Sortable: true},
+		"email":      {Kind: qs.String, Operators: qs.Text, Sortable: true},
+		"role":       {Kind: qs.String, Operators: qs.Equality},
+		"status":     {Kind: qs.String, Operators: qs.Equality, Sortable: true},
+		"expired_at": {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+		"created_at": {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+	},
+	DefaultSort: "-id",
+}
+
+var ProductReviewQuery = qs.Spec{
+	Fields: map[string]qs.Field{
+		"id":         {Kind: qs.Int, Sortable: true},
+		"rating":     {Kind: qs.Int, Operators: qs.Comparable, Sortable: true},
+		"status":     {Kind: qs.String, Operators: qs.Equality},
+		"product_id": {Kind: qs.Int, Operators: qs.Equality},
+		"user_id":    {Kind: qs.Int, Operators: qs.Equality},
+		"created_at": {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+	},
+	DefaultSort: "-created_at",
+}
+
+var ProductReportQuery = qs.Spec{
+	Fields: map[string]qs.Field{
+		"id":         {Kind: qs.Int, Sortable: true},
+		"status":     {Kind: qs.String, Operators: qs.Equality},
+		"reason":     {Kind: qs.String, Operators: qs.Equality},
+		"action":     {Kind: qs.String, Operators: qs.Equality},
+		"product_id": {Kind: qs.Int, Operators: qs.Equality},
+		"user_id":    {Kind: qs.Int, Operators: qs.Equality},
+		"created_at": {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+	},
+	// the oldest report first in the queue
+	DefaultSort: "created_at",
+}
+
+var ProductTrashQuery = qs.Spec{
+	Fields: map[string]qs.Field{
+		"id":         {Kind: qs.Int, Operators: qs.Equality, Sortable: true},
+		"title":      {Kind: qs.String, Operators: qs.Text, Sortable: true},
+		"user_id":    {Kind: qs.Int, Operators: qs.Equality},
+		"deleted_at": {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+	},
+	DefaultSort: "-deleted_at",
+}
+
+var ProductRevisionQuery = qs.Spec{
+	Fields: map[string]qs.Field{
+		"id":         {Kind: qs.Int, Sortable: true},
+		"action":     {Kind: qs.String, Operators: qs.Equality},
+		"user_id":    {Kind: qs.Int, Operators: qs.Equality},
+		"created_at": {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+	},
+	DefaultSort: "-id",
+}
+
+var StockMovementQuery = qs.Spec{
+	Fields: map[string]qs.Field{
+		"id":         {Kind: qs.Int, Sortable: true},
+		"change":     {Kind: qs.Int, Operators: qs.Comparable, Sortable: true},
+		"reason":     {Kind: qs.String, Operators: qs.Equality},
+		"created_at": {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+	},
+	DefaultSort: "-id",
+}
+
+var OrderQuery = qs.Spec{
+	Fields: map[string]qs.Field{
+		"id":         {Kind: qs.Int, Sortable: true},
+		"status":     {Kind: qs.String, Operators: qs.Equality},
+		"total":      {Column: "total_amount", Kind: qs.Int, Operators: qs.Comparable, Sortable: true}, // minor units
+		"created_at": {Kind: qs.Time, Operators: qs.Range, Sortable: true},
+	},
+	DefaultSort: "-id",
+}
@@ -3,6 +3,8 @@ package models
 type AccessDetails struct {
 	TokenUuid string
 	UserID    uint
+	// active organization, 0 is the personal workspace
+	OrganizationID uint
 }
 
 type TokenDetails struct {
@@ -64,7 +64,10 @@ func (user *User) AfterCreate(tx *gorm.DB) (err error) {
 		tx.Model(user).Updates(User{IsSuperuser: true, IsStaff: true})
 		tx.Model(user.UserProfile).Update("role", "admin")
 	}
-	tx.Model(user.UserProfile).Update("role", "user")
+	// keep the role assigned before create (i.e. from invitation)
+	if user.UserProfile.Role == "" {
+		tx.Model(user.UserProfile).Update("role", "user")
+	}
 	return
 }
 
@@ -76,6 +79,11 @@ func (u *User) BeforeDelete(tx *gorm.DB) (err error) {
 	return
 }
 
+// IsLocked check the inactive or suspended user, who can't sign in or use their tokens
+func (md User) IsLocked() bool {
+	return md.UserProfile.StatusID == StatusInactive || md.UserProfile.StatusID == StatusSuspended
+}
+
 func (user *User) ValidatePassword(password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 }
@@ -90,7 +98,7 @@ func (user *User) HashPassword(password string) (string, error) {
 
 type UserUsecase interface {
 	// USECASE
-	Register(ctx context.Context, payload RegisterInput) *fiber.Error
+	Register(ctx context.Context, payload RegisterInput) (User, *fiber.Error)
 	Login(ctx context.Context, payload LoginInput) (Token, *fiber.Error)
 	RefreshToken(ctx context.Context, payload RefreshTokenInput) (Token, *fiber.Error)
 	VerificationEmail(ctx context.Context, code string) *fiber.Error
@@ -105,20 +113,31 @@ type UserUsecase interface {
 	// Delete(ctx context.Context, md User) *fiber.Error
 	UploadPhotoProfile(c *fiber.Ctx, md User) *fiber.Error
 	RequestDeleteAccount(c *fiber.Ctx, md User) *fiber.Error
-	DeleteAccount(c *fiber.Ctx, otp string) *fiber.Error
+	DeleteAccount(c *fiber.Ctx, otp string) (AccountDeletion, *fiber.Error)
+	CancelDeleteAccountByCode(ctx context.Context, code string) *fiber.Error
+	PurgeDeletedAccounts() error
 	ListUser(c *fiber.Ctx) (*response.Pagination, []*User, *fiber.Error)
 
 	// ADMIN ROLE
+	GetUser(c *fiber.Ctx, id uint) (User, *fiber.Error)
+	CreateUser(c *fiber.Ctx, payload AdminCreateUserInput) (User, *fiber.Error)
+	UpdateUser(c *fiber.Ctx, id uint, payload AdminUpdateUserInput) (User, *fiber.Error)
+	UpdateUserStatus(c *fiber.Ctx, id uint, statusID uint) (User, *fiber.Error)
+	UpdateUserStaff(c *fiber.Ctx, id uint, isStaff bool) (User, *fiber.Error)
+	VerifyUser(c *fiber.Ctx, id uint) (User, *fiber.Error)
+	ResetUserPassword(c *fiber.Ctx, id uint) *fiber.Error
 	RestoreUser(c *fiber.Ctx, email string) *fiber.Error
 	DeleteUser(c *fiber.Ctx, id uint) *fiber.Error
 	PermanentDeleteUser(c *fiber.Ctx, id uint) *fiber.Error
+	ListPendingDeletion(c *fiber.Ctx) (*response.Pagination, *fiber.Error)
 }
 
 type UserRepository interface {
 	// FUNTIONS
 	DeleteAuthRedis(givenUuid string) (int64, error)
-	GeneratePairToken(userID uint) (Token, error)
+	GeneratePairToken(userID uint, organizationID uint) (Token, error)
 	SendVerificationEmail(obj User, code string) error
+	SendDeletionEmail(obj User, deletion AccountDeletion, code string) error
 
 	// REPOS
 	Register(obj User) *fiber.Error
@@ -143,8 +162,17 @@ type UserRepository interface {
 	FindUserById(id uint) (User, *fiber.Error)
 	ListUser(param response.ParamsPagination) (*response.Pagination, []*User, *fiber.Error)
 
+	ScheduleDeletion(obj AccountDeletion) (AccountDeletion, *fiber.Error)
+	FindPendingDeletion(userID uint) (AccountDeletion, *fiber.Error)
+	FindDeletionByCode(code string) (AccountDeletion, *fiber.Error)
+	UpdateDeletion(obj AccountDeletion) *fiber.Error
+	ListDueDeletion(now time.Time) ([]*AccountDeletion, *fiber.Error)
+	ListPendingDeletion(param response.ParamsPagination) (*response.Pagination, *fiber.Error)
+
 	// ADMIN ROLE
 	FindDeletedUserByEmail(email string) (User, *fiber.Error)
+	// the user, deleted or not
+	FindUserUnscoped(id uint) (User, *fiber.Error)
 	RestoreUser(id uint) *fiber.Error
 	Delete(obj User) *fiber.Error
 	PermanentDelete(obj User) *fiber.Error
@@ -28,8 +28,10 @@ type UserProfile struct {
 func (md UserProfile) MarshalJSON() ([]byte, error) {
 	type Alias UserProfile
 	var photo *string = md.Photo
+	// the copy keep the path of the profile
 	if photo != nil {
-		*photo = fmt.Sprintf("%s/%s", os.Getenv("CLIENT_ORIGIN"), *md.Photo)
+		url := fmt.Sprintf("%s/%s", os.Getenv("CLIENT_ORIGIN"), *md.Photo)
+		photo = &url
 	}
 
 	aux := struct {
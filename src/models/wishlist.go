@@ -0,0 +1,116 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// the maximum number of products in a wishlist
+const MaxWishlistItems = 500
+
+// Wishlist is a named list of products, the default list is the favorites of the user
+type Wishlist struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name" gorm:"size:100;not null"`
+	// the favorites, created on the first favorite and can't be deleted
+	IsDefault bool `json:"is_default" gorm:"not null;default:false"`
+	// the secret of the public link, nil is private
+	ShareToken *string `json:"share_token" gorm:"size:64;uniqueIndex"`
+	// foreignkey User, the owner
+	UserID uint            `json:"user_id" gorm:"index;uniqueIndex:idx_wishlist_default,where:is_default"`
+	User   *User           `gorm:"foreignkey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+	Items  []*WishlistItem `gorm:"foreignkey:WishlistID" json:"items,omitempty"`
+	// filled by the list of wishlists
+	ItemCount int64 `gorm:"->;-:migration" json:"item_count"`
+}
+
+func (md Wishlist) MarshalJSON() ([]byte, error) {
+	type Alias Wishlist
+	var shareURL *string
+	if md.ShareToken != nil {
+		url := fmt.Sprintf("%s/api/v1/wishlists/shared/%s", os.Getenv("CLIENT_ORIGIN"), *md.ShareToken)
+		shareURL = &url
+	}
+
+	aux := struct {
+		Alias
+		ShareURL *string `json:"share_url"`
+	}{
+		Alias:    (Alias)(md),
+		ShareURL: shareURL,
+	}
+	return json.Marshal(aux)
+}
+
+// WishlistItem is a product saved in the wishlist, a product is saved once per list
+type WishlistItem struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	// foreignkey Wishlist
+	WishlistID uint      `json:"wishlist_id" gorm:"not null;uniqueIndex:idx_wishlist_item"`
+	Wishlist   *Wishlist `gorm:"foreignkey:WishlistID;constraint:OnDelete:CASCADE;" json:"-"`
+	// foreignkey Product
+	ProductID uint     `json:"product_id" gorm:"not null;uniqueIndex:idx_wishlist_item;index"`
+	Product   *Product `gorm:"foreignkey:ProductID;constraint:OnDelete:CASCADE;" json:"product,omitempty"`
+}
+
+// FavoriteStat is the favorites of a product
+type FavoriteStat struct {
+	ProductID uint
+	// number of users with the product in their favorites
+	Total int64
+	// the product is in the favorites of the current user
+	Favorited bool
+}
+
+type WishlistInput struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+	// true create the public link, false revoke it
+	IsPublic bool `json:"is_public"`
+}
+
+func (f *WishlistInput) Sanitize() {
+	f.Name = strings.TrimSpace(f.Name)
+}
+
+type WishlistItemInput struct {
+	ProductID uint `json:"product_id" validate:"required"`
+}
+
+type WishlistUsecase interface {
+	// USECASE
+	ListWishlist(c *fiber.Ctx) ([]*Wishlist, *fiber.Error)
+	GetWishlist(c *fiber.Ctx) (Wishlist, *fiber.Error)
+	GetShared(c *fiber.Ctx) (Wishlist, *fiber.Error)
+	Create(c *fiber.Ctx, payload WishlistInput) (Wishlist, *fiber.Error)
+	Update(c *fiber.Ctx, payload WishlistInput) (Wishlist, *fiber.Error)
+	Delete(c *fiber.Ctx) *fiber.Error
+	AddItem(c *fiber.Ctx, payload WishlistItemInput) (Wishlist, *fiber.Error)
+	RemoveItem(c *fiber.Ctx) *fiber.Error
+	Favorite(c *fiber.Ctx) *fiber.Error
+	Unfavorite(c *fiber.Ctx) *fiber.Error
+}
+
+type WishlistRepository interface {
+	// FUNTIONS
+	FavoriteStats(userID uint, productIDs []uint) (map[uint]FavoriteStat, *fiber.Error)
+
+	// REPOS
+	ListWishlist(userID uint) ([]*Wishlist, *fiber.Error)
+	GetWishlist(userID uint, id uint) (Wishlist, *fiber.Error)
+	GetShared(token string) (Wishlist, *fiber.Error)
+	DefaultWishlist(userID uint) (Wishlist, *fiber.Error)
+	Create(obj Wishlist) (Wishlist, *fiber.Error)
+	Update(obj Wishlist) (Wishlist, *fiber.Error)
+	Delete(obj Wishlist) *fiber.Error
+	// added is false when the product is already in the wishlist
+	AddItem(obj Wishlist, productID uint) (bool, *fiber.Error)
+	RemoveItem(obj Wishlist, productID uint) *fiber.Error
+}
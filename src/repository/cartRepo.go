@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"myapp/pkg/utils"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CartRepository struct {
+	DB *gorm.DB
+}
+
+// NewCartRepository will create an object that represent the models.CartRepository interface
+func NewCartRepository(Conn *gorm.DB) models.CartRepository {
+	return &CartRepository{Conn}
+}
+
+// ListItem implements models.CartRepository.
+func (r *CartRepository) ListItem(userID uint) ([]*models.CartItem, *fiber.Error) {
+	var data []*models.CartItem
+	err := r.DB.Preload("Product.Images", orderImages).Preload("Product.Variants").Preload("ProductVariant").
+		Where("user_id = ?", userID).Order("id asc").Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
+
+// GetItem implements models.CartRepository.
+func (r *CartRepository) GetItem(userID uint, id uint) (models.CartItem, *fiber.Error) {
+	var obj models.CartItem
+	result := r.DB.Preload("Product.Variants").Preload("ProductVariant").Where("user_id = ?", userID).First(&obj, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// AddItem implements models.CartRepository.
+func (r *CartRepository) AddItem(obj models.CartItem) (models.CartItem, *fiber.Error) {
+	var errD *fiber.Error
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		// lock the cart of the user, so the same item isn't created twice
+		var ids []uint
+		if err := tx.Model(&models.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", obj.UserID).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+
+		var current models.CartItem
+		db := tx.Where("user_id = ? AND product_id = ?", obj.UserID, obj.ProductID)
+		if obj.ProductVariantID != nil {
+			db = db.Where("product_variant_id = ?", *obj.ProductVariantID)
+		} else {
+			db = db.Where("product_variant_id IS NULL")
+		}
+		if result := db.Limit(1).Find(&current); result.Error != nil {
+			return result.Error
+		}
+
+		if current.ID == 0 {
+			var count int64
+			if err := tx.Model(&models.CartItem{}).Where("user_id = ?", obj.UserID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count >= models.MaxCartItems {
+				errD = fiber.NewError(422, "The cart is full.")
+				return errD
+			}
+			return tx.Omit("User", "Product", "ProductVariant").Create(&obj).Error
+		}
+
+		// the same product and variant add up
+		obj.ID = current.ID
+		obj.CreatedAt = current.CreatedAt
+		obj.Quantity += current.Quantity
+		if obj.Quantity > models.MaxCartQuantity {
+			errD = fiber.NewError(422, "The quantity can't be more than 99.")
+			return errD
+		}
+		return tx.Omit("User", "Product", "ProductVariant").Save(&obj).Error
+	})
+	if errD != nil {
+		return obj, errD
+	}
+	if err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// UpdateItem implements models.CartRepository.
+func (r *CartRepository) UpdateItem(obj models.CartItem) (models.CartItem, *fiber.Error) {
+	if err := r.DB.Model(&obj).UpdateColumn("quantity", obj.Quantity).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// RemoveItem implements models.CartRepository.
+func (r *CartRepository) RemoveItem(obj models.CartItem) *fiber.Error {
+	if err := r.DB.Delete(&obj).Error; err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	return nil
+}
+
+// Clear implements models.CartRepository.
+func (r *CartRepository) Clear(userID uint) *fiber.Error {
+	if err := r.DB.Where("user_id = ?", userID).Delete(&models.CartItem{}).Error; err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	return nil
+}
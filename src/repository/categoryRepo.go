@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"myapp/pkg/configs"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// the product count is refreshed at least every 10 minutes
+const categoryCountTTL = 10 * time.Minute
+
+type CategoryRepository struct {
+	DB *gorm.DB
+}
+
+// NewCategoryRepository will create an object that represent the models.CategoryRepository interface
+func NewCategoryRepository(Conn *gorm.DB) models.CategoryRepository {
+	return &CategoryRepository{Conn}
+}
+
+func categoryCountKey(organizationID uint) string {
+	return fmt.Sprintf("category_count:%d", organizationID)
+}
+
+// CountProduct implements models.CategoryRepository.
+func (r *CategoryRepository) CountProduct(organizationID uint) (map[uint]int64, *fiber.Error) {
+	counts := map[uint]int64{}
+	key := categoryCountKey(organizationID)
+
+	ctxTodo := context.TODO()
+	// get from cache first
+	if cached, err := configs.RedisClient.Get(ctxTodo, key).Result(); err == nil {
+		if err := json.Unmarshal([]byte(cached), &counts); err == nil {
+			return counts, nil
+		}
+	}
+
+	var rows []struct {
+		CategoryID uint
+		Total      int64
+	}
+	err := r.DB.Model(&models.Product{}).Select("category_id, COUNT(*) AS total").
+		Scopes(utils.OrganizationThis(organizationID), models.LiveProduct(time.Now())).
+		Where("category_id IS NOT NULL").
+		Group("category_id").Scan(&rows).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Total
+	}
+
+	if data, err := json.Marshal(counts); err == nil {
+		configs.RedisClient.Set(ctxTodo, key, data, categoryCountTTL)
+	}
+
+	return counts, nil
+}
+
+// ClearCountProduct implements models.CategoryRepository.
+func (*CategoryRepository) ClearCountProduct(organizationID *uint) {
+	var id uint
+	if organizationID != nil {
+		id = *organizationID
+	}
+
+	ctxTodo := context.TODO()
+	configs.RedisClient.Del(ctxTodo, categoryCountKey(id))
+}
+
+// ListCategory implements models.CategoryRepository.
+func (r *CategoryRepository) ListCategory() ([]*models.Category, *fiber.Error) {
+	var data []*models.Category
+	if err := r.DB.Order("name asc").Find(&data).Error; err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
+
+// Get implements models.CategoryRepository.
+func (r *CategoryRepository) Get(id uint) (models.Category, *fiber.Error) {
+	var obj models.Category
+	result := r.DB.First(&obj, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// DescendantIDs implements models.CategoryRepository.
+func (r *CategoryRepository) DescendantIDs(id uint) ([]uint, *fiber.Error) {
+	var ids []uint
+
+	// walk the tree with recursive query, the result include the category itself
+	err := r.DB.Raw(`WITH RECURSIVE tree AS (
+			SELECT id FROM categories WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL
+		)
+		SELECT id FROM tree`, id).Scan(&ids).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return ids, nil
+}
+
+// Create implements models.CategoryRepository.
+func (r *CategoryRepository) Create(obj models.Category) (models.Category, *fiber.Error) {
+	if err := r.DB.Omit("Parent", "Children").Create(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// Update implements models.CategoryRepository.
+func (r *CategoryRepository) Update(obj models.Category) (models.Category, *fiber.Error) {
+	if err := r.DB.Omit("Parent", "Children").Save(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// Delete implements models.CategoryRepository.
+func (r *CategoryRepository) Delete(obj models.Category) *fiber.Error {
+	if err := r.DB.Delete(&obj).Error; err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	return nil
+}
+
+// HasProduct implements models.CategoryRepository.
+func (r *CategoryRepository) HasProduct(id uint) (bool, *fiber.Error) {
+	var count int64
+	if err := r.DB.Model(&models.Product{}).Where("category_id = ?", id).Count(&count).Error; err != nil {
+		return false, fiber.NewError(500, err.Error())
+	}
+
+	return count > 0, nil
+}
+
+// ListTag implements models.CategoryRepository.
+func (r *CategoryRepository) ListTag(search string, limit int) ([]*models.Tag, *fiber.Error) {
+	var data []*models.Tag
+
+	db := r.DB.Order("name asc").Limit(limit)
+	if search != "" {
+		db = db.Where("name ILIKE ?", "%"+search+"%")
+	}
+
+	if err := db.Find(&data).Error; err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
+
+// FirstOrCreateTag implements models.CategoryRepository.
+func (r *CategoryRepository) FirstOrCreateTag(names []string) ([]*models.Tag, *fiber.Error) {
+	var data []*models.Tag
+	if len(names) == 0 {
+		return data, nil
+	}
+
+	// create the new tags, the existing tags are skipped
+	tags := make([]models.Tag, len(names))
+	for i, name := range names {
+		tags[i].Name = name
+	}
+	err := r.DB.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+		Create(&tags).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	if err := r.DB.Where("name IN ?", names).Find(&data).Error; err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
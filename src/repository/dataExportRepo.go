@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"myapp/pkg/configs"
+	"myapp/pkg/helpers"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type DataExportRepository struct {
+	DB *gorm.DB
+}
+
+// NewDataExportRepository will create an object that represent the models.DataExportRepository interface
+func NewDataExportRepository(Conn *gorm.DB) models.DataExportRepository {
+	return &DataExportRepository{Conn}
+}
+
+// SendExportEmail implements models.DataExportRepository.
+func (*DataExportRepository) SendExportEmail(user models.User, obj models.DataExport, code string) error {
+	var accountName = user.FirstName
+	if accountName == "" {
+		accountName = user.Email
+	}
+
+	siteData, _ := configs.GetSiteData(".")
+	emailData := helpers.EmailData{
+		URL:          siteData.ClientOrigin + "/api/v1/accounts/export/" + code,
+		FirstName:    accountName,
+		Subject:      "Your data export is ready",
+		Message:      "This download link is valid until " + obj.ExpiredAt.UTC().Format("02 January 2006 15:04 MST") + ".",
+		TypeOfAction: "Data Export",
+		SiteData:     siteData,
+	}
+
+	// send email with goroutine
+	go helpers.SendEmail(user, &emailData, "data_export.html")
+
+	return nil
+}
+
+// ListExport implements models.DataExportRepository.
+func (r *DataExportRepository) ListExport(userID uint) ([]*models.DataExport, *fiber.Error) {
+	var data []*models.DataExport
+
+	err := r.DB.Where("user_id = ?", userID).Order("id desc").Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
+
+// FindActive implements models.DataExportRepository.
+func (r *DataExportRepository) FindActive(userID uint) (models.DataExport, *fiber.Error) {
+	var obj models.DataExport
+
+	result := r.DB.First(&obj, "user_id = ? AND status IN ?", userID,
+		[]models.ExportStatus{models.ExportPending, models.ExportProcessing})
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// FindByCode implements models.DataExportRepository.
+func (r *DataExportRepository) FindByCode(code string) (models.DataExport, *fiber.Error) {
+	var obj models.DataExport
+
+	result := r.DB.First(&obj, "code = ?", utils.Encode(code))
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, "Invalid download link or the export doesn't exists.")
+	}
+	return obj, nil
+}
+
+// ListExpired implements models.DataExportRepository.
+func (r *DataExportRepository) ListExpired(now time.Time) ([]*models.DataExport, *fiber.Error) {
+	var data []*models.DataExport
+
+	err := r.DB.Where("status = ? AND expired_at <= ?", models.ExportReady, now).Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
+
+// Create implements models.DataExportRepository.
+func (r *DataExportRepository) Create(obj models.DataExport) (models.DataExport, *fiber.Error) {
+	if err := r.DB.Create(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// Update implements models.DataExportRepository.
+func (r *DataExportRepository) Update(obj models.DataExport) (models.DataExport, *fiber.Error) {
+	if err := r.DB.Omit("User").Save(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// CollectData implements models.DataExportRepository.
+func (r *DataExportRepository) CollectData(userID uint) (models.ExportData, *fiber.Error) {
+	var data models.ExportData
+
+	result := r.DB.Preload("UserProfile.Status").Find(&data.User, userID)
+	if result.RowsAffected == 0 {
+		return data, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+
+	if err := r.DB.Order("id").Preload("Images").Where("user_id = ?", userID).Find(&data.Products).Error; err != nil {
+		return data, fiber.NewError(500, err.Error())
+	}
+	if err := r.DB.Order("id").Where("user_id = ?", userID).Find(&data.Drives).Error; err != nil {
+		return data, fiber.NewError(500, err.Error())
+	}
+
+	// login and security history
+	if err := r.DB.Order("id").Where("user_id = ?", userID).Find(&data.Activity.Logins).Error; err != nil {
+		return data, fiber.NewError(500, err.Error())
+	}
+	if err := r.DB.Order("id").Where("user_id = ?", userID).Find(&data.Activity.Passkeys).Error; err != nil {
+		return data, fiber.NewError(500, err.Error())
+	}
+	if err := r.DB.Order("id").Where("invited_by_id = ?", userID).Find(&data.Activity.Invitations).Error; err != nil {
+		return data, fiber.NewError(500, err.Error())
+	}
+	if err := r.DB.Order("id").Where("user_id = ?", userID).Find(&data.Activity.Deletions).Error; err != nil {
+		return data, fiber.NewError(500, err.Error())
+	}
+
+	data.Activity.LastLoginAt = data.User.LastLoginAt
+	data.Activity.LastLoginIp = data.User.LastLoginIp
+	data.Activity.VerifiedAt = data.User.VerifiedAt
+
+	return data, nil
+}
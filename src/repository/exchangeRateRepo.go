@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"myapp/pkg/utils"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type ExchangeRateRepository struct {
+	DB *gorm.DB
+}
+
+// NewExchangeRateRepository will create an object that represent the models.ExchangeRateRepository interface
+func NewExchangeRateRepository(Conn *gorm.DB) models.ExchangeRateRepository {
+	return &ExchangeRateRepository{Conn}
+}
+
+// ListRate implements models.ExchangeRateRepository.
+func (r *ExchangeRateRepository) ListRate() ([]*models.ExchangeRate, *fiber.Error) {
+	var data []*models.ExchangeRate
+	if err := r.DB.Order("currency asc").Find(&data).Error; err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
+
+// Get implements models.ExchangeRateRepository.
+func (r *ExchangeRateRepository) Get(currency string) (models.ExchangeRate, *fiber.Error) {
+	var obj models.ExchangeRate
+	result := r.DB.Where("currency = ?", currency).First(&obj)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// Save implements models.ExchangeRateRepository.
+func (r *ExchangeRateRepository) Save(obj models.ExchangeRate) (models.ExchangeRate, *fiber.Error) {
+	if err := r.DB.Save(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// Delete implements models.ExchangeRateRepository.
+func (r *ExchangeRateRepository) Delete(obj models.ExchangeRate) *fiber.Error {
+	if err := r.DB.Delete(&obj).Error; err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	return nil
+}
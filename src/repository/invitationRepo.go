@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"myapp/pkg/configs"
+	"myapp/pkg/helpers"
+	"myapp/pkg/response"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type InvitationRepository struct {
+	DB *gorm.DB
+}
+
+// NewInvitationRepository will create an object that represent the models.InvitationRepository interface
+func NewInvitationRepository(Conn *gorm.DB) models.InvitationRepository {
+	return &InvitationRepository{Conn}
+}
+
+// SendInvitationEmail implements models.InvitationRepository.
+func (*InvitationRepository) SendInvitationEmail(obj models.Invitation, code string) error {
+	siteData, _ := configs.GetSiteData(".")
+	emailData := helpers.EmailData{
+		URL:          siteData.ClientOrigin + "/register?invitation_code=" + code,
+		FirstName:    obj.Email,
+		Subject:      "You are invited to join " + siteData.AppName,
+		Message:      "This invitation is valid until " + obj.ExpiredAt.Format("02 January 2006 15:04 MST") + ".",
+		TypeOfAction: "Invitation",
+		SiteData:     siteData,
+	}
+
+	// send email with goroutine
+	go helpers.SendEmail(models.User{Email: obj.Email}, &emailData, "invitation.html")
+
+	return nil
+}
+
+// MarkUsed implements models.InvitationRepository.
+func (r *InvitationRepository) MarkUsed(obj models.Invitation) *fiber.Error {
+	// increase the counter atomically, so the invitation can't be used more than max_uses
+	result := r.DB.Model(&obj).
+		Where("used_count < max_uses").
+		Updates(map[string]interface{}{
+			"used_count": gorm.Expr("used_count + 1"),
+			"status": gorm.Expr("CASE WHEN used_count + 1 >= max_uses THEN ? ELSE status END",
+				models.InvitationAccepted),
+		})
+	if result.Error != nil {
+		return fiber.NewError(500, result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return fiber.NewError(422, "Invitation has been used.")
+	}
+
+	return nil
+}
+
+// Update implements models.InvitationRepository.
+func (r *InvitationRepository) Update(obj models.Invitation) (models.Invitation, *fiber.Error) {
+	err := r.DB.Omit("InvitedBy").Save(&obj).Error
+	if err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// Create implements models.InvitationRepository.
+func (r *InvitationRepository) Create(obj models.Invitation) (models.Invitation, *fiber.Error) {
+	result := r.DB.Omit("InvitedBy").Create(&obj)
+	if result.Error != nil {
+		return obj, fiber.NewError(500, result.Error.Error())
+	}
+
+	return obj, nil
+}
+
+// FindByCode implements models.InvitationRepository.
+func (r *InvitationRepository) FindByCode(code string) (models.Invitation, *fiber.Error) {
+	var obj models.Invitation
+	result := r.DB.First(&obj, "code = ?", utils.Encode(code))
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, "Invalid invitation code.")
+	}
+	return obj, nil
+}
+
+// Get implements models.InvitationRepository.
+func (r *InvitationRepository) Get(id uint) (models.Invitation, *fiber.Error) {
+	var obj models.Invitation
+	result := r.DB.First(&obj, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// ListInvitation implements models.InvitationRepository.
+func (r *InvitationRepository) ListInvitation(invitedByID uint, param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
+	var data []*models.Invitation
+	var pagination response.Pagination
+
+	db := r.DB.Model(&models.Invitation{})
+
+	// admin can see all invitations
+	if invitedByID != 0 {
+		db = db.Where("invited_by_id = ?", invitedByID)
+	}
+
+	if param.Search != "" {
+		// search data based on email
+		db = db.Where("email ILIKE ?", "%"+param.Search+"%")
+	}
+
+	if param.Filter != nil {
+		// the whitelisted filters of the query
+		db = db.Scopes(param.Filter)
+	}
+
+	// 	fill all params pagination
+	pagination.Sort = param.SortQuery
+	pagination.Page = param.Page
+	pagination.Limit = param.Limit
+
+	err := db.Scopes(response.Paginate(data, &pagination, db)).Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	pagination.Data = data
+
+	return &pagination, nil
+}
@@ -29,9 +29,9 @@ func (r *MyDriveRepository) Delete(obj models.MyDrive) *fiber.Error {
 }
 
 // Get implements models.MyDriveRepository.
-func (r *MyDriveRepository) Get(id string) (models.MyDrive, *fiber.Error) {
+func (r *MyDriveRepository) Get(tenant models.Tenant, id string) (models.MyDrive, *fiber.Error) {
 	var obj models.MyDrive
-	result := r.DB.First(&obj, "id = ?", id)
+	result := r.DB.Scopes(utils.TenantThis(tenant.UserID, tenant.OrganizationID)).First(&obj, "id = ?", id)
 	if result.RowsAffected == 0 {
 		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
 	}
@@ -59,17 +59,21 @@ func (r *MyDriveRepository) Create(obj models.MyDrive) (models.MyDrive, *fiber.E
 }
 
 // MyDrive implements models.MyDriveRepository.
-func (r *MyDriveRepository) MyDrive(user models.User, param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
+func (r *MyDriveRepository) MyDrive(tenant models.Tenant, param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
 	var data []*models.MyDrive
 	// var count int64
 	var pagination response.Pagination
 
-	db := r.DB.Where("user_id = ?", user.ID)
+	db := r.DB.Scopes(utils.TenantThis(tenant.UserID, tenant.OrganizationID))
 
 	if param.Search != "" {
-		// search data based on title, description
-		db = db.Where("title ILIKE ?", "%"+param.Search+"%").
-			Or("description ILIKE ?", "%"+param.Search+"%")
+		// search data based on name
+		db = db.Where("name ILIKE ?", "%"+param.Search+"%")
+	}
+
+	if param.Filter != nil {
+		// the whitelisted filters of the query
+		db = db.Scopes(param.Filter)
 	}
 
 	// 	fill all params pagination
@@ -77,6 +81,13 @@ func (r *MyDriveRepository) MyDrive(user models.User, param response.ParamsPagin
 	pagination.Page = param.Page
 	pagination.Limit = param.Limit
 
+	if param.CursorMode {
+		if err := response.PaginateCursor(db, &data, &pagination, param); err != nil {
+			return nil, err
+		}
+		return &pagination, nil
+	}
+
 	err := db.Scopes(response.Paginate(data, &pagination, db)).Find(&data).Error
 	if err != nil {
 		return nil, fiber.NewError(500, db.Error.Error())
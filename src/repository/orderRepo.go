@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"fmt"
+	"myapp/pkg/configs"
+	"myapp/pkg/helpers"
+	"myapp/pkg/response"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrderRepository struct {
+	DB *gorm.DB
+}
+
+// NewOrderRepository will create an object that represent the models.OrderRepository interface
+func NewOrderRepository(Conn *gorm.DB) models.OrderRepository {
+	return &OrderRepository{Conn}
+}
+
+// SendOrderEmail implements models.OrderRepository.
+func (*OrderRepository) SendOrderEmail(obj models.Order, to models.User) error {
+	siteData, _ := configs.GetSiteData(".")
+
+	message := fmt.Sprintf("Order #%d is %s, the total is %s.", obj.ID, obj.Status, obj.Total.Format())
+	if obj.Status == models.OrderShipped && obj.TrackingNumber != "" {
+		message += " The tracking number is " + obj.TrackingNumber + "."
+	}
+
+	emailData := helpers.EmailData{
+		URL:          fmt.Sprintf("%s/orders/%d", siteData.ClientOrigin, obj.ID),
+		FirstName:    to.FirstName,
+		Subject:      fmt.Sprintf("Order #%d: %s", obj.ID, obj.Status),
+		Message:      message,
+		TypeOfAction: "Order",
+		SiteData:     siteData,
+	}
+
+	// send email with goroutine
+	go helpers.SendEmail(to, &emailData, "order_status.html")
+
+	return nil
+}
+
+// ListOrder implements models.OrderRepository.
+func (r *OrderRepository) ListOrder(scope func(db *gorm.DB) *gorm.DB, param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
+	var data []*models.Order
+	var pagination response.Pagination
+
+	db := r.DB.Model(&models.Order{}).Preload("Items").Scopes(scope)
+
+	if param.Filter != nil {
+		// the whitelisted filters of the query
+		db = db.Scopes(param.Filter)
+	}
+
+	// 	fill all params pagination
+	pagination.Sort = param.SortQuery
+	pagination.Page = param.Page
+	pagination.Limit = param.Limit
+
+	if param.CursorMode {
+		if err := response.PaginateCursor(db, &data, &pagination, param); err != nil {
+			return nil, err
+		}
+		return &pagination, nil
+	}
+
+	err := db.Scopes(response.Paginate(data, &pagination, db)).Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	pagination.Data = data
+
+	return &pagination, nil
+}
+
+// GetOrder implements models.OrderRepository.
+func (r *OrderRepository) GetOrder(id uint) (models.Order, *fiber.Error) {
+	var obj models.Order
+	result := r.DB.Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id asc") }).
+		Preload("Buyer").Preload("Seller").First(&obj, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// Checkout implements models.OrderRepository.
+func (r *OrderRepository) Checkout(buyerID uint, orders []*models.Order, cart []*models.CartItem) ([]*models.Order, []*models.ProductVariant, *fiber.Error) {
+	var errD *fiber.Error
+	var lowStock []*models.ProductVariant
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		for _, order := range orders {
+			if err := tx.Omit("Buyer", "Seller", "Organization").Create(order).Error; err != nil {
+				return err
+			}
+
+			for _, item := range order.Items {
+				if item.ProductVariantID == nil {
+					continue
+				}
+
+				// take the stock in single statement, the concurrent checkout can't oversell
+				variant := models.ProductVariant{}
+				variant.ID = *item.ProductVariantID
+				result := tx.Model(&variant).Clauses(clause.Returning{}).
+					Where("stock >= ?", item.Quantity).
+					UpdateColumn("stock", gorm.Expr("stock - ?", item.Quantity))
+				if result.Error != nil {
+					return result.Error
+				}
+				if result.RowsAffected == 0 {
+					errD = fiber.NewError(422, fmt.Sprintf("Insufficient stock of %s.", item.Title))
+					return errD
+				}
+
+				movement := models.StockMovement{
+					Change:           -item.Quantity,
+					StockAfter:       variant.Stock,
+					Reason:           models.StockSale,
+					Note:             fmt.Sprintf("Order #%d", order.ID),
+					ProductVariantID: variant.ID,
+					UserID:           &buyerID,
+				}
+				if err := tx.Create(&movement).Error; err != nil {
+					return err
+				}
+
+				// the owner is notified after the commit, like the manual adjustment
+				if variant.CrossedLowStock(movement.Change) {
+					lowStock = append(lowStock, &variant)
+				}
+			}
+		}
+
+		// the bought items leave the cart
+		ids := make([]uint, 0, len(cart))
+		for _, item := range cart {
+			ids = append(ids, item.ID)
+		}
+		return tx.Where("user_id = ? AND id IN ?", buyerID, ids).Delete(&models.CartItem{}).Error
+	})
+	if errD != nil {
+		return nil, nil, errD
+	}
+	if err != nil {
+		return nil, nil, fiber.NewError(500, err.Error())
+	}
+
+	return orders, lowStock, nil
+}
+
+// Transition implements models.OrderRepository.
+func (r *OrderRepository) Transition(obj models.Order, from models.OrderStatus, userID uint) (models.Order, *fiber.Error) {
+	var errD *fiber.Error
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		// the status must not have been changed by another request
+		result := tx.Model(&obj).Where("status = ?", from).
+			Select("status", "tracking_number", "paid_at", "shipped_at", "completed_at", "cancelled_at", "refunded_at").
+			Updates(&obj)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			errD = fiber.NewError(409, "The order status has been changed, please reload the order.")
+			return errD
+		}
+
+		if obj.Status != models.OrderCancelled {
+			return nil
+		}
+
+		// the cancelled items are put back in stock
+		for _, item := range obj.Items {
+			if item.ProductVariantID == nil {
+				continue
+			}
+
+			variant := models.ProductVariant{}
+			variant.ID = *item.ProductVariantID
+			result := tx.Model(&variant).Clauses(clause.Returning{}).
+				UpdateColumn("stock", gorm.Expr("stock + ?", item.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			// the variant has been deleted
+			if result.RowsAffected == 0 {
+				continue
+			}
+
+			movement := models.StockMovement{
+				Change:           item.Quantity,
+				StockAfter:       variant.Stock,
+				Reason:           models.StockReturn,
+				Note:             fmt.Sprintf("Order #%d cancelled", obj.ID),
+				ProductVariantID: variant.ID,
+				UserID:           &userID,
+			}
+			if err := tx.Create(&movement).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if errD != nil {
+		return obj, errD
+	}
+	if err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"myapp/pkg/configs"
+	"myapp/pkg/helpers"
+	"myapp/pkg/response"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository struct {
+	DB *gorm.DB
+}
+
+// NewOrganizationRepository will create an object that represent the models.OrganizationRepository interface
+func NewOrganizationRepository(Conn *gorm.DB) models.OrganizationRepository {
+	return &OrganizationRepository{Conn}
+}
+
+// SendInvitationEmail implements models.OrganizationRepository.
+func (*OrganizationRepository) SendInvitationEmail(obj models.OrganizationInvitation, org models.Organization, code string) error {
+	siteData, _ := configs.GetSiteData(".")
+	emailData := helpers.EmailData{
+		URL:          siteData.ClientOrigin + "/organizations/invitations/" + code,
+		FirstName:    obj.Email,
+		Subject:      "You are invited to join " + org.Name,
+		Message:      "You are invited to join " + org.Name + " as " + string(obj.Role) + ". This invitation is valid until " + obj.ExpiredAt.Format("02 January 2006 15:04 MST") + ".",
+		TypeOfAction: "Organization Invitation",
+		SiteData:     siteData,
+	}
+
+	// send email with goroutine
+	go helpers.SendEmail(models.User{Email: obj.Email}, &emailData, "organization_invitation.html")
+
+	return nil
+}
+
+// MyOrganization implements models.OrganizationRepository.
+func (r *OrganizationRepository) MyOrganization(userID uint) ([]*models.OrganizationMember, *fiber.Error) {
+	var data []*models.OrganizationMember
+
+	// skip the membership of deleted organization
+	err := r.DB.InnerJoins("Organization").Where("organization_members.user_id = ?", userID).
+		Order("organization_members.id asc").Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
+
+// Get implements models.OrganizationRepository.
+func (r *OrganizationRepository) Get(id uint) (models.Organization, *fiber.Error) {
+	var obj models.Organization
+	result := r.DB.First(&obj, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// Create implements models.OrganizationRepository.
+func (r *OrganizationRepository) Create(obj models.Organization) (models.Organization, *fiber.Error) {
+	// the organization and the owner membership are created together
+	if err := r.DB.Create(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// Update implements models.OrganizationRepository.
+func (r *OrganizationRepository) Update(obj models.Organization) (models.Organization, *fiber.Error) {
+	if err := r.DB.Omit("Members").Save(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// Delete implements models.OrganizationRepository.
+func (r *OrganizationRepository) Delete(obj models.Organization) *fiber.Error {
+	if err := r.DB.Delete(&obj).Error; err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	return nil
+}
+
+// FindMember implements models.OrganizationRepository.
+func (r *OrganizationRepository) FindMember(orgID uint, userID uint) (models.OrganizationMember, *fiber.Error) {
+	var obj models.OrganizationMember
+	// the membership of deleted organization is not found
+	result := r.DB.InnerJoins("Organization").Preload("User").
+		First(&obj, "organization_members.organization_id = ? AND organization_members.user_id = ?", orgID, userID)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, "Member doesn't exists in this organization.")
+	}
+	return obj, nil
+}
+
+// ListMember implements models.OrganizationRepository.
+func (r *OrganizationRepository) ListMember(orgID uint, param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
+	var data []*models.OrganizationMember
+	var pagination response.Pagination
+
+	db := r.DB.Model(&models.OrganizationMember{}).Preload("User").Where("organization_id = ?", orgID)
+
+	if param.Search != "" {
+		// search data based on the user email and name
+		users := r.DB.Model(&models.User{}).Select("id").
+			Where("email ILIKE ?", "%"+param.Search+"%").
+			Or("first_name ILIKE ?", "%"+param.Search+"%").
+			Or("last_name ILIKE ?", "%"+param.Search+"%")
+		db = db.Where("user_id IN (?)", users)
+	}
+
+	if param.Filter != nil {
+		// the whitelisted filters of the query
+		db = db.Scopes(param.Filter)
+	}
+
+	// 	fill all params pagination
+	pagination.Sort = param.SortQuery
+	pagination.Page = param.Page
+	pagination.Limit = param.Limit
+
+	err := db.Scopes(response.Paginate(data, &pagination, db)).Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	pagination.Data = data
+
+	return &pagination, nil
+}
+
+// CountOwner implements models.OrganizationRepository.
+func (r *OrganizationRepository) CountOwner(orgID uint) (int64, *fiber.Error) {
+	var count int64
+	err := r.DB.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", orgID, models.OrgRoleOwner).Count(&count).Error
+	if err != nil {
+		return 0, fiber.NewError(500, err.Error())
+	}
+
+	return count, nil
+}
+
+// CreateMember implements models.OrganizationRepository.
+func (r *OrganizationRepository) CreateMember(obj models.OrganizationMember) (models.OrganizationMember, *fiber.Error) {
+	if err := r.DB.Create(&obj).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key value violates unique") {
+			return obj, fiber.NewError(422, "You are already a member of this organization.")
+		}
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// UpdateMember implements models.OrganizationRepository.
+func (r *OrganizationRepository) UpdateMember(obj models.OrganizationMember) (models.OrganizationMember, *fiber.Error) {
+	if err := r.DB.Omit("User", "Organization").Save(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// DeleteMember implements models.OrganizationRepository.
+func (r *OrganizationRepository) DeleteMember(obj models.OrganizationMember) *fiber.Error {
+	// delete permanent, so the user can join again
+	if err := r.DB.Unscoped().Delete(&obj).Error; err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	return nil
+}
+
+// ListInvitation implements models.OrganizationRepository.
+func (r *OrganizationRepository) ListInvitation(orgID uint, param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
+	var data []*models.OrganizationInvitation
+	var pagination response.Pagination
+
+	db := r.DB.Model(&models.OrganizationInvitation{}).Where("organization_id = ?", orgID)
+
+	if param.Search != "" {
+		// search data based on email
+		db = db.Where("email ILIKE ?", "%"+param.Search+"%")
+	}
+
+	if param.Filter != nil {
+		// the whitelisted filters of the query
+		db = db.Scopes(param.Filter)
+	}
+
+	// 	fill all params pagination
+	pagination.Sort = param.SortQuery
+	pagination.Page = param.Page
+	pagination.Limit = param.Limit
+
+	err := db.Scopes(response.Paginate(data, &pagination, db)).Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	pagination.Data = data
+
+	return &pagination, nil
+}
+
+// GetInvitation implements models.OrganizationRepository.
+func (r *OrganizationRepository) GetInvitation(id uint) (models.OrganizationInvitation, *fiber.Error) {
+	var obj models.OrganizationInvitation
+	result := r.DB.First(&obj, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// FindInvitationByCode implements models.OrganizationRepository.
+func (r *OrganizationRepository) FindInvitationByCode(code string) (models.OrganizationInvitation, *fiber.Error) {
+	var obj models.OrganizationInvitation
+	result := r.DB.Preload("Organization").First(&obj, "code = ?", utils.Encode(code))
+	if result.RowsAffected == 0 || obj.Organization == nil {
+		return obj, fiber.NewError(404, "Invalid invitation code or the organization doesn't exists.")
+	}
+	return obj, nil
+}
+
+// CreateInvitation implements models.OrganizationRepository.
+func (r *OrganizationRepository) CreateInvitation(obj models.OrganizationInvitation) (models.OrganizationInvitation, *fiber.Error) {
+	if err := r.DB.Omit("Organization", "InvitedBy").Create(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// UpdateInvitation implements models.OrganizationRepository.
+func (r *OrganizationRepository) UpdateInvitation(obj models.OrganizationInvitation) (models.OrganizationInvitation, *fiber.Error) {
+	if err := r.DB.Omit("Organization", "InvitedBy").Save(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
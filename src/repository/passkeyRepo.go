@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"myapp/pkg/configs"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type PasskeyRepository struct {
+	DB *gorm.DB
+}
+
+// NewPasskeyRepository will create an object that represent the models.PasskeyRepository interface
+func NewPasskeyRepository(Conn *gorm.DB) models.PasskeyRepository {
+	return &PasskeyRepository{Conn}
+}
+
+// SaveSession implements models.PasskeyRepository.
+func (*PasskeyRepository) SaveSession(key string, session webauthn.SessionData) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	ctxTodo := context.TODO()
+	// the ceremony must be completed within 5 minutes
+	return configs.RedisClient.Set(ctxTodo, key, data, 5*time.Minute).Err()
+}
+
+// PopSession implements models.PasskeyRepository.
+func (*PasskeyRepository) PopSession(key string) (webauthn.SessionData, *fiber.Error) {
+	var session webauthn.SessionData
+
+	ctxTodo := context.TODO()
+	// challenge only can be used once
+	data, err := configs.RedisClient.GetDel(ctxTodo, key).Result()
+	if err == redis.Nil {
+		return session, fiber.NewError(400, "Passkey session is invalid or has expired")
+	}
+	if err != nil {
+		return session, fiber.NewError(500, err.Error())
+	}
+
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return session, fiber.NewError(500, err.Error())
+	}
+
+	return session, nil
+}
+
+// Delete implements models.PasskeyRepository.
+func (r *PasskeyRepository) Delete(obj models.Passkey) *fiber.Error {
+	// delete permanent obj
+	err := r.DB.Unscoped().Delete(&obj).Error
+	if err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	return nil
+}
+
+// Update implements models.PasskeyRepository.
+func (r *PasskeyRepository) Update(obj models.Passkey) (models.Passkey, *fiber.Error) {
+	err := r.DB.Model(&obj).Select("Name", "SignCount", "CloneWarning", "BackupState", "LastUsedAt").
+		Updates(&obj).Error
+	if err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// Create implements models.PasskeyRepository.
+func (r *PasskeyRepository) Create(obj models.Passkey) (models.Passkey, *fiber.Error) {
+	result := r.DB.Omit("User").Create(&obj)
+	if result.Error != nil {
+		return obj, fiber.NewError(500, result.Error.Error())
+	}
+
+	return obj, nil
+}
+
+// FindByCredentialID implements models.PasskeyRepository.
+func (r *PasskeyRepository) FindByCredentialID(credentialID []byte) (models.Passkey, *fiber.Error) {
+	var obj models.Passkey
+	result := r.DB.Preload("User.UserProfile").First(&obj, "credential_id = ?", credentialID)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, "Passkey is not registered.")
+	}
+	return obj, nil
+}
+
+// Get implements models.PasskeyRepository.
+func (r *PasskeyRepository) Get(id uint) (models.Passkey, *fiber.Error) {
+	var obj models.Passkey
+	result := r.DB.First(&obj, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// ListPasskey implements models.PasskeyRepository.
+func (r *PasskeyRepository) ListPasskey(userID uint) ([]*models.Passkey, *fiber.Error) {
+	var data []*models.Passkey
+
+	err := r.DB.Scopes(utils.OwnerThis(userID)).Order("id asc").Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"fmt"
+	"myapp/pkg/payment"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRepository struct {
+	DB *gorm.DB
+}
+
+// NewPaymentRepository will create an object that represent the models.PaymentRepository interface
+func NewPaymentRepository(Conn *gorm.DB) models.PaymentRepository {
+	return &PaymentRepository{Conn}
+}
+
+// LatestPayment implements models.PaymentRepository.
+func (r *PaymentRepository) LatestPayment(orderID uint) (models.Payment, *fiber.Error) {
+	var obj models.Payment
+	result := r.DB.Where("order_id = ?", orderID).Order("id desc").Limit(1).Find(&obj)
+	if result.Error != nil {
+		return obj, fiber.NewError(500, result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// Create implements models.PaymentRepository.
+func (r *PaymentRepository) Create(obj models.Payment) (models.Payment, *fiber.Error) {
+	if err := r.DB.Omit("Order").Create(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// ApplyEvent implements models.PaymentRepository.
+func (r *PaymentRepository) ApplyEvent(provider string, event payment.Event) (models.PaymentResult, *fiber.Error) {
+	var result models.PaymentResult
+	var errD *fiber.Error
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		// the event already processed is skipped, the provider may send it again
+		record := models.PaymentEvent{Provider: provider, EventID: event.ID, Type: event.Type, IntentID: event.IntentID}
+		created := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if created.Error != nil {
+			return created.Error
+		}
+
+		found := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("provider = ? AND intent_id = ?", provider, event.IntentID).Limit(1).Find(&result.Payment)
+		if found.Error != nil {
+			return found.Error
+		}
+		// the event may arrive before the payment is saved, the provider retry it later
+		if found.RowsAffected == 0 {
+			errD = fiber.NewError(404, "The payment of the event doesn't exist.")
+			return errD
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&result.Order, result.Payment.OrderID).Error; err != nil {
+			return err
+		}
+
+		// the duplicate event, and the late event of an older status, change nothing
+		if created.RowsAffected == 0 || !result.Payment.Status.CanMoveTo(event.Status) {
+			return nil
+		}
+
+		// only the full amount pay or refund the order
+		if (event.Status == payment.StatusSucceeded || event.Status == payment.StatusRefunded) && event.Amount != result.Payment.Amount {
+			errD = fiber.NewError(422, fmt.Sprintf("The amount of the event %s doesn't match the payment %s.",
+				event.Amount.Format(), result.Payment.Amount.Format()))
+			return errD
+		}
+
+		now := time.Now()
+		result.Payment.Status = event.Status
+		result.Payment.FailureReason = event.FailureReason
+		result.Payment.LastEventAt = &now
+		err := tx.Model(&result.Payment).Select("status", "failure_reason", "last_event_at").Updates(&result.Payment).Error
+		if err != nil {
+			return err
+		}
+		result.Applied = true
+
+		// reconcile the order with the payment
+		order := &result.Order
+		switch {
+		case event.Status == payment.StatusSucceeded && order.Status == models.OrderPending:
+			order.Status = models.OrderPaid
+			order.PaidAt = &now
+		case event.Status == payment.StatusRefunded && order.Status.CanTransition(models.OrderRefunded):
+			order.Status = models.OrderRefunded
+			order.RefundedAt = &now
+		default:
+			return nil
+		}
+		if err := tx.Model(order).Select("status", "paid_at", "refunded_at").Updates(order).Error; err != nil {
+			return err
+		}
+		result.OrderChanged = true
+
+		return nil
+	})
+	if errD != nil {
+		return result, errD
+	}
+	if err != nil {
+		return result, fiber.NewError(500, err.Error())
+	}
+
+	return result, nil
+}
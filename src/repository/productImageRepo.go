@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"myapp/pkg/utils"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type ProductImageRepository struct {
+	DB *gorm.DB
+}
+
+// NewProductImageRepository will create an object that represent the models.ProductImageRepository interface
+func NewProductImageRepository(Conn *gorm.DB) models.ProductImageRepository {
+	return &ProductImageRepository{Conn}
+}
+
+// ListImage implements models.ProductImageRepository.
+func (r *ProductImageRepository) ListImage(productID uint) ([]*models.ProductImage, *fiber.Error) {
+	var data []*models.ProductImage
+	if err := r.DB.Where("product_id = ?", productID).Order("position asc, id asc").Find(&data).Error; err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
+
+// GetImage implements models.ProductImageRepository.
+func (r *ProductImageRepository) GetImage(productID uint, id uint) (models.ProductImage, *fiber.Error) {
+	var obj models.ProductImage
+	result := r.DB.First(&obj, "product_id = ? AND id = ?", productID, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// AddImages implements models.ProductImageRepository.
+func (r *ProductImageRepository) AddImages(product models.Product, images []*models.ProductImage) ([]*models.ProductImage, *fiber.Error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.ProductImage{}).Where("product_id = ?", product.ID).Count(&count).Error; err != nil {
+			return err
+		}
+
+		// the image uploaded before the gallery become the first image
+		if count == 0 && product.Image != nil {
+			legacy := models.ProductImage{Path: *product.Image, IsCover: true, ProductID: product.ID}
+			if err := tx.Create(&legacy).Error; err != nil {
+				return err
+			}
+		}
+
+		// append after the last image
+		var last int
+		err := tx.Model(&models.ProductImage{}).Select("COALESCE(MAX(position), -1)").
+			Where("product_id = ?", product.ID).Scan(&last).Error
+		if err != nil {
+			return err
+		}
+		for i, obj := range images {
+			obj.ProductID = product.ID
+			obj.Position = last + 1 + i
+		}
+
+		if err := tx.Omit("Product").Create(&images).Error; err != nil {
+			return err
+		}
+
+		return syncCover(tx, product.ID)
+	})
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return images, nil
+}
+
+// UpdateImage implements models.ProductImageRepository.
+func (r *ProductImageRepository) UpdateImage(obj models.ProductImage) (models.ProductImage, *fiber.Error) {
+	if err := r.DB.Omit("Product", "IsCover", "Position").Save(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// DeleteImage implements models.ProductImageRepository.
+func (r *ProductImageRepository) DeleteImage(obj models.ProductImage) *fiber.Error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		// delete permanent, the file is removed
+		if err := tx.Unscoped().Delete(&obj).Error; err != nil {
+			return err
+		}
+
+		return syncCover(tx, obj.ProductID)
+	})
+	if err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	// remove file from server, skip the file on S3
+	if obj.IsLocal() {
+		utils.RemoveFileSilence(obj.Path, string(models.ImageFile))
+	}
+
+	return nil
+}
+
+// Reorder implements models.ProductImageRepository.
+func (r *ProductImageRepository) Reorder(productID uint, ids []uint) *fiber.Error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		for position, id := range ids {
+			err := tx.Model(&models.ProductImage{}).Where("product_id = ? AND id = ?", productID, id).
+				UpdateColumn("position", position).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	return nil
+}
+
+// SetCover implements models.ProductImageRepository.
+func (r *ProductImageRepository) SetCover(productID uint, id uint) *fiber.Error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.ProductImage{}).Where("product_id = ?", productID).
+			UpdateColumn("is_cover", gorm.Expr("id = ?", id)).Error
+		if err != nil {
+			return err
+		}
+
+		return syncCover(tx, productID)
+	})
+	if err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	return nil
+}
+
+// syncCover make sure the product has one cover and copy its path to products.image,
+// the first image become the cover when the cover is deleted
+func syncCover(tx *gorm.DB, productID uint) error {
+	var images []*models.ProductImage
+	if err := tx.Where("product_id = ?", productID).Order("position asc, id asc").Find(&images).Error; err != nil {
+		return err
+	}
+
+	if len(images) == 0 {
+		return tx.Model(&models.Product{}).Where("id = ?", productID).UpdateColumn("image", nil).Error
+	}
+
+	cover := images[0]
+	for _, obj := range images {
+		if obj.IsCover {
+			cover = obj
+			break
+		}
+	}
+
+	err := tx.Model(&models.ProductImage{}).Where("product_id = ?", productID).
+		UpdateColumn("is_cover", gorm.Expr("id = ?", cover.ID)).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&models.Product{}).Where("id = ?", productID).UpdateColumn("image", cover.Path).Error
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"myapp/pkg/utils"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type ProductImportRepository struct {
+	DB *gorm.DB
+}
+
+// NewProductImportRepository will create an object that represent the models.ProductImportRepository interface
+func NewProductImportRepository(Conn *gorm.DB) models.ProductImportRepository {
+	return &ProductImportRepository{Conn}
+}
+
+// ListImport implements models.ProductImportRepository.
+func (r *ProductImportRepository) ListImport(tenant models.Tenant) ([]*models.ProductImport, *fiber.Error) {
+	var data []*models.ProductImport
+	err := r.DB.Scopes(utils.TenantThis(tenant.UserID, tenant.OrganizationID)).
+		Where("user_id = ?", tenant.UserID).Order("id desc").Limit(50).Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
+
+// GetImport implements models.ProductImportRepository.
+func (r *ProductImportRepository) GetImport(tenant models.Tenant, id uint) (models.ProductImport, *fiber.Error) {
+	var obj models.ProductImport
+	result := r.DB.Scopes(utils.TenantThis(tenant.UserID, tenant.OrganizationID)).
+		Where("user_id = ?", tenant.UserID).First(&obj, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// Create implements models.ProductImportRepository.
+func (r *ProductImportRepository) Create(obj models.ProductImport) (models.ProductImport, *fiber.Error) {
+	if err := r.DB.Omit("User", "Organization").Create(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// Update implements models.ProductImportRepository.
+func (r *ProductImportRepository) Update(obj models.ProductImport) (models.ProductImport, *fiber.Error) {
+	if err := r.DB.Omit("User", "Organization").Save(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
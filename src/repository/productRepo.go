@@ -1,30 +1,114 @@
 package repository
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"myapp/pkg/configs"
+	"myapp/pkg/money"
 	"myapp/pkg/response"
 	"myapp/pkg/utils"
 	"myapp/src/models"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-faker/faker/v4"
 	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductRepository struct {
 	DB *gorm.DB
+	// text search configuration of products.search_vector
+	Language string
+	// the currency of the populated prices
+	Currency string
 }
 
 // NewProductRepository will create an object that represent the models.ProductRepository interface
 func NewProductRepository(Conn *gorm.DB) models.ProductRepository {
-	return &ProductRepository{Conn}
+	config, _ := configs.LoadConfig(".")
+	return &ProductRepository{Conn, config.FullTextLanguage(), config.BaseCurrency()}
+}
+
+// the time of the last write of the catalog, in unix nanoseconds
+const catalogModifiedKey = "catalog:modified"
+
+func catalogPageKey(version time.Time, key string) string {
+	return fmt.Sprintf("catalog_page:%d:%s", version.UnixNano(), key)
+}
+
+// CatalogModifiedAt implements models.ProductRepository.
+func (*ProductRepository) CatalogModifiedAt() time.Time {
+	ctxTodo := context.TODO()
+	nano, err := configs.RedisClient.Get(ctxTodo, catalogModifiedKey).Int64()
+	if err == redis.Nil {
+		// the first request after the redis start is the first version,
+		// unless a concurrent request or a write has already set it
+		now := time.Now()
+		set, errSet := configs.RedisClient.SetNX(ctxTodo, catalogModifiedKey, now.UnixNano(), 0).Result()
+		if errSet != nil || set {
+			return now
+		}
+		nano, err = configs.RedisClient.Get(ctxTodo, catalogModifiedKey).Int64()
+	}
+	if err != nil {
+		return time.Now()
+	}
+	return time.Unix(0, nano)
+}
+
+// GetCatalogPage implements models.ProductRepository.
+func (*ProductRepository) GetCatalogPage(version time.Time, key string) (models.CatalogPage, bool) {
+	var page models.CatalogPage
+
+	ctxTodo := context.TODO()
+	cached, err := configs.RedisClient.Get(ctxTodo, catalogPageKey(version, key)).Bytes()
+	if err != nil {
+		return page, false
+	}
+	if err := json.Unmarshal(cached, &page); err != nil {
+		return page, false
+	}
+	return page, true
+}
+
+// SetCatalogPage implements models.ProductRepository.
+func (*ProductRepository) SetCatalogPage(key string, page models.CatalogPage, ttl time.Duration) {
+	data, err := json.Marshal(page)
+	if err != nil {
+		return
+	}
+
+	ctxTodo := context.TODO()
+	configs.RedisClient.Set(ctxTodo, catalogPageKey(page.ModifiedAt, key), data, ttl)
+}
+
+// ClearCatalogCache implements models.ProductRepository.
+func (*ProductRepository) ClearCatalogCache() {
+	// the pages of the previous version are no longer read and expire with their TTL
+	ctxTodo := context.TODO()
+	configs.RedisClient.Set(ctxTodo, catalogModifiedKey, time.Now().UnixNano(), 0)
 }
 
 // Delete implements models.ProductRepository.
-func (r *ProductRepository) Delete(obj models.Product) *fiber.Error {
-	// delete obj
-	err := r.DB.Delete(&obj).Error
+func (r *ProductRepository) Delete(obj models.Product, actorID uint) *fiber.Error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		// delete obj
+		if err := tx.Delete(&obj).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.ProductRevision{
+			Action:    models.RevisionDelete,
+			Changes:   map[string]models.FieldChange{},
+			ProductID: obj.ID,
+			UserID:    &actorID,
+		}).Error
+	})
 	if err != nil {
 		return fiber.NewError(500, err.Error())
 	}
@@ -33,8 +117,32 @@ func (r *ProductRepository) Delete(obj models.Product) *fiber.Error {
 }
 
 // Update implements models.ProductRepository.
-func (r *ProductRepository) Update(product models.Product) (models.Product, *fiber.Error) {
-	err := r.DB.UpdateColumns(&product).Error
+func (r *ProductRepository) Update(product models.Product, actorID uint) (models.Product, *fiber.Error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := productState(tx, product.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := assignSlug(tx, &product); err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Image", "Category", "Tags", "Variants", "Images", "RatingAverage", "RatingCount").UpdateColumns(&product).Error; err != nil {
+			return err
+		}
+
+		// the category can be removed, UpdateColumns skip the nil value
+		if err := tx.Model(&product).UpdateColumn("category_id", product.CategoryID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&product).Association("Tags").Replace(product.Tags); err != nil {
+			return err
+		}
+
+		return recordRevision(tx, models.ProductRevision{Action: models.RevisionUpdate, ProductID: product.ID, UserID: &actorID}, before)
+	})
 	if err != nil {
 		return product, fiber.NewError(500, err.Error())
 	}
@@ -42,46 +150,321 @@ func (r *ProductRepository) Update(product models.Product) (models.Product, *fib
 	return product, nil
 }
 
+// UpdateStatus implements models.ProductRepository.
+func (r *ProductRepository) UpdateStatus(obj models.Product, actorID uint) (models.Product, *fiber.Error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := productState(tx, obj.ID)
+		if err != nil {
+			return err
+		}
+
+		// the nil times are saved too
+		if err := tx.Model(&obj).Select("status", "is_enable", "publish_at", "unpublish_at").Updates(&obj).Error; err != nil {
+			return err
+		}
+
+		return recordRevision(tx, models.ProductRevision{Action: models.RevisionStatus, ProductID: obj.ID, UserID: &actorID}, before)
+	})
+	if err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// Revert implements models.ProductRepository.
+func (r *ProductRepository) Revert(obj models.Product, revisionID uint, actorID uint) (models.Product, *fiber.Error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := productState(tx, obj.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := assignSlug(tx, &obj); err != nil {
+			return err
+		}
+
+		// every tracked column, the empty values are restored too
+		err = tx.Model(&obj).Select("title", "slug", "description", "price_amount", "price_currency", "category_id",
+			"status", "is_enable", "publish_at", "unpublish_at", "external_id").Updates(&obj).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&obj).Association("Tags").Replace(obj.Tags); err != nil {
+			return err
+		}
+
+		return recordRevision(tx, models.ProductRevision{Action: models.RevisionRevert, RevertedFrom: &revisionID, ProductID: obj.ID, UserID: &actorID}, before)
+	})
+	if err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// ListTrash implements models.ProductRepository.
+func (r *ProductRepository) ListTrash(tenant models.Tenant, param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
+	var data []*models.Product
+	var pagination response.Pagination
+
+	db := r.DB.Unscoped().Model(&models.Product{}).Preload("Category").Preload("Tags").
+		Preload("Images", orderImages).
+		Scopes(trashedProduct(tenant))
+
+	if param.Filter != nil {
+		// the whitelisted filters of the query
+		db = db.Scopes(param.Filter)
+	}
+
+	// 	fill all params pagination
+	pagination.Sort = param.SortQuery
+	pagination.Page = param.Page
+	pagination.Limit = param.Limit
+
+	if param.CursorMode {
+		if err := response.PaginateCursor(db, &data, &pagination, param); err != nil {
+			return nil, err
+		}
+		return &pagination, nil
+	}
+
+	err := db.Scopes(response.Paginate(data, &pagination, db)).Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	pagination.Data = data
+
+	return &pagination, nil
+}
+
+// GetTrashed implements models.ProductRepository.
+func (r *ProductRepository) GetTrashed(tenant models.Tenant, id uint) (models.Product, *fiber.Error) {
+	var obj models.Product
+	result := r.DB.Unscoped().Scopes(trashedProduct(tenant)).First(&obj, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// ListTrashDue implements models.ProductRepository.
+func (r *ProductRepository) ListTrashDue(before time.Time, limit int) ([]*models.Product, *fiber.Error) {
+	var data []*models.Product
+	err := r.DB.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("deleted_at asc").Limit(limit).Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+	return data, nil
+}
+
+// Restore implements models.ProductRepository.
+func (r *ProductRepository) Restore(obj models.Product, actorID uint) (models.Product, *fiber.Error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&obj).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.ProductRevision{
+			Action:    models.RevisionRestore,
+			Changes:   map[string]models.FieldChange{},
+			ProductID: obj.ID,
+			UserID:    &actorID,
+		}).Error
+	})
+	if err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// PermanentDelete implements models.ProductRepository.
+func (r *ProductRepository) PermanentDelete(obj models.Product) *fiber.Error {
+	var images []*models.ProductImage
+	var photos []string
+
+	// collect the files before the rows are gone
+	r.DB.Unscoped().Where("product_id = ?", obj.ID).Find(&images)
+	r.DB.Unscoped().Model(&models.ProductReview{}).Where("product_id = ? AND photo IS NOT NULL", obj.ID).Pluck("photo", &photos)
+
+	// the variants, images, reviews, revisions and slugs are deleted by the foreign keys,
+	// the order items keep their snapshot
+	err := r.DB.Unscoped().Delete(&obj).Error
+	if err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	// remove files from server, skip the file on S3
+	if obj.Image != nil && !strings.HasPrefix(*obj.Image, "http") {
+		utils.RemoveFileSilence(*obj.Image, string(models.ImageFile))
+	}
+	for _, image := range images {
+		if image.IsLocal() {
+			utils.RemoveFileSilence(image.Path, string(models.ImageFile))
+		}
+	}
+	for _, photo := range photos {
+		if !strings.HasPrefix(photo, "http") {
+			utils.RemoveFileSilence(photo, string(models.ImageFile))
+		}
+	}
+
+	return nil
+}
+
+// PublishDue implements models.ProductRepository.
+func (r *ProductRepository) PublishDue(now time.Time) ([]*models.Product, *fiber.Error) {
+	var data []*models.Product
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&data).Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "organization_id"}}}).
+			Where("status = ? AND publish_at <= ?", models.ProductScheduled, now).
+			Updates(map[string]interface{}{"status": models.ProductPublished, "is_enable": true}).Error
+		if err != nil {
+			return err
+		}
+
+		return recordStatusRevisions(tx, data, models.RevisionPublish, models.ProductScheduled, models.ProductPublished)
+	})
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
+
+// ArchiveDue implements models.ProductRepository.
+func (r *ProductRepository) ArchiveDue(now time.Time) ([]*models.Product, *fiber.Error) {
+	var data []*models.Product
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&data).Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "organization_id"}}}).
+			Where("status = ? AND unpublish_at <= ?", models.ProductPublished, now).
+			Updates(map[string]interface{}{"status": models.ProductArchived, "is_enable": false}).Error
+		if err != nil {
+			return err
+		}
+
+		return recordStatusRevisions(tx, data, models.RevisionArchive, models.ProductPublished, models.ProductArchived)
+	})
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
+
 // Create implements models.ProductRepository.
-func (r *ProductRepository) Create(product models.Product) (models.Product, *fiber.Error) {
-	result := r.DB.Create(&product)
-	if result.Error != nil {
-		return product, fiber.NewError(500, result.Error.Error())
+func (r *ProductRepository) Create(product models.Product, actorID uint) (models.Product, *fiber.Error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := assignSlug(tx, &product); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&product).Error; err != nil {
+			return err
+		}
+
+		// every field set by the creation
+		return recordRevision(tx, models.ProductRevision{Action: models.RevisionCreate, ProductID: product.ID, UserID: &actorID}, models.Product{}.Snapshot())
+	})
+	if err != nil {
+		return product, fiber.NewError(500, err.Error())
 	}
 
 	return product, nil
 }
 
 // GetProduct implements models.ProductRepository.
-func (r *ProductRepository) GetProduct(id uint) (models.Product, *fiber.Error) {
+func (r *ProductRepository) GetProduct(tenant models.Tenant, id uint) (models.Product, *fiber.Error) {
+	return r.getProduct(tenant, id)
+}
+
+// GetProductBySlug implements models.ProductRepository.
+func (r *ProductRepository) GetProductBySlug(tenant models.Tenant, slug string) (models.Product, *fiber.Error) {
+	return r.getProduct(tenant, "slug = ?", slug)
+}
+
+// FindSlugRedirect implements models.ProductRepository.
+func (r *ProductRepository) FindSlugRedirect(slug string) (string, *fiber.Error) {
+	var current string
+	result := r.DB.Model(&models.ProductSlug{}).Select("products.slug").
+		Joins("JOIN products ON products.id = product_slugs.product_id AND products.deleted_at IS NULL").
+		Where("product_slugs.slug = ?", slug).Limit(1).Scan(&current)
+	if result.Error != nil {
+		return "", fiber.NewError(500, result.Error.Error())
+	}
+	if current == "" {
+		return "", fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return current, nil
+}
+
+// FindByExternalID implements models.ProductRepository.
+func (r *ProductRepository) FindByExternalID(userID uint, externalID string) (models.Product, *fiber.Error) {
 	var obj models.Product
-	result := r.DB.Preload("User.UserProfile.Status").First(&obj, id)
+	result := r.DB.Where("user_id = ? AND external_id = ?", userID, externalID).Limit(1).Find(&obj)
+	if result.Error != nil {
+		return obj, fiber.NewError(500, result.Error.Error())
+	}
 	if result.RowsAffected == 0 {
 		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
 	}
 	return obj, nil
 }
 
+// EachProduct implements models.ProductRepository.
+func (r *ProductRepository) EachProduct(tenant models.Tenant, size int, fn func([]*models.Product) error) *fiber.Error {
+	var data []*models.Product
+	result := r.DB.Preload("Tags").
+		Scopes(utils.TenantThis(tenant.UserID, tenant.OrganizationID)).
+		Where("user_id = ?", tenant.UserID).
+		FindInBatches(&data, size, func(tx *gorm.DB, batch int) error {
+			return fn(data)
+		})
+	if result.Error != nil {
+		return fiber.NewError(500, result.Error.Error())
+	}
+
+	return nil
+}
+
 // MyProduct implements models.ProductRepository.
-func (r *ProductRepository) MyProduct(user models.User, param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
+func (r *ProductRepository) MyProduct(tenant models.Tenant, param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
 	var data []*models.Product
 	// var count int64
 	var pagination response.Pagination
 
-	db := r.DB.Preload("User.UserProfile.Status").Where("user_id = ?", user.ID)
+	db := r.DB.Preload("User.UserProfile.Status").Preload("Category").Preload("Tags").
+		Preload("Images", orderImages).
+		Scopes(utils.TenantThis(tenant.UserID, tenant.OrganizationID)).
+		Where("user_id = ?", tenant.UserID)
 
 	if param.Search != "" {
-		// search data based on title, description
-		db = db.Where("title ILIKE ?", "%"+param.Search+"%").
-			Or("description ILIKE ?", "%"+param.Search+"%")
+		// full-text search on title and description
+		db = db.Where("search_vector @@ websearch_to_tsquery(?::regconfig, ?)", r.Language, param.Search)
+	}
+
+	if param.Filter != nil {
+		// the whitelisted filters of the query
+		db = db.Scopes(param.Filter)
 	}
 
 	// 	fill all params pagination
-	pagination.Sort = param.SortQuery
+	pagination.Sort = r.sortQuery(param)
 	pagination.Page = param.Page
 	pagination.Limit = param.Limit
 
-	err := db.Scopes(response.Paginate(data, &pagination, db)).Find(&data).Error
+	if param.CursorMode {
+		if err := response.PaginateCursor(db.Scopes(r.searchRank(param.Search)), &data, &pagination, param); err != nil {
+			return nil, err
+		}
+		return &pagination, nil
+	}
+
+	err := db.Scopes(response.Paginate(data, &pagination, db), r.searchRank(param.Search)).Find(&data).Error
 	if err != nil {
 		return nil, fiber.NewError(500, db.Error.Error())
 	}
@@ -92,25 +475,53 @@ func (r *ProductRepository) MyProduct(user models.User, param response.ParamsPag
 }
 
 // ListProduct implements models.ProductRepository.
-func (r *ProductRepository) ListProduct(param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
+func (r *ProductRepository) ListProduct(tenant models.Tenant, param response.ParamsPagination, filter models.ProductFilter) (*response.Pagination, *fiber.Error) {
 	var data []*models.Product
 	// var count int64
 	var pagination response.Pagination
 
-	db := r.DB.Preload("User.UserProfile.Status")
+	// the organization catalog is only visible to its members, and only the live products
+	db := r.DB.Preload("User.UserProfile.Status").Preload("Category").Preload("Tags").
+		Preload("Images", orderImages).
+		Scopes(utils.OrganizationThis(tenant.OrganizationID), models.LiveProduct(time.Now()))
+
+	if filter.CategoryIDs != nil {
+		db = db.Where("category_id IN ?", filter.CategoryIDs)
+	}
+
+	if len(filter.Tags) > 0 {
+		// the product must have all of the tags
+		tagged := r.DB.Table("product_tags").Select("product_tags.product_id").
+			Joins("JOIN tags ON tags.id = product_tags.tag_id").
+			Where("tags.name IN ?", filter.Tags).
+			Group("product_tags.product_id").
+			Having("COUNT(DISTINCT product_tags.tag_id) = ?", len(filter.Tags))
+		db = db.Where("id IN (?)", tagged)
+	}
 
 	if param.Search != "" {
-		// search data based on title, description
-		db = db.Where("title ILIKE ?", "%"+param.Search+"%").
-			Or("description ILIKE ?", "%"+param.Search+"%")
+		// full-text search on title and description
+		db = db.Where("search_vector @@ websearch_to_tsquery(?::regconfig, ?)", r.Language, param.Search)
+	}
+
+	if param.Filter != nil {
+		// the whitelisted filters of the query
+		db = db.Scopes(param.Filter)
 	}
 
 	// 	fill all params pagination
-	pagination.Sort = param.SortQuery
+	pagination.Sort = r.sortQuery(param)
 	pagination.Page = param.Page
 	pagination.Limit = param.Limit
 
-	err := db.Scopes(response.Paginate(data, &pagination, db)).Find(&data).Error
+	if param.CursorMode {
+		if err := response.PaginateCursor(db.Scopes(r.searchRank(param.Search)), &data, &pagination, param); err != nil {
+			return nil, err
+		}
+		return &pagination, nil
+	}
+
+	err := db.Scopes(response.Paginate(data, &pagination, db), r.searchRank(param.Search)).Find(&data).Error
 	if err != nil {
 		return nil, fiber.NewError(500, db.Error.Error())
 	}
@@ -124,16 +535,205 @@ func (r *ProductRepository) ListProduct(param response.ParamsPagination) (*respo
 func (r *ProductRepository) PopulateProducts(userID uint, n int) *fiber.Error {
 	for i := 0; i < n; i++ {
 		image := fmt.Sprintf("https://loremflickr.com/320/240/product?%s", faker.UUIDDigit())
-		r.DB.Create(&models.Product{
+		obj := models.Product{
 			Title:       fmt.Sprintf("%s Product No.%s", faker.Word(), strconv.Itoa(i+1)),
 			Description: faker.Paragraph(),
 			Image:       &image,
-			Price:       utils.GetRandFloat(5.0, 100.0),
+			Price:       money.New(int64(utils.GetRandInt(500, 10000)), r.Currency),
 			IsEnable:    false,
 			UserID:      userID,
 			User:        models.User{},
+		}
+		r.DB.Transaction(func(tx *gorm.DB) error {
+			if err := assignSlug(tx, &obj); err != nil {
+				return err
+			}
+			return tx.Create(&obj).Error
 		})
 	}
 
 	return nil
 }
+
+// searchRank select the rank and the highlighted snippets of the search result,
+// the snippets aren't HTML escaped, only the <mark> tags are added
+func (r *ProductRepository) searchRank(search string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if search == "" {
+			return db
+		}
+
+		query := gorm.Expr("websearch_to_tsquery(?::regconfig, ?)", r.Language, search)
+		return db.Select(
+			"products.*, ts_rank_cd(search_vector, ?) AS search_rank, "+
+				"ts_headline(?::regconfig, title, ?, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS title_highlight, "+
+				"ts_headline(?::regconfig, COALESCE(description, ''), ?, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS description_highlight",
+			query, r.Language, query, r.Language, query,
+		)
+	}
+}
+
+// sortQuery map the rank sort to the search rank, without search the rank is meaningless
+func (r *ProductRepository) sortQuery(param response.ParamsPagination) string {
+	orders := strings.Split(param.SortQuery, ", ")
+	ranked := false
+	for i, order := range orders {
+		field, direction, _ := strings.Cut(order, " ")
+		if field != "rank" {
+			continue
+		}
+		if param.Search == "" {
+			orders[i] = "id desc"
+			continue
+		}
+		orders[i] = "search_rank " + direction
+		ranked = true
+	}
+
+	// the equal rank is sorted by the newest product
+	if ranked {
+		orders = append(orders, "id desc")
+	}
+
+	return strings.Join(orders, ", ")
+}
+
+// visibleProduct hide the product isn't live, except from the user who can manage it
+func visibleProduct(tenant models.Tenant) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if tenant.IsManager() {
+			return db
+		}
+
+		live := models.LiveProduct(time.Now())(db.Session(&gorm.Session{NewDB: true}))
+		return db.Where(live.Or("products.user_id = ?", tenant.UserID))
+	}
+}
+
+// trashedProduct scope the deleted products the tenant can manage, the scope must be Unscoped
+func trashedProduct(tenant models.Tenant) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("products.deleted_at IS NOT NULL").
+			Scopes(utils.TenantThis(tenant.UserID, tenant.OrganizationID))
+		if !tenant.IsManager() {
+			db = db.Where("products.user_id = ?", tenant.UserID)
+		}
+		return db
+	}
+}
+
+// orderImages preload the gallery in the display order
+func orderImages(db *gorm.DB) *gorm.DB {
+	return db.Order("position asc, id asc")
+}
+
+// productState load the tracked fields of the product in the transaction
+func productState(tx *gorm.DB, id uint) (models.ProductSnapshot, error) {
+	var obj models.Product
+	if err := tx.Unscoped().Preload("Tags").First(&obj, id).Error; err != nil {
+		return models.ProductSnapshot{}, err
+	}
+	return obj.Snapshot(), nil
+}
+
+// recordRevision save the fields changed since before, the update without change isn't recorded
+func recordRevision(tx *gorm.DB, revision models.ProductRevision, before models.ProductSnapshot) error {
+	after, err := productState(tx, revision.ProductID)
+	if err != nil {
+		return err
+	}
+
+	revision.Changes = before.Diff(after)
+	if len(revision.Changes) == 0 && revision.Action != models.RevisionCreate {
+		return nil
+	}
+
+	return tx.Create(&revision).Error
+}
+
+// recordStatusRevisions save the status change of the scheduler, it has no actor
+func recordStatusRevisions(tx *gorm.DB, data []*models.Product, action models.RevisionAction, from models.ProductStatus, to models.ProductStatus) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	fromJSON, _ := json.Marshal(from)
+	toJSON, _ := json.Marshal(to)
+	revisions := make([]*models.ProductRevision, 0, len(data))
+	for _, product := range data {
+		revisions = append(revisions, &models.ProductRevision{
+			Action:    action,
+			Changes:   map[string]models.FieldChange{"status": {From: fromJSON, To: toJSON}},
+			ProductID: product.ID,
+		})
+	}
+
+	return tx.CreateInBatches(revisions, 500).Error
+}
+
+// getProduct return the product of the conditions visible in the tenant
+func (r *ProductRepository) getProduct(tenant models.Tenant, conds ...interface{}) (models.Product, *fiber.Error) {
+	var obj models.Product
+	result := r.DB.Preload("User.UserProfile.Status").Preload("Category").Preload("Tags").
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("id asc") }).
+		Preload("Images", orderImages).
+		Scopes(utils.OrganizationThis(tenant.OrganizationID), visibleProduct(tenant)).First(&obj, conds...)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// assignSlug set the slug from the title, the previous slug is kept in the history to redirect,
+// the number suffix make it unique, e.g. "red-shirt-2"
+func assignSlug(tx *gorm.DB, product *models.Product) error {
+	base := utils.Slugify(product.Title)
+	if base == "" {
+		base = "product"
+	}
+
+	if product.Slug == base {
+		return nil
+	}
+
+	// the concurrent products of the same title wait for each other
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", base).Error; err != nil {
+		return err
+	}
+
+	var taken []string
+	err := tx.Raw(`SELECT slug FROM products WHERE (slug = @base OR slug LIKE @like) AND id <> @id
+		UNION SELECT slug FROM product_slugs WHERE (slug = @base OR slug LIKE @like) AND product_id <> @id`,
+		map[string]interface{}{"base": base, "like": base + "-%", "id": product.ID}).Scan(&taken).Error
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]bool, len(taken))
+	for _, slug := range taken {
+		used[slug] = true
+	}
+	slug := base
+	for n := 2; used[slug]; n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+	// the title change without changing the slug, e.g. keep the suffix
+	if slug == product.Slug {
+		return nil
+	}
+
+	if product.ID != 0 {
+		if product.Slug != "" {
+			if err := tx.Create(&models.ProductSlug{Slug: product.Slug, ProductID: product.ID}).Error; err != nil {
+				return err
+			}
+		}
+		// the product get back its previous slug
+		if err := tx.Where("product_id = ? AND slug = ?", product.ID, slug).Delete(&models.ProductSlug{}).Error; err != nil {
+			return err
+		}
+	}
+
+	product.Slug = slug
+	return nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"fmt"
+	"myapp/pkg/configs"
+	"myapp/src/models"
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// the size of the catalog of the search benchmark
+const benchProducts = 100000
+
+// benchSearches are the typical searches, a word of the title and words of the description
+var benchSearches = []string{"product", "dolor sit", "voluptatem"}
+
+// benchProductRepo connect to the database of BENCH_DB_DSN and populate the products of the benchmark user once,
+// the products are kept for the next runs
+func benchProductRepo(b *testing.B) (*ProductRepository, uint) {
+	dsn := os.Getenv("BENCH_DB_DSN")
+	if dsn == "" {
+		b.Skip("BENCH_DB_DSN isn't set, e.g. host=localhost user=postgres password=postgres dbname=bench_db port=5432 sslmode=disable")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		b.Fatal(err)
+	}
+	configs.DB = db
+	if err := db.AutoMigrate(&models.User{}, &models.UserProfile{}); err != nil {
+		b.Fatal(err)
+	}
+	if err := configs.MigrateDB(); err != nil {
+		b.Fatal(err)
+	}
+
+	user := models.User{Username: "bench", Email: "bench@example.com"}
+	if err := db.Omit(clause.Associations).Where("username = ?", user.Username).FirstOrCreate(&user).Error; err != nil {
+		b.Fatal(err)
+	}
+
+	repo := &ProductRepository{DB: db, Language: "english", Currency: "USD"}
+
+	var count int64
+	db.Model(&models.Product{}).Where("user_id = ?", user.ID).Count(&count)
+	if missing := benchProducts - int(count); missing > 0 {
+		b.Logf("populating %d products, only on the first run", missing)
+		if err := repo.PopulateProducts(user.ID, missing); err != nil {
+			b.Fatal(err)
+		}
+		db.Exec("ANALYZE products")
+	}
+
+	return repo, user.ID
+}
+
+// BenchmarkProductSearch compare the full-text search on the GIN index with the ILIKE search it replaced,
+// both count the matches and take the first page like the product list
+//
+//	BENCH_DB_DSN="..." go test ./src/repository -run '^$' -bench ProductSearch -benchtime 20x
+func BenchmarkProductSearch(b *testing.B) {
+	repo, userID := benchProductRepo(b)
+
+	queries := map[string]func(db *gorm.DB, search string) *gorm.DB{
+		"fulltext": func(db *gorm.DB, search string) *gorm.DB {
+			return db.Where("search_vector @@ websearch_to_tsquery(?::regconfig, ?)", repo.Language, search)
+		},
+		"ilike": func(db *gorm.DB, search string) *gorm.DB {
+			return db.Where("title ILIKE ? OR description ILIKE ?", "%"+search+"%", "%"+search+"%")
+		},
+	}
+
+	for _, name := range []string{"fulltext", "ilike"} {
+		query := queries[name]
+		for _, search := range benchSearches {
+			b.Run(fmt.Sprintf("%s/%s", name, search), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					var count int64
+					var data []*models.Product
+
+					db := repo.DB.Model(&models.Product{}).Where("user_id = ?", userID)
+					if err := query(db, search).Count(&count).Error; err != nil {
+						b.Fatal(err)
+					}
+					if err := query(repo.DB.Where("user_id = ?", userID), search).Order("id desc").Limit(20).Find(&data).Error; err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"fmt"
+	"myapp/pkg/configs"
+	"myapp/pkg/helpers"
+	"myapp/pkg/response"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type ProductReportRepository struct {
+	DB *gorm.DB
+}
+
+// NewProductReportRepository will create an object that represent the models.ProductReportRepository interface
+func NewProductReportRepository(Conn *gorm.DB) models.ProductReportRepository {
+	return &ProductReportRepository{Conn}
+}
+
+// SendModerationEmail implements models.ProductReportRepository.
+func (*ProductReportRepository) SendModerationEmail(product models.Product, action models.ModerationAction, note string) error {
+	siteData, _ := configs.GetSiteData(".")
+
+	var subject, message string
+	switch action {
+	case models.ModerationHide:
+		subject = fmt.Sprintf("Your product \"%s\" has been hidden", product.Title)
+		message = "The product has been hidden from the catalog after a review of the reports."
+	case models.ModerationDelete:
+		subject = fmt.Sprintf("Your product \"%s\" has been removed", product.Title)
+		message = "The product has been removed from the catalog after a review of the reports."
+	default:
+		subject = fmt.Sprintf("Warning about your product \"%s\"", product.Title)
+		message = "The product has been reported and reviewed by our moderators, please make sure it follows our rules."
+	}
+	message += " Reason: " + note
+
+	emailData := helpers.EmailData{
+		URL:          fmt.Sprintf("%s/products/%d", siteData.ClientOrigin, product.ID),
+		FirstName:    product.User.FirstName,
+		Subject:      subject,
+		Message:      message,
+		TypeOfAction: "Moderation",
+		SiteData:     siteData,
+	}
+
+	// send email with goroutine
+	go helpers.SendEmail(product.User, &emailData, "product_moderation.html")
+
+	return nil
+}
+
+// ListReport implements models.ProductReportRepository.
+func (r *ProductReportRepository) ListReport(param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
+	var data []*models.ProductReport
+	var pagination response.Pagination
+
+	db := r.DB.Model(&models.ProductReport{}).Preload("User.UserProfile.Status").
+		Preload("Product", func(db *gorm.DB) *gorm.DB { return db.Unscoped() })
+
+	if param.Filter != nil {
+		// the whitelisted filters of the query
+		db = db.Scopes(param.Filter)
+	}
+
+	// 	fill all params pagination
+	pagination.Sort = param.SortQuery
+	pagination.Page = param.Page
+	pagination.Limit = param.Limit
+
+	if param.CursorMode {
+		if err := response.PaginateCursor(db, &data, &pagination, param); err != nil {
+			return nil, err
+		}
+		return &pagination, nil
+	}
+
+	err := db.Scopes(response.Paginate(data, &pagination, db)).Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	pagination.Data = data
+
+	return &pagination, nil
+}
+
+// GetReport implements models.ProductReportRepository.
+func (r *ProductReportRepository) GetReport(id uint) (models.ProductReport, *fiber.Error) {
+	var obj models.ProductReport
+	result := r.DB.Preload("User.UserProfile.Status").
+		Preload("Product", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Product.User").
+		First(&obj, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// HasOpenReport implements models.ProductReportRepository.
+func (r *ProductReportRepository) HasOpenReport(productID uint, userID uint) (bool, *fiber.Error) {
+	var count int64
+	err := r.DB.Model(&models.ProductReport{}).
+		Where("product_id = ? AND user_id = ? AND status = ?", productID, userID, models.ReportOpen).
+		Count(&count).Error
+	if err != nil {
+		return false, fiber.NewError(500, err.Error())
+	}
+	return count > 0, nil
+}
+
+// Create implements models.ProductReportRepository.
+func (r *ProductReportRepository) Create(obj models.ProductReport) (models.ProductReport, *fiber.Error) {
+	if err := r.DB.Create(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+	return obj, nil
+}
+
+// Resolve implements models.ProductReportRepository.
+func (r *ProductReportRepository) Resolve(obj models.ProductReport) (models.ProductReport, *fiber.Error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if *obj.Action == models.ModerationHide || *obj.Action == models.ModerationDelete {
+			err := tx.Unscoped().Model(&models.Product{}).Where("id = ?", obj.ProductID).
+				Updates(map[string]interface{}{"hidden_at": obj.ResolvedAt, "hidden_reason": obj.Note}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		resolved := tx.Model(&models.ProductReport{}).Where("id = ?", obj.ID)
+		if *obj.Action != models.ModerationDismiss {
+			// the decision on the product answer all of its reports
+			resolved = tx.Model(&models.ProductReport{}).
+				Where("id = ? OR (product_id = ? AND status = ?)", obj.ID, obj.ProductID, models.ReportOpen)
+		}
+
+		return resolved.Updates(map[string]interface{}{
+			"status":         models.ReportResolved,
+			"action":         obj.Action,
+			"note":           obj.Note,
+			"resolved_at":    obj.ResolvedAt,
+			"resolved_by_id": obj.ResolvedByID,
+		}).Error
+	})
+	if err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// Unhide implements models.ProductReportRepository.
+func (r *ProductReportRepository) Unhide(productID uint) (models.Product, *fiber.Error) {
+	var obj models.Product
+	result := r.DB.Unscoped().First(&obj, productID)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+
+	err := r.DB.Unscoped().Model(&obj).Updates(map[string]interface{}{"hidden_at": nil, "hidden_reason": nil}).Error
+	if err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+	return obj, nil
+}
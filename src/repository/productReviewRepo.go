@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"myapp/pkg/response"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProductReviewRepository struct {
+	DB *gorm.DB
+}
+
+// NewProductReviewRepository will create an object that represent the models.ProductReviewRepository interface
+func NewProductReviewRepository(Conn *gorm.DB) models.ProductReviewRepository {
+	return &ProductReviewRepository{Conn}
+}
+
+// ListReview implements models.ProductReviewRepository.
+func (r *ProductReviewRepository) ListReview(filter models.ReviewFilter, param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
+	var data []*models.ProductReview
+	var pagination response.Pagination
+
+	db := r.DB.Model(&models.ProductReview{}).Preload("User.UserProfile.Status")
+
+	if filter.ProductID != 0 {
+		db = db.Where("product_id = ?", filter.ProductID)
+	}
+	if filter.Status != "" {
+		if filter.AuthorID != 0 {
+			db = db.Where("status = ? OR user_id = ?", filter.Status, filter.AuthorID)
+		} else {
+			db = db.Where("status = ?", filter.Status)
+		}
+	}
+
+	if param.Filter != nil {
+		// the whitelisted filters of the query
+		db = db.Scopes(param.Filter)
+	}
+
+	// 	fill all params pagination
+	pagination.Sort = param.SortQuery
+	pagination.Page = param.Page
+	pagination.Limit = param.Limit
+
+	if param.CursorMode {
+		if err := response.PaginateCursor(db, &data, &pagination, param); err != nil {
+			return nil, err
+		}
+		return &pagination, nil
+	}
+
+	err := db.Scopes(response.Paginate(data, &pagination, db)).Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	pagination.Data = data
+
+	return &pagination, nil
+}
+
+// GetReview implements models.ProductReviewRepository.
+func (r *ProductReviewRepository) GetReview(id uint) (models.ProductReview, *fiber.Error) {
+	var obj models.ProductReview
+	result := r.DB.Preload("User.UserProfile.Status").First(&obj, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// HasReviewed implements models.ProductReviewRepository.
+func (r *ProductReviewRepository) HasReviewed(productID uint, userID uint) (bool, *fiber.Error) {
+	var count int64
+	err := r.DB.Model(&models.ProductReview{}).Where("product_id = ? AND user_id = ?", productID, userID).Count(&count).Error
+	if err != nil {
+		return false, fiber.NewError(500, err.Error())
+	}
+
+	return count > 0, nil
+}
+
+// Create implements models.ProductReviewRepository.
+func (r *ProductReviewRepository) Create(obj models.ProductReview) (models.ProductReview, *fiber.Error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockProduct(tx, obj.ProductID); err != nil {
+			return err
+		}
+		if err := tx.Omit("Product", "User").Create(&obj).Error; err != nil {
+			return err
+		}
+		return refreshRating(tx, obj.ProductID)
+	})
+	if err != nil {
+		return obj, reviewError(err)
+	}
+
+	return obj, nil
+}
+
+// Update implements models.ProductReviewRepository.
+func (r *ProductReviewRepository) Update(obj models.ProductReview) (models.ProductReview, *fiber.Error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockProduct(tx, obj.ProductID); err != nil {
+			return err
+		}
+		if err := tx.Omit("Product", "User").Save(&obj).Error; err != nil {
+			return err
+		}
+		return refreshRating(tx, obj.ProductID)
+	})
+	if err != nil {
+		return obj, reviewError(err)
+	}
+
+	return obj, nil
+}
+
+// Delete implements models.ProductReviewRepository.
+func (r *ProductReviewRepository) Delete(obj models.ProductReview) *fiber.Error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockProduct(tx, obj.ProductID); err != nil {
+			return err
+		}
+		if err := tx.Delete(&obj).Error; err != nil {
+			return err
+		}
+		return refreshRating(tx, obj.ProductID)
+	})
+	if err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	return nil
+}
+
+// lockProduct serialize the reviews of the product, so the concurrent reviews
+// are all counted by the last refreshRating
+func lockProduct(tx *gorm.DB, productID uint) error {
+	var id uint
+	return tx.Model(&models.Product{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").Where("id = ?", productID).Scan(&id).Error
+}
+
+// refreshRating recount the average and the number of the approved reviews of the product
+func refreshRating(tx *gorm.DB, productID uint) error {
+	approved := tx.Session(&gorm.Session{NewDB: true}).Model(&models.ProductReview{}).
+		Where("product_id = ? AND status = ?", productID, models.ReviewApproved)
+
+	return tx.Model(&models.Product{}).Where("id = ?", productID).UpdateColumns(map[string]interface{}{
+		"rating_average": gorm.Expr("(?)", approved.Session(&gorm.Session{}).Select("COALESCE(ROUND(AVG(rating), 2), 0)")),
+		"rating_count":   gorm.Expr("(?)", approved.Session(&gorm.Session{}).Select("COUNT(*)")),
+	}).Error
+}
+
+func reviewError(err error) *fiber.Error {
+	if strings.Contains(err.Error(), "duplicate key value violates unique") {
+		return fiber.NewError(409, "You have already reviewed this product.")
+	}
+	return fiber.NewError(500, err.Error())
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"myapp/pkg/response"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type ProductRevisionRepository struct {
+	DB *gorm.DB
+}
+
+// NewProductRevisionRepository will create an object that represent the models.ProductRevisionRepository interface
+func NewProductRevisionRepository(Conn *gorm.DB) models.ProductRevisionRepository {
+	return &ProductRevisionRepository{Conn}
+}
+
+// ListRevision implements models.ProductRevisionRepository.
+func (r *ProductRevisionRepository) ListRevision(productID uint, param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
+	var data []*models.ProductRevision
+	var pagination response.Pagination
+
+	db := r.DB.Model(&models.ProductRevision{}).Preload("User.UserProfile.Status").Where("product_id = ?", productID)
+
+	if param.Filter != nil {
+		// the whitelisted filters of the query
+		db = db.Scopes(param.Filter)
+	}
+
+	// 	fill all params pagination
+	pagination.Sort = param.SortQuery
+	pagination.Page = param.Page
+	pagination.Limit = param.Limit
+
+	if param.CursorMode {
+		if err := response.PaginateCursor(db, &data, &pagination, param); err != nil {
+			return nil, err
+		}
+		return &pagination, nil
+	}
+
+	err := db.Scopes(response.Paginate(data, &pagination, db)).Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	pagination.Data = data
+
+	return &pagination, nil
+}
+
+// GetRevision implements models.ProductRevisionRepository.
+func (r *ProductRevisionRepository) GetRevision(productID uint, id uint) (models.ProductRevision, *fiber.Error) {
+	var obj models.ProductRevision
+	result := r.DB.Where("product_id = ?", productID).First(&obj, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// RevisionsAfter implements models.ProductRevisionRepository.
+func (r *ProductRevisionRepository) RevisionsAfter(productID uint, id uint) ([]*models.ProductRevision, *fiber.Error) {
+	var data []*models.ProductRevision
+	err := r.DB.Where("product_id = ? AND id > ?", productID, id).Order("id desc").Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+	return data, nil
+}
+
+// GetProductUnscoped implements models.ProductRevisionRepository.
+func (r *ProductRevisionRepository) GetProductUnscoped(id uint) (models.Product, *fiber.Error) {
+	var obj models.Product
+	result := r.DB.Unscoped().Preload("Tags").First(&obj, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"myapp/pkg/configs"
+	"myapp/src/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// the hash of the counts not flushed yet, the field is "day:product_id:metric"
+	productStatBufferKey = "product_stats:buffer"
+	// the buffer being flushed, kept until it's written so a failed flush is retried
+	productStatFlushingKey = "product_stats:flushing"
+	// only one instance flush at the same time
+	productStatLockKey = "product_stats:lock"
+	productStatLockTTL = 5 * time.Minute
+)
+
+type ProductStatRepository struct {
+	DB *gorm.DB
+}
+
+// NewProductStatRepository will create an object that represent the models.ProductStatRepository interface
+func NewProductStatRepository(Conn *gorm.DB) models.ProductStatRepository {
+	return &ProductStatRepository{Conn}
+}
+
+// Track implements models.ProductStatRepository.
+func (*ProductStatRepository) Track(metric models.StatMetric, productIDs ...uint) {
+	if len(productIDs) == 0 {
+		return
+	}
+
+	day := time.Now().UTC().Format(time.DateOnly)
+	ctxTodo := context.TODO()
+	_, err := configs.RedisClient.Pipelined(ctxTodo, func(pipe redis.Pipeliner) error {
+		for _, id := range productIDs {
+			pipe.HIncrBy(ctxTodo, productStatBufferKey, fmt.Sprintf("%s:%d:%s", day, id, metric), 1)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println("Failed to track the product stats:", err.Error())
+	}
+}
+
+// Flush implements models.ProductStatRepository.
+func (r *ProductStatRepository) Flush() (int, error) {
+	ctxTodo := context.TODO()
+
+	locked, err := configs.RedisClient.SetNX(ctxTodo, productStatLockKey, 1, productStatLockTTL).Result()
+	if err != nil {
+		return 0, err
+	}
+	if !locked {
+		return 0, nil
+	}
+	defer configs.RedisClient.Del(ctxTodo, productStatLockKey)
+
+	// the new counts go to a new buffer while this one is written,
+	// a buffer left by a failed flush is written first
+	if _, err := configs.RedisClient.RenameNX(ctxTodo, productStatBufferKey, productStatFlushingKey).Result(); err != nil && !strings.Contains(err.Error(), "no such key") {
+		return 0, err
+	}
+
+	fields, err := configs.RedisClient.HGetAll(ctxTodo, productStatFlushingKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	if len(fields) == 0 {
+		return 0, nil
+	}
+
+	stats := parseStatBuffer(fields)
+
+	// the counts of the purged products are dropped
+	ids := make([]uint, 0, len(stats))
+	for _, stat := range stats {
+		ids = append(ids, stat.ProductID)
+	}
+	var existing []uint
+	if err := r.DB.Unscoped().Model(&models.Product{}).Where("id IN ?", ids).Pluck("id", &existing).Error; err != nil {
+		return 0, err
+	}
+	exists := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		exists[id] = true
+	}
+
+	data := make([]*models.ProductStat, 0, len(stats))
+	for _, stat := range stats {
+		if exists[stat.ProductID] {
+			data = append(data, stat)
+		}
+	}
+
+	if len(data) > 0 {
+		err = r.DB.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "product_id"}, {Name: "day"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"views":       gorm.Expr("product_stats.views + excluded.views"),
+				"impressions": gorm.Expr("product_stats.impressions + excluded.impressions"),
+				"favorites":   gorm.Expr("product_stats.favorites + excluded.favorites"),
+			}),
+		}).Omit("Product").CreateInBatches(&data, 500).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := configs.RedisClient.Del(ctxTodo, productStatFlushingKey).Err(); err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+// parseStatBuffer group the counts of the buffer by product and day, the invalid fields are skipped
+func parseStatBuffer(fields map[string]string) map[string]*models.ProductStat {
+	stats := map[string]*models.ProductStat{}
+	for field, value := range fields {
+		parts := strings.Split(field, ":")
+		if len(parts) != 3 {
+			continue
+		}
+		day, errDay := time.Parse(time.DateOnly, parts[0])
+		productID, errID := strconv.ParseUint(parts[1], 10, 64)
+		count, errCount := strconv.ParseInt(value, 10, 64)
+		if errDay != nil || errID != nil || errCount != nil || productID == 0 {
+			continue
+		}
+
+		key := parts[0] + ":" + parts[1]
+		stat, ok := stats[key]
+		if !ok {
+			stat = &models.ProductStat{ProductID: uint(productID), Day: day}
+			stats[key] = stat
+		}
+
+		switch models.StatMetric(parts[2]) {
+		case models.StatView:
+			stat.Views += count
+		case models.StatImpression:
+			stat.Impressions += count
+		case models.StatFavorite:
+			stat.Favorites += count
+		}
+	}
+	return stats
+}
+
+// ListStat implements models.ProductStatRepository.
+func (r *ProductStatRepository) ListStat(productID uint, period models.StatPeriod) ([]*models.ProductStat, *fiber.Error) {
+	var data []*models.ProductStat
+	err := r.DB.Where("product_id = ? AND day BETWEEN ? AND ?", productID, period.From, period.To).
+		Order("day").Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+	return data, nil
+}
+
+// TopProducts implements models.ProductStatRepository.
+func (r *ProductStatRepository) TopProducts(period models.StatPeriod, metric models.StatMetric, limit int) ([]*models.TopProduct, *fiber.Error) {
+	var data []*models.TopProduct
+	err := r.DB.Model(&models.ProductStat{}).
+		Select("product_id, SUM(views) AS views, SUM(impressions) AS impressions, SUM(favorites) AS favorites").
+		Where("day BETWEEN ? AND ?", period.From, period.To).
+		Group("product_id").
+		// the metric is one of the constants, the product ID break the ties
+		Order(fmt.Sprintf("%s desc, product_id", metric)).
+		Limit(limit).Scan(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+	if len(data) == 0 {
+		return data, nil
+	}
+
+	ids := make([]uint, 0, len(data))
+	for _, top := range data {
+		ids = append(ids, top.ProductID)
+	}
+	var products []*models.Product
+	if err := r.DB.Unscoped().Where("id IN ?", ids).Find(&products).Error; err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+	byID := make(map[uint]*models.Product, len(products))
+	for _, obj := range products {
+		byID[obj.ID] = obj
+	}
+	for _, top := range data {
+		top.Product = byID[top.ProductID]
+	}
+
+	return data, nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"fmt"
+	"myapp/pkg/configs"
+	"myapp/pkg/helpers"
+	"myapp/pkg/response"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProductVariantRepository struct {
+	DB *gorm.DB
+}
+
+// NewProductVariantRepository will create an object that represent the models.ProductVariantRepository interface
+func NewProductVariantRepository(Conn *gorm.DB) models.ProductVariantRepository {
+	return &ProductVariantRepository{Conn}
+}
+
+// SendLowStockEmail implements models.ProductVariantRepository.
+func (*ProductVariantRepository) SendLowStockEmail(owner models.User, product models.Product, obj models.ProductVariant) error {
+	siteData, _ := configs.GetSiteData(".")
+	emailData := helpers.EmailData{
+		URL:          fmt.Sprintf("%s/products/%d", siteData.ClientOrigin, product.ID),
+		FirstName:    owner.FirstName,
+		Subject:      "Low stock: " + product.Title + " (" + obj.SKU + ")",
+		Message:      fmt.Sprintf("%s (%s) has %d item(s) left in stock.", product.Title, obj.SKU, obj.Stock),
+		TypeOfAction: "Low Stock",
+		SiteData:     siteData,
+	}
+
+	// send email with goroutine
+	go helpers.SendEmail(owner, &emailData, "low_stock.html")
+
+	return nil
+}
+
+// ListVariant implements models.ProductVariantRepository.
+func (r *ProductVariantRepository) ListVariant(productID uint) ([]*models.ProductVariant, *fiber.Error) {
+	var data []*models.ProductVariant
+	if err := r.DB.Where("product_id = ?", productID).Order("id asc").Find(&data).Error; err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
+
+// GetVariant implements models.ProductVariantRepository.
+func (r *ProductVariantRepository) GetVariant(productID uint, id uint) (models.ProductVariant, *fiber.Error) {
+	var obj models.ProductVariant
+	result := r.DB.First(&obj, "product_id = ? AND id = ?", productID, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// Create implements models.ProductVariantRepository.
+func (r *ProductVariantRepository) Create(obj models.ProductVariant, userID uint) (models.ProductVariant, *fiber.Error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Product").Create(&obj).Error; err != nil {
+			return err
+		}
+
+		// the initial stock is the first history
+		return tx.Create(&models.StockMovement{
+			Change:           obj.Stock,
+			StockAfter:       obj.Stock,
+			Reason:           models.StockInitial,
+			ProductVariantID: obj.ID,
+			UserID:           &userID,
+		}).Error
+	})
+	if err != nil {
+		return obj, skuError(err)
+	}
+
+	return obj, nil
+}
+
+// Update implements models.ProductVariantRepository.
+func (r *ProductVariantRepository) Update(obj models.ProductVariant) (models.ProductVariant, *fiber.Error) {
+	// the stock is only changed by AdjustStock
+	if err := r.DB.Omit("Product", "Stock").Save(&obj).Error; err != nil {
+		return obj, skuError(err)
+	}
+
+	return obj, nil
+}
+
+// Delete implements models.ProductVariantRepository.
+func (r *ProductVariantRepository) Delete(obj models.ProductVariant) *fiber.Error {
+	if err := r.DB.Delete(&obj).Error; err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	return nil
+}
+
+// AdjustStock implements models.ProductVariantRepository.
+func (r *ProductVariantRepository) AdjustStock(obj models.ProductVariant, movement models.StockMovement) (models.ProductVariant, models.StockMovement, *fiber.Error) {
+	var errD *fiber.Error
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		// change the stock in single statement, so the concurrent adjustments can't lost
+		result := tx.Model(&obj).Clauses(clause.Returning{}).
+			Where("stock + ? >= 0", movement.Change).
+			UpdateColumn("stock", gorm.Expr("stock + ?", movement.Change))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			errD = fiber.NewError(422, "Insufficient stock.")
+			return errD
+		}
+
+		movement.ProductVariantID = obj.ID
+		movement.StockAfter = obj.Stock
+		return tx.Create(&movement).Error
+	})
+	if errD != nil {
+		return obj, movement, errD
+	}
+	if err != nil {
+		return obj, movement, fiber.NewError(500, err.Error())
+	}
+
+	return obj, movement, nil
+}
+
+// ListStockMovement implements models.ProductVariantRepository.
+func (r *ProductVariantRepository) ListStockMovement(variantID uint, param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
+	var data []*models.StockMovement
+	var pagination response.Pagination
+
+	db := r.DB.Model(&models.StockMovement{}).Where("product_variant_id = ?", variantID)
+
+	if param.Filter != nil {
+		// the whitelisted filters of the query
+		db = db.Scopes(param.Filter)
+	}
+
+	// 	fill all params pagination
+	pagination.Sort = param.SortQuery
+	pagination.Page = param.Page
+	pagination.Limit = param.Limit
+
+	if param.CursorMode {
+		if err := response.PaginateCursor(db, &data, &pagination, param); err != nil {
+			return nil, err
+		}
+		return &pagination, nil
+	}
+
+	err := db.Scopes(response.Paginate(data, &pagination, db)).Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	pagination.Data = data
+
+	return &pagination, nil
+}
+
+func skuError(err error) *fiber.Error {
+	if strings.Contains(err.Error(), "duplicate key value violates unique") {
+		return fiber.NewError(422, "The SKU is already used by another variant.")
+	}
+	return fiber.NewError(500, err.Error())
+}
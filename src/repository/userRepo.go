@@ -8,6 +8,7 @@ import (
 	"myapp/pkg/response"
 	"myapp/pkg/utils"
 	"myapp/src/models"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -39,9 +40,15 @@ func (r *UserRepository) ListUser(param response.ParamsPagination) (*response.Pa
 
 	if param.Search != "" {
 		// search data based on first_name, last_name, email
-		db = db.Where("first_name ILIKE ?", "%"+param.Search+"%").
+		// grouped, so the OR can't escape the filters
+		db = db.Where(r.DB.Where("first_name ILIKE ?", "%"+param.Search+"%").
 			Or("last_name ILIKE ?", "%"+param.Search+"%").
-			Or("email ILIKE ?", "%"+param.Search+"%")
+			Or("email ILIKE ?", "%"+param.Search+"%"))
+	}
+
+	if param.Filter != nil {
+		// the whitelisted filters of the query
+		db = db.Scopes(param.Filter)
 	}
 
 	// 	fill all params pagination
@@ -56,6 +63,13 @@ func (r *UserRepository) ListUser(param response.ParamsPagination) (*response.Pa
 		return nil, data, nil
 	}
 
+	if param.CursorMode {
+		if err := response.PaginateCursor(db, &data, &pagination, param); err != nil {
+			return nil, nil, err
+		}
+		return &pagination, nil, nil
+	}
+
 	err := db.Scopes(response.Paginate(data, &pagination, db)).Find(&data).Error
 	if err != nil {
 		return nil, nil, fiber.NewError(500, err.Error())
@@ -79,18 +93,160 @@ func (r *UserRepository) RestoreUser(id uint) *fiber.Error {
 
 // PermanentDelete implements models.UserRepository.
 func (r *UserRepository) PermanentDelete(user models.User) *fiber.Error {
-	// delete user
-	err := r.DB.Select(clause.Associations).Unscoped().Delete(&user).Error
+	var products []*models.Product
+	var drives []*models.MyDrive
+	var photos []string
+	var exports []*models.DataExport
+	var imports []*models.ProductImport
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		// the data of the shared organizations stay with the other members
+		if err := reassignOrganizationData(tx, user.ID); err != nil {
+			return err
+		}
+
+		// collect the files before the rows are gone
+		tx.Unscoped().Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+			Where("user_id = ?", user.ID).Find(&products)
+		tx.Unscoped().Where("user_id = ?", user.ID).Find(&drives)
+		// the reviews of the user and the reviews of their products
+		tx.Unscoped().Model(&models.ProductReview{}).
+			Where("photo IS NOT NULL").
+			Where(tx.Where("user_id = ?", user.ID).
+				Or("product_id IN (?)", tx.Unscoped().Model(&models.Product{}).Select("id").Where("user_id = ?", user.ID))).
+			Pluck("photo", &photos)
+		tx.Where("user_id = ?", user.ID).Find(&exports)
+		tx.Where("user_id = ?", user.ID).Find(&imports)
+
+		// delete all user data
+		if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(&models.Product{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(&models.MyDrive{}).Error; err != nil {
+			return err
+		}
+
+		// delete user
+		return tx.Select(clause.Associations).Unscoped().Delete(&user).Error
+	})
 	if err != nil {
 		return fiber.NewError(500, err.Error())
 	}
 
+	// remove files from server, skip the file on S3
+	for _, obj := range products {
+		if obj.Image != nil && !strings.HasPrefix(*obj.Image, "http") {
+			utils.RemoveFileSilence(*obj.Image, string(models.ImageFile))
+		}
+		for _, image := range obj.Images {
+			if image.IsLocal() {
+				utils.RemoveFileSilence(image.Path, string(models.ImageFile))
+			}
+		}
+	}
+	for _, obj := range drives {
+		if !strings.HasPrefix(obj.Link, "http") {
+			utils.RemoveFileSilence(obj.Link, string(obj.FileType))
+		}
+	}
+	if photo := user.UserProfile.Photo; photo != nil && !strings.HasPrefix(*photo, "http") {
+		utils.RemoveFileSilence(*photo, string(models.ImageFile))
+	}
+	for _, photo := range photos {
+		if !strings.HasPrefix(photo, "http") {
+			utils.RemoveFileSilence(photo, string(models.ImageFile))
+		}
+	}
+	// the archives and the uploaded spreadsheets are outside of the media folder
+	for _, obj := range exports {
+		if obj.FilePath != "" {
+			os.Remove(obj.FilePath)
+		}
+	}
+	for _, obj := range imports {
+		for _, path := range []string{obj.FilePath, obj.ReportPath} {
+			if path != "" {
+				os.Remove(path)
+			}
+		}
+	}
+
 	// goroutine - delete all otpotpR by email
 	go r.deleteAllOTPRequestByEmail(user.Email)
 
 	return nil
 }
 
+// reassignOrganizationData give the products and the files the user created in an organization
+// to the oldest owner, else admin, else member of the organization.
+// The organization without another member is purged with the user.
+func reassignOrganizationData(tx *gorm.DB, userID uint) error {
+	var orgIDs []uint
+	err := tx.Unscoped().Model(&models.Product{}).Where("user_id = ? AND organization_id IS NOT NULL", userID).
+		Distinct().Pluck("organization_id", &orgIDs).Error
+	if err != nil {
+		return err
+	}
+	var driveOrgIDs []uint
+	err = tx.Unscoped().Model(&models.MyDrive{}).Where("user_id = ? AND organization_id IS NOT NULL", userID).
+		Distinct().Pluck("organization_id", &driveOrgIDs).Error
+	if err != nil {
+		return err
+	}
+
+	seen := map[uint]bool{}
+	for _, orgID := range append(orgIDs, driveOrgIDs...) {
+		if seen[orgID] {
+			continue
+		}
+		seen[orgID] = true
+
+		var members []*models.OrganizationMember
+		if err := tx.Where("organization_id = ? AND user_id <> ?", orgID, userID).Order("id").Find(&members).Error; err != nil {
+			return err
+		}
+		successor := organizationSuccessor(members)
+		if successor == nil {
+			continue
+		}
+
+		// the import key of the product can't clash with the one of the successor
+		err := tx.Unscoped().Model(&models.Product{}).
+			Where("user_id = ? AND organization_id = ?", userID, orgID).
+			Where("external_id IN (?)", tx.Model(&models.Product{}).Select("external_id").
+				Where("user_id = ? AND external_id IS NOT NULL", successor.UserID)).
+			Update("external_id", nil).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Model(&models.Product{}).Where("user_id = ? AND organization_id = ?", userID, orgID).
+			Update("user_id", successor.UserID).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Model(&models.MyDrive{}).Where("user_id = ? AND organization_id = ?", userID, orgID).
+			Update("user_id", successor.UserID).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// organizationSuccessor choose the member who receive the data of a purged member, nil without member
+func organizationSuccessor(members []*models.OrganizationMember) *models.OrganizationMember {
+	for _, role := range []models.OrganizationRole{models.OrgRoleOwner, models.OrgRoleAdmin, models.OrgRoleMember} {
+		for _, member := range members {
+			if member.Role == role {
+				return member
+			}
+		}
+	}
+	return nil
+}
+
 // Delete implements models.UserRepository.
 func (r *UserRepository) Delete(user models.User) *fiber.Error {
 	// delete user
@@ -276,6 +432,114 @@ func (*UserRepository) SendVerificationEmail(user models.User, code string) erro
 	return nil
 }
 
+// SendDeletionEmail implements models.UserRepository.
+func (*UserRepository) SendDeletionEmail(user models.User, deletion models.AccountDeletion, code string) error {
+	var accountName = user.FirstName
+	if accountName == "" {
+		accountName = user.Email
+	}
+
+	siteData, _ := configs.GetSiteData(".")
+	emailData := helpers.EmailData{
+		URL:          siteData.ClientOrigin + "/cancel-deletion/" + code,
+		FirstName:    accountName,
+		Subject:      "Your account will be deleted",
+		Message:      "Your account and all of your data will be deleted permanently on " + deletion.ScheduledAt.UTC().Format("02 January 2006 15:04 MST") + ".",
+		TypeOfAction: "Delete Account",
+		SiteData:     siteData,
+	}
+
+	// send email with goroutine
+	go helpers.SendEmail(user, &emailData, "account_deletion.html")
+
+	return nil
+}
+
+// ScheduleDeletion implements models.UserRepository.
+func (r *UserRepository) ScheduleDeletion(obj models.AccountDeletion) (models.AccountDeletion, *fiber.Error) {
+	if err := r.DB.Create(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// FindPendingDeletion implements models.UserRepository.
+func (r *UserRepository) FindPendingDeletion(userID uint) (models.AccountDeletion, *fiber.Error) {
+	var obj models.AccountDeletion
+
+	result := r.DB.First(&obj, "user_id = ? AND status = ?", userID, models.DeletionPending)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, "There is no pending deletion for this account.")
+	}
+	return obj, nil
+}
+
+// FindDeletionByCode implements models.UserRepository.
+func (r *UserRepository) FindDeletionByCode(code string) (models.AccountDeletion, *fiber.Error) {
+	var obj models.AccountDeletion
+
+	result := r.DB.First(&obj, "code = ? AND status = ?", utils.Encode(code), models.DeletionPending)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, "Invalid cancel code or the deletion is no longer pending.")
+	}
+	return obj, nil
+}
+
+// UpdateDeletion implements models.UserRepository.
+func (r *UserRepository) UpdateDeletion(obj models.AccountDeletion) *fiber.Error {
+	if err := r.DB.Omit("User").Save(&obj).Error; err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	return nil
+}
+
+// ListDueDeletion implements models.UserRepository.
+func (r *UserRepository) ListDueDeletion(now time.Time) ([]*models.AccountDeletion, *fiber.Error) {
+	var data []*models.AccountDeletion
+
+	err := r.DB.Where("status = ? AND scheduled_at <= ?", models.DeletionPending, now).
+		Order("scheduled_at").Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
+
+// ListPendingDeletion implements models.UserRepository.
+func (r *UserRepository) ListPendingDeletion(param response.ParamsPagination) (*response.Pagination, *fiber.Error) {
+	var data []*models.AccountDeletion
+	var pagination response.Pagination
+
+	db := r.DB.Model(&models.AccountDeletion{}).Where("status = ?", models.DeletionPending)
+
+	if param.Search != "" {
+		// search data based on email
+		db = db.Where("email ILIKE ?", "%"+param.Search+"%")
+	}
+
+	if param.Filter != nil {
+		// the whitelisted filters of the query
+		db = db.Scopes(param.Filter)
+	}
+
+	// 	fill all params pagination
+	pagination.Sort = param.SortQuery
+	pagination.Page = param.Page
+	pagination.Limit = param.Limit
+
+	err := db.Scopes(response.Paginate(data, &pagination, db)).Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	pagination.Data = data
+
+	return &pagination, nil
+}
+
 // ResendVerificationCode implements models.UserRepository.
 func (r *UserRepository) ResendVerificationCode(user models.User) *fiber.Error {
 	if user.Verified {
@@ -292,7 +556,9 @@ func (r *UserRepository) ResendVerificationCode(user models.User) *fiber.Error {
 	r.DB.Save(&user)
 
 	// Send verification email
-	r.SendVerificationEmail(user, code)
+	if !user.Verified {
+		r.SendVerificationEmail(user, code)
+	}
 
 	return nil
 }
@@ -346,13 +612,32 @@ func (r *UserRepository) UsernameExists(username string) *fiber.Error {
 
 // Create implements models.UserRepository.
 func (r *UserRepository) Create(obj models.User) *fiber.Error {
-	panic("unimplemented")
+	err := r.DB.Create(&obj).Error
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key value violates unique") {
+			return fiber.NewError(422, "user with that email/username already exists")
+		}
+
+		return fiber.NewError(500, err.Error())
+	}
+
+	return nil
+}
+
+// FindUserUnscoped implements models.UserRepository.
+func (r *UserRepository) FindUserUnscoped(id uint) (models.User, *fiber.Error) {
+	var user models.User
+	result := r.DB.Unscoped().Preload("UserProfile").Find(&user, id)
+	if result.RowsAffected == 0 {
+		return user, fiber.NewError(404, "Account doesn't exists.")
+	}
+	return user, nil
 }
 
 // FindDeletedUserByEmail implements models.UserRepository.
 func (r *UserRepository) FindDeletedUserByEmail(email string) (models.User, *fiber.Error) {
 	var user models.User
-	result := r.DB.Unscoped().Find(&user, "email = ?", email)
+	result := r.DB.Unscoped().Preload("UserProfile").Find(&user, "email = ?", email)
 	if result.RowsAffected == 0 {
 		return user, fiber.NewError(404, "Account doesn't exists.")
 	}
@@ -383,7 +668,7 @@ func (r *UserRepository) FindUserByEmail(email string) (models.User, *fiber.Erro
 // FindUserByIdentity implements models.UserRepository.
 func (r *UserRepository) FindUserByIdentity(identity string) (models.User, *fiber.Error) {
 	var user models.User
-	result := r.DB.Where("username = ?", strings.ToLower(identity)).Or("email = ?", strings.ToLower(identity)).First(&user)
+	result := r.DB.Preload("UserProfile").Where("username = ?", strings.ToLower(identity)).Or("email = ?", strings.ToLower(identity)).First(&user)
 	if result.RowsAffected == 0 {
 		return user, fiber.NewError(422, "Invalid Email or Account doesn't exists.")
 	}
@@ -412,14 +697,17 @@ func (r *UserRepository) RefreshToken(payload models.RefreshTokenInput) (models.
 	}
 
 	var user models.User
-	err = r.DB.First(&user, "id = ?", tokenClaims.UserID).Error
+	err = r.DB.Preload("UserProfile").First(&user, "id = ?", tokenClaims.UserID).Error
 
 	if err == gorm.ErrRecordNotFound {
 		return token, fiber.NewError(404, "the user belonging to this token no logger exists")
 	}
+	if user.IsLocked() {
+		return token, fiber.NewError(403, utils.ERR_ACCOUNT_LOCKED)
+	}
 
 	// generate new tokens
-	token, err = r.GeneratePairToken(tokenClaims.UserID)
+	token, err = r.GeneratePairToken(tokenClaims.UserID, tokenClaims.OrganizationID)
 	if err != nil {
 		return token, fiber.NewError(404, err.Error())
 	}
@@ -428,10 +716,10 @@ func (r *UserRepository) RefreshToken(payload models.RefreshTokenInput) (models.
 }
 
 // GeneratePairToken implements models.UserRepository.
-func (r *UserRepository) GeneratePairToken(userID uint) (models.Token, error) {
+func (r *UserRepository) GeneratePairToken(userID uint, organizationID uint) (models.Token, error) {
 	token := models.Token{}
 
-	td, err := helpers.CreateToken(userID)
+	td, err := helpers.CreateToken(userID, organizationID)
 	if err != nil {
 		return token, err
 	}
@@ -463,7 +751,7 @@ func (r *UserRepository) GeneratePairToken(userID uint) (models.Token, error) {
 
 // Login implements models.UserRepository.
 func (r *UserRepository) Login(user models.User) (models.Token, *fiber.Error) {
-	token, err := r.GeneratePairToken(user.ID)
+	token, err := r.GeneratePairToken(user.ID, 0)
 	if err != nil {
 		return token, fiber.NewError(500, err.Error())
 	}
@@ -472,13 +760,18 @@ func (r *UserRepository) Login(user models.User) (models.Token, *fiber.Error) {
 
 // Register implements models.UserRepository.
 func (r *UserRepository) Register(user models.User) *fiber.Error {
-	// Generate Verification Code
-	code := randstr.String(64)
+	var code string
 
-	verification_code := utils.Encode(code)
+	// user from invitation is already verified
+	if !user.Verified {
+		// Generate Verification Code
+		code = randstr.String(64)
 
-	// fill User verification code
-	user.VerificationCode = verification_code
+		verification_code := utils.Encode(code)
+
+		// fill User verification code
+		user.VerificationCode = verification_code
+	}
 
 	err := r.DB.Create(&user).Error
 	if err != nil {
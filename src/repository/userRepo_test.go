@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"myapp/src/models"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestOrganizationSuccessor(t *testing.T) {
+	member := &models.OrganizationMember{Role: models.OrgRoleMember, UserID: 1}
+	admin := &models.OrganizationMember{Role: models.OrgRoleAdmin, UserID: 2}
+	owner := &models.OrganizationMember{Role: models.OrgRoleOwner, UserID: 3}
+	secondOwner := &models.OrganizationMember{Role: models.OrgRoleOwner, UserID: 4}
+
+	tests := []struct {
+		members []*models.OrganizationMember
+		want    *models.OrganizationMember
+	}{
+		{[]*models.OrganizationMember{member, admin, owner, secondOwner}, owner},
+		{[]*models.OrganizationMember{member, admin}, admin},
+		{[]*models.OrganizationMember{member}, member},
+		{nil, nil},
+	}
+
+	for _, tt := range tests {
+		if got := organizationSuccessor(tt.members); got != tt.want {
+			t.Errorf("got %+v, want %+v", got, tt.want)
+		}
+	}
+}
+
+// TestReassignOrganizationData check the rows of the shared organization go to its owner,
+// the organization 2 has no other member so its rows are purged with the user
+func TestReassignOrganizationData(t *testing.T) {
+	// the purge run the reassignment in its transaction
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Callback().Query().After("gorm:query").Register("test:rows", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]uint:
+			// the organizations of the products, then of the files
+			if tx.Statement.Table == "products" {
+				*dest = []uint{1, 2}
+			} else {
+				*dest = []uint{1}
+			}
+		case *[]*models.OrganizationMember:
+			if tx.Statement.Vars[0] == uint(1) {
+				*dest = []*models.OrganizationMember{
+					{Role: models.OrgRoleAdmin, UserID: 8},
+					{Role: models.OrgRoleOwner, UserID: 9},
+				}
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var updates []string
+	err = db.Callback().Update().After("gorm:update").Register("test:sql", func(tx *gorm.DB) {
+		updates = append(updates, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := reassignOrganizationData(db, 7); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`UPDATE "products" SET "external_id"=NULL,"updated_at"=`,
+		`UPDATE "products" SET "user_id"=9,`,
+		`UPDATE "my_drives" SET "user_id"=9,`,
+	}
+	if len(updates) != len(want) {
+		t.Fatalf("got %d updates, want %d:\n%s", len(updates), len(want), strings.Join(updates, "\n"))
+	}
+	for i, sql := range updates {
+		if !strings.HasPrefix(sql, want[i]) {
+			t.Errorf("got %s, want %s...", sql, want[i])
+		}
+		if !strings.Contains(sql, "user_id = 7 AND organization_id = 1") {
+			t.Errorf("%s should only change the rows of the user in the organization 1", sql)
+		}
+	}
+	if !strings.Contains(updates[0], `SELECT "external_id" FROM "products" WHERE (user_id = 9 AND external_id IS NOT NULL)`) {
+		t.Errorf("the import keys of the owner should be kept: %s", updates[0])
+	}
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WishlistRepository struct {
+	DB *gorm.DB
+}
+
+// NewWishlistRepository will create an object that represent the models.WishlistRepository interface
+func NewWishlistRepository(Conn *gorm.DB) models.WishlistRepository {
+	return &WishlistRepository{Conn}
+}
+
+// FavoriteStats implements models.WishlistRepository.
+func (r *WishlistRepository) FavoriteStats(userID uint, productIDs []uint) (map[uint]models.FavoriteStat, *fiber.Error) {
+	stats := map[uint]models.FavoriteStat{}
+	if len(productIDs) == 0 {
+		return stats, nil
+	}
+
+	// one query for the whole page, the favorites are the default wishlists
+	var rows []models.FavoriteStat
+	err := r.DB.Table("wishlist_items").
+		Select("wishlist_items.product_id, COUNT(*) AS total, BOOL_OR(wishlists.user_id = ?) AS favorited", userID).
+		Joins("JOIN wishlists ON wishlists.id = wishlist_items.wishlist_id AND wishlists.is_default").
+		Where("wishlist_items.product_id IN ?", productIDs).
+		Group("wishlist_items.product_id").Scan(&rows).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	for _, row := range rows {
+		stats[row.ProductID] = row
+	}
+
+	return stats, nil
+}
+
+// ListWishlist implements models.WishlistRepository.
+func (r *WishlistRepository) ListWishlist(userID uint) ([]*models.Wishlist, *fiber.Error) {
+	var data []*models.Wishlist
+	err := r.DB.Select("wishlists.*, (SELECT COUNT(*) FROM wishlist_items WHERE wishlist_items.wishlist_id = wishlists.id) AS item_count").
+		Where("user_id = ?", userID).
+		Order("is_default desc, name asc").Find(&data).Error
+	if err != nil {
+		return nil, fiber.NewError(500, err.Error())
+	}
+
+	return data, nil
+}
+
+// GetWishlist implements models.WishlistRepository.
+func (r *WishlistRepository) GetWishlist(userID uint, id uint) (models.Wishlist, *fiber.Error) {
+	var obj models.Wishlist
+	result := r.DB.Preload("Items", orderWishlistItems).Preload("Items.Product.Images", orderImages).
+		Where("user_id = ?", userID).First(&obj, id)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// GetShared implements models.WishlistRepository.
+func (r *WishlistRepository) GetShared(token string) (models.Wishlist, *fiber.Error) {
+	var obj models.Wishlist
+
+	// the visitor only see the live products
+	live := func(db *gorm.DB) *gorm.DB {
+		return orderWishlistItems(db).Where("product_id IN (?)",
+			r.DB.Model(&models.Product{}).Select("id").Scopes(models.LiveProduct(time.Now())))
+	}
+	result := r.DB.Preload("Items", live).Preload("Items.Product.Images", orderImages).
+		Where("share_token = ?", token).First(&obj)
+	if result.RowsAffected == 0 {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return obj, nil
+}
+
+// DefaultWishlist implements models.WishlistRepository.
+func (r *WishlistRepository) DefaultWishlist(userID uint) (models.Wishlist, *fiber.Error) {
+	obj := models.Wishlist{Name: "Favorites", IsDefault: true, UserID: userID}
+
+	// the concurrent request may have created it, the unique index keep one default list
+	err := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Omit("User", "Items").Create(&obj).Error
+	if err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+	if obj.ID != 0 {
+		return obj, nil
+	}
+
+	if err := r.DB.Where("user_id = ? AND is_default", userID).First(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+	return obj, nil
+}
+
+// Create implements models.WishlistRepository.
+func (r *WishlistRepository) Create(obj models.Wishlist) (models.Wishlist, *fiber.Error) {
+	if err := r.DB.Omit("User", "Items").Create(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// Update implements models.WishlistRepository.
+func (r *WishlistRepository) Update(obj models.Wishlist) (models.Wishlist, *fiber.Error) {
+	if err := r.DB.Omit("User", "Items").Save(&obj).Error; err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	return obj, nil
+}
+
+// Delete implements models.WishlistRepository.
+func (r *WishlistRepository) Delete(obj models.Wishlist) *fiber.Error {
+	// the items are removed by the foreign key
+	if err := r.DB.Delete(&obj).Error; err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+
+	return nil
+}
+
+// AddItem implements models.WishlistRepository.
+func (r *WishlistRepository) AddItem(obj models.Wishlist, productID uint) (bool, *fiber.Error) {
+	var errD *fiber.Error
+	var added bool
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		// lock the list, so the concurrent requests can't exceed the limit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Wishlist{}, obj.ID).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.WishlistItem{}).Where("wishlist_id = ?", obj.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= models.MaxWishlistItems {
+			errD = fiber.NewError(422, "The wishlist is full.")
+			return errD
+		}
+
+		// the saved product is kept as is
+		item := models.WishlistItem{WishlistID: obj.ID, ProductID: productID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit("Wishlist", "Product").Create(&item)
+		added = result.RowsAffected > 0
+		return result.Error
+	})
+	if errD != nil {
+		return false, errD
+	}
+	if err != nil {
+		return false, fiber.NewError(500, err.Error())
+	}
+
+	return added, nil
+}
+
+// RemoveItem implements models.WishlistRepository.
+func (r *WishlistRepository) RemoveItem(obj models.Wishlist, productID uint) *fiber.Error {
+	result := r.DB.Where("wishlist_id = ? AND product_id = ?", obj.ID, productID).Delete(&models.WishlistItem{})
+	if result.Error != nil {
+		return fiber.NewError(500, result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+
+	return nil
+}
+
+// orderWishlistItems preload the items with the last saved first
+func orderWishlistItems(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at desc, id desc")
+}
@@ -0,0 +1,362 @@
+package usecase
+
+import (
+	"fmt"
+	"myapp/pkg/money"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CartUsecase struct {
+	cRepo models.CartRepository
+	pRepo models.ProductRepository
+	oRepo models.OrderRepository
+	vRepo models.ProductVariantRepository
+}
+
+// NewCartUsecase will create an object that represent the models.CartUsecase interface
+func NewCartUsecase(cart models.CartRepository, product models.ProductRepository, order models.OrderRepository, variant models.ProductVariantRepository) models.CartUsecase {
+	return &CartUsecase{
+		cRepo: cart,
+		pRepo: product,
+		oRepo: order,
+		vRepo: variant,
+	}
+}
+
+// GetCart implements models.CartUsecase.
+func (uc *CartUsecase) GetCart(c *fiber.Ctx) (models.Cart, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Cart{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	items, err := uc.cRepo.ListItem(tenant.UserID)
+	if err != nil {
+		return models.Cart{}, err
+	}
+
+	return priceCart(items), nil
+}
+
+// AddItem implements models.CartUsecase.
+func (uc *CartUsecase) AddItem(c *fiber.Ctx, payload models.CartItemInput) (models.Cart, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Cart{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	product, err := uc.pRepo.GetProduct(tenant, payload.ProductID)
+	if err != nil {
+		return models.Cart{}, err
+	}
+	if product.UserID == tenant.UserID {
+		return models.Cart{}, fiber.NewError(422, "You can't buy your own product.")
+	}
+
+	obj := models.CartItem{
+		UserID:           tenant.UserID,
+		ProductID:        product.ID,
+		ProductVariantID: payload.VariantID,
+		Quantity:         payload.Quantity,
+		Product:          &product,
+	}
+
+	// the variant must belong to the product
+	if payload.VariantID != nil {
+		for _, variant := range product.Variants {
+			if variant.ID == *payload.VariantID {
+				obj.ProductVariant = variant
+			}
+		}
+		if obj.ProductVariant == nil {
+			return models.Cart{}, fiber.NewError(422, "The variant doesn't belong to the product.")
+		}
+	}
+
+	// the quantity already in the cart is added
+	items, err := uc.cRepo.ListItem(tenant.UserID)
+	if err != nil {
+		return models.Cart{}, err
+	}
+	total := obj
+	for _, item := range items {
+		if item.ProductID == obj.ProductID && sameVariant(item.ProductVariantID, obj.ProductVariantID) {
+			total.Quantity += item.Quantity
+		}
+	}
+	if problem := cartProblem(&total, time.Now()); problem != "" {
+		return models.Cart{}, fiber.NewError(422, problem)
+	}
+
+	if _, err := uc.cRepo.AddItem(obj); err != nil {
+		return models.Cart{}, err
+	}
+
+	return uc.GetCart(c)
+}
+
+// UpdateItem implements models.CartUsecase.
+func (uc *CartUsecase) UpdateItem(c *fiber.Ctx, payload models.CartQuantityInput) (models.Cart, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Cart{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.cRepo.GetItem(tenant.UserID, utils.StringToUint(c.Params("item_id")))
+	if err != nil {
+		return models.Cart{}, err
+	}
+
+	obj.Quantity = payload.Quantity
+	if problem := cartProblem(&obj, time.Now()); problem != "" {
+		return models.Cart{}, fiber.NewError(422, problem)
+	}
+
+	if _, err := uc.cRepo.UpdateItem(obj); err != nil {
+		return models.Cart{}, err
+	}
+
+	return uc.GetCart(c)
+}
+
+// RemoveItem implements models.CartUsecase.
+func (uc *CartUsecase) RemoveItem(c *fiber.Ctx) (models.Cart, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Cart{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.cRepo.GetItem(tenant.UserID, utils.StringToUint(c.Params("item_id")))
+	if err != nil {
+		return models.Cart{}, err
+	}
+
+	if err := uc.cRepo.RemoveItem(obj); err != nil {
+		return models.Cart{}, err
+	}
+
+	return uc.GetCart(c)
+}
+
+// Clear implements models.CartUsecase.
+func (uc *CartUsecase) Clear(c *fiber.Ctx) *fiber.Error {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	return uc.cRepo.Clear(tenant.UserID)
+}
+
+// Checkout implements models.CartUsecase.
+func (uc *CartUsecase) Checkout(c *fiber.Ctx, payload models.CheckoutInput) ([]*models.Order, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	items, err := uc.cRepo.ListItem(tenant.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart := priceCart(items)
+	if len(cart.Items) == 0 {
+		return nil, fiber.NewError(422, "The cart is empty.")
+	}
+	for _, item := range cart.Items {
+		// the trashed or purged product isn't loaded
+		if item.Product == nil {
+			return nil, fiber.NewError(422, "A product in your cart is no longer available, please remove it.")
+		}
+		if item.Problem != "" {
+			return nil, fiber.NewError(422, item.Product.Title+": "+item.Problem)
+		}
+	}
+	if cart.Total == nil {
+		return nil, fiber.NewError(422, "The items of the cart have different currencies, please checkout one currency at a time.")
+	}
+
+	// one order per seller, the items keep the snapshot of the product
+	var orders []*models.Order
+	sellers := map[string]*models.Order{}
+	for _, item := range cart.Items {
+		product := item.Product
+		key := fmt.Sprintf("%d:%v", product.UserID, orgKey(product.OrganizationID))
+		order, ok := sellers[key]
+		if !ok {
+			order = &models.Order{
+				Status:          models.OrderPending,
+				Total:           money.New(0, cart.Total.Currency),
+				ShippingAddress: strings.TrimSpace(payload.ShippingAddress),
+				Note:            strings.TrimSpace(payload.Note),
+				BuyerID:         &tenant.UserID,
+				SellerID:        &product.UserID,
+				OrganizationID:  product.OrganizationID,
+			}
+			sellers[key] = order
+			orders = append(orders, order)
+		}
+
+		line := &models.OrderItem{
+			Title:            product.Title,
+			UnitPrice:        *item.UnitPrice,
+			Quantity:         item.Quantity,
+			Subtotal:         *item.Subtotal,
+			ProductID:        &product.ID,
+			ProductVariantID: item.ProductVariantID,
+		}
+		if item.ProductVariant != nil {
+			line.SKU = item.ProductVariant.SKU
+			line.Options = item.ProductVariant.Options
+		}
+		order.Items = append(order.Items, line)
+
+		total, errTotal := order.Total.Add(line.Subtotal)
+		if errTotal != nil {
+			return nil, fiber.NewError(422, errTotal.Error())
+		}
+		order.Total = total
+	}
+
+	orders, lowStock, err := uc.oRepo.Checkout(tenant.UserID, orders, cart.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	// notify the buyer and the sellers
+	owners := map[uint]models.User{}
+	for i, order := range orders {
+		obj, errGet := uc.oRepo.GetOrder(order.ID)
+		if errGet != nil {
+			continue
+		}
+		orders[i] = &obj
+		if obj.Buyer != nil {
+			uc.oRepo.SendOrderEmail(obj, *obj.Buyer)
+		}
+		if obj.Seller != nil {
+			uc.oRepo.SendOrderEmail(obj, *obj.Seller)
+			for _, item := range obj.Items {
+				if item.ProductID != nil {
+					owners[*item.ProductID] = *obj.Seller
+				}
+			}
+		}
+	}
+
+	// notify the owner of the variants the purchase brought to the low-stock threshold
+	products := map[uint]*models.Product{}
+	for _, item := range cart.Items {
+		products[item.ProductID] = item.Product
+	}
+	for _, variant := range lowStock {
+		product, okProduct := products[variant.ProductID]
+		owner, okOwner := owners[variant.ProductID]
+		if okProduct && okOwner {
+			uc.vRepo.SendLowStockEmail(owner, *product, *variant)
+		}
+	}
+
+	return orders, nil
+}
+
+// priceCart fill the current price and the problem of the items
+func priceCart(items []*models.CartItem) models.Cart {
+	cart := models.Cart{Items: items, IsValid: len(items) > 0}
+	if cart.Items == nil {
+		cart.Items = []*models.CartItem{}
+	}
+
+	now := time.Now()
+	var total *money.Money
+	sameCurrency := true
+	for _, item := range items {
+		item.Problem = cartProblem(item, now)
+		if item.Problem != "" {
+			cart.IsValid = false
+		}
+		if item.Product == nil {
+			sameCurrency = false
+			continue
+		}
+
+		price := item.Product.Price
+		if item.ProductVariant != nil {
+			price = item.ProductVariant.EffectivePrice(*item.Product)
+		}
+		subtotal, err := price.Mul(int64(item.Quantity))
+		if err != nil {
+			item.Problem = err.Error()
+			cart.IsValid = false
+			continue
+		}
+		item.UnitPrice = &price
+		item.Subtotal = &subtotal
+
+		if total == nil {
+			total = &subtotal
+			continue
+		}
+		sum, err := total.Add(subtotal)
+		if err != nil {
+			sameCurrency = false
+			continue
+		}
+		total = &sum
+	}
+
+	if sameCurrency {
+		cart.Total = total
+	}
+	return cart
+}
+
+// cartProblem return the reason the item can't be bought, empty is available
+func cartProblem(item *models.CartItem, now time.Time) string {
+	product := item.Product
+	if product == nil || !product.IsLive(now) {
+		return "The product is no longer available."
+	}
+	if item.Quantity > models.MaxCartQuantity {
+		return fmt.Sprintf("The quantity can't be more than %d.", models.MaxCartQuantity)
+	}
+
+	if item.ProductVariantID == nil {
+		if len(product.Variants) > 0 {
+			return "Please choose a variant of the product."
+		}
+		return ""
+	}
+
+	variant := item.ProductVariant
+	if variant == nil || !variant.IsEnable {
+		return "The variant is no longer available."
+	}
+	if variant.Stock < item.Quantity {
+		if variant.Stock <= 0 {
+			return "The variant is out of stock."
+		}
+		return fmt.Sprintf("Only %d item(s) left in stock.", variant.Stock)
+	}
+	return ""
+}
+
+func sameVariant(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func orgKey(organizationID *uint) uint {
+	if organizationID == nil {
+		return 0
+	}
+	return *organizationID
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"myapp/pkg/money"
+	"myapp/src/models"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// memCartRepository hold the items of the cart
+type memCartRepository struct {
+	models.CartRepository
+	items []*models.CartItem
+}
+
+func (r *memCartRepository) ListItem(userID uint) ([]*models.CartItem, *fiber.Error) {
+	return r.items, nil
+}
+
+func TestCartCheckoutTrashedProduct(t *testing.T) {
+	live := &models.Product{Title: "Shoe", Status: models.ProductPublished, Price: money.New(1999, "USD"), UserID: 2}
+	live.ID = 1
+
+	// the trashed product isn't loaded with the item
+	cart := &memCartRepository{items: []*models.CartItem{
+		{ID: 1, Quantity: 1, ProductID: live.ID, Product: live},
+		{ID: 2, Quantity: 1, ProductID: 5},
+	}}
+	// the orders must not be created
+	uc := NewCartUsecase(cart, nil, &memOrderRepository{}, nil)
+
+	app := fiber.New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	c.Locals("tenant", models.Tenant{UserID: 3})
+
+	orders, err := uc.Checkout(c, models.CheckoutInput{ShippingAddress: "1 Main Street"})
+	if err == nil || err.Code != 422 {
+		t.Fatalf("got %v, %v, want 422", orders, err)
+	}
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CategoryUsecase struct {
+	cRepo models.CategoryRepository
+}
+
+// NewCategoryUsecase will create an object that represent the models.CategoryUsecase interface
+func NewCategoryUsecase(category models.CategoryRepository) models.CategoryUsecase {
+	return &CategoryUsecase{
+		cRepo: category,
+	}
+}
+
+// ListCategory implements models.CategoryUsecase.
+func (uc *CategoryUsecase) ListCategory(c *fiber.Ctx) ([]*models.Category, *fiber.Error) {
+	tenant, _ := c.Locals("tenant").(models.Tenant)
+
+	data, err := uc.cRepo.ListCategory()
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := uc.cRepo.CountProduct(tenant.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCategoryTree(data, counts), nil
+}
+
+// GetCategory implements models.CategoryUsecase.
+func (uc *CategoryUsecase) GetCategory(c *fiber.Ctx) (models.Category, *fiber.Error) {
+	id := utils.StringToUint(c.Params("id"))
+
+	data, err := uc.ListCategory(c)
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	// find the node with its subtree
+	if obj := findCategory(data, id); obj != nil {
+		return *obj, nil
+	}
+
+	return models.Category{}, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+}
+
+// ListTag implements models.CategoryUsecase.
+func (uc *CategoryUsecase) ListTag(c *fiber.Ctx) ([]*models.Tag, *fiber.Error) {
+	limit, _ := strconv.Atoi(c.Query("per_page", "50"))
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	return uc.cRepo.ListTag(c.Query("search"), limit)
+}
+
+// Create implements models.CategoryUsecase.
+func (uc *CategoryUsecase) Create(c *fiber.Ctx, payload models.CategoryInput) (models.Category, *fiber.Error) {
+	var obj models.Category
+
+	payload.Sanitize()
+
+	if payload.ParentID != nil {
+		if _, err := uc.cRepo.Get(*payload.ParentID); err != nil {
+			return obj, fiber.NewError(422, "Parent category doesn't exists.")
+		}
+	}
+
+	obj.Name = payload.Name
+	obj.Description = payload.Description
+	obj.ParentID = payload.ParentID
+
+	return uc.cRepo.Create(obj)
+}
+
+// Update implements models.CategoryUsecase.
+func (uc *CategoryUsecase) Update(c *fiber.Ctx, payload models.CategoryInput) (models.Category, *fiber.Error) {
+	id := utils.StringToUint(c.Params("id"))
+
+	obj, err := uc.cRepo.Get(id)
+	if err != nil {
+		return obj, err
+	}
+
+	payload.Sanitize()
+
+	if payload.ParentID != nil {
+		if _, err := uc.cRepo.Get(*payload.ParentID); err != nil {
+			return obj, fiber.NewError(422, "Parent category doesn't exists.")
+		}
+
+		// the parent can't be the category itself or one of its descendants
+		ids, err := uc.cRepo.DescendantIDs(obj.ID)
+		if err != nil {
+			return obj, err
+		}
+		for _, descendantID := range ids {
+			if descendantID == *payload.ParentID {
+				return obj, fiber.NewError(422, "A category can't be moved under itself or its descendants.")
+			}
+		}
+	}
+
+	obj.Name = payload.Name
+	obj.Description = payload.Description
+	obj.ParentID = payload.ParentID
+
+	return uc.cRepo.Update(obj)
+}
+
+// Delete implements models.CategoryUsecase.
+func (uc *CategoryUsecase) Delete(c *fiber.Ctx) *fiber.Error {
+	id := utils.StringToUint(c.Params("id"))
+
+	obj, err := uc.cRepo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	// only the empty leaf can be deleted
+	ids, err := uc.cRepo.DescendantIDs(obj.ID)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 1 {
+		return fiber.NewError(422, "The category still has sub categories.")
+	}
+
+	hasProduct, err := uc.cRepo.HasProduct(obj.ID)
+	if err != nil {
+		return err
+	}
+	if hasProduct {
+		return fiber.NewError(422, "The category still has products.")
+	}
+
+	return uc.cRepo.Delete(obj)
+}
+
+// buildCategoryTree link the flat list into a tree,
+// the product count of a node include all of its descendants
+func buildCategoryTree(data []*models.Category, counts map[uint]int64) []*models.Category {
+	nodes := make(map[uint]*models.Category, len(data))
+	for _, obj := range data {
+		obj.Children = nil
+		obj.ProductCount = counts[obj.ID]
+		nodes[obj.ID] = obj
+	}
+
+	var roots []*models.Category
+	for _, obj := range data {
+		var parent *models.Category
+		if obj.ParentID != nil {
+			parent = nodes[*obj.ParentID]
+		}
+		if parent == nil {
+			roots = append(roots, obj)
+			continue
+		}
+		parent.Children = append(parent.Children, obj)
+	}
+
+	for _, obj := range roots {
+		sumProductCount(obj)
+	}
+
+	return roots
+}
+
+func sumProductCount(obj *models.Category) int64 {
+	for _, child := range obj.Children {
+		obj.ProductCount += sumProductCount(child)
+	}
+	return obj.ProductCount
+}
+
+func findCategory(data []*models.Category, id uint) *models.Category {
+	for _, obj := range data {
+		if obj.ID == id {
+			return obj
+		}
+		if found := findCategory(obj.Children, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
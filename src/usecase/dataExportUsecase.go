@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"myapp/pkg/configs"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thanhpk/randstr"
+)
+
+type DataExportUsecase struct {
+	exportRepo models.DataExportRepository
+	uRepo      models.UserRepository
+	// limit the number of archives built at the same time
+	workers chan struct{}
+}
+
+// NewDataExportUsecase will create an object that represent the models.DataExportUsecase interface
+func NewDataExportUsecase(export models.DataExportRepository, user models.UserRepository, maxWorkers int) models.DataExportUsecase {
+	if maxWorkers <= 0 {
+		maxWorkers = 2
+	}
+
+	return &DataExportUsecase{
+		exportRepo: export,
+		uRepo:      user,
+		workers:    make(chan struct{}, maxWorkers),
+	}
+}
+
+// ListExport implements models.DataExportUsecase.
+func (uc *DataExportUsecase) ListExport(c *fiber.Ctx) ([]*models.DataExport, *fiber.Error) {
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	return uc.exportRepo.ListExport(user.ID)
+}
+
+// RequestExport implements models.DataExportUsecase.
+func (uc *DataExportUsecase) RequestExport(c *fiber.Ctx) (models.DataExport, *fiber.Error) {
+	var obj models.DataExport
+
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return obj, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	// only one export at a time, a job older than a day was lost on restart
+	if active, err := uc.exportRepo.FindActive(user.ID); err == nil {
+		if time.Since(active.CreatedAt) < 24*time.Hour {
+			return obj, fiber.NewError(422, "Your previous data export is still in progress.")
+		}
+
+		active.Status = models.ExportFailed
+		active.Error = "The export was interrupted."
+		uc.exportRepo.Update(active)
+	}
+
+	// Generate download code
+	code := randstr.String(64)
+
+	obj.Code = utils.Encode(code)
+	obj.Status = models.ExportPending
+	obj.UserID = user.ID
+
+	obj, err := uc.exportRepo.Create(obj)
+	if err != nil {
+		return obj, err
+	}
+
+	// build the archive in background
+	go uc.build(obj, user, code)
+
+	return obj, nil
+}
+
+// Download implements models.DataExportUsecase.
+func (uc *DataExportUsecase) Download(ctx context.Context, code string) (models.DataExport, *fiber.Error) {
+	obj, err := uc.exportRepo.FindByCode(code)
+	if err != nil {
+		return obj, err
+	}
+
+	if obj.Status == models.ExportExpired || (obj.ExpiredAt != nil && time.Now().After(*obj.ExpiredAt)) {
+		return obj, fiber.NewError(410, "The download link has expired, please request a new export.")
+	}
+
+	if obj.Status != models.ExportReady {
+		return obj, fiber.NewError(422, "Your data export is not ready yet.")
+	}
+
+	return obj, nil
+}
+
+// PurgeExpiredExport implements models.DataExportUsecase.
+func (uc *DataExportUsecase) PurgeExpiredExport() error {
+	data, err := uc.exportRepo.ListExpired(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range data {
+		os.Remove(obj.FilePath)
+
+		obj.Status = models.ExportExpired
+		obj.FilePath = ""
+		if _, err := uc.exportRepo.Update(*obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (uc *DataExportUsecase) build(obj models.DataExport, user models.User, code string) {
+	uc.workers <- struct{}{}
+	defer func() { <-uc.workers }()
+
+	obj.Status = models.ExportProcessing
+	obj, _ = uc.exportRepo.Update(obj)
+
+	filePath := fmt.Sprintf("exports/%d/data-export-%d-%s.zip", user.ID, obj.ID, time.Now().Format("20060102150405"))
+
+	size, err := uc.createArchive(user.ID, filePath)
+	if err != nil {
+		log.Printf("Data export %d error: %s", obj.ID, err.Error())
+		os.Remove(filePath)
+
+		obj.Status = models.ExportFailed
+		obj.Error = "Unable to create the archive."
+		uc.exportRepo.Update(obj)
+		return
+	}
+
+	config, _ := configs.LoadConfig(".")
+	expiresIn := config.DataExportExpiresIn
+	if expiresIn <= 0 {
+		expiresIn = 24 * time.Hour
+	}
+
+	expiredAt := time.Now().Add(expiresIn)
+	obj.Status = models.ExportReady
+	obj.FilePath = filePath
+	obj.Size = size
+	obj.ExpiredAt = &expiredAt
+	if _, err := uc.exportRepo.Update(obj); err != nil {
+		return
+	}
+
+	// Send download link
+	uc.exportRepo.SendExportEmail(user, obj, code)
+}
+
+func (uc *DataExportUsecase) createArchive(userID uint, filePath string) (int64, error) {
+	data, errD := uc.exportRepo.CollectData(userID)
+	if errD != nil {
+		return 0, errD
+	}
+
+	archive, err := utils.NewZipArchive(filePath)
+	if err != nil {
+		return 0, err
+	}
+
+	// collect the media files before the JSON, a missing file is skipped
+	var files []string
+	if data.User.UserProfile.Photo != nil {
+		files = append(files, *data.User.UserProfile.Photo)
+	}
+	for _, product := range data.Products {
+		if product.Image != nil {
+			files = append(files, *product.Image)
+		}
+		for _, image := range product.Images {
+			files = append(files, image.Path)
+		}
+	}
+	for _, drive := range data.Drives {
+		files = append(files, drive.Link)
+	}
+
+	documents := map[string]any{
+		"user.json":         data.User,
+		"user_profile.json": data.User.UserProfile,
+		"products.json":     data.Products,
+		"drive.json":        data.Drives,
+		"activity.json":     data.Activity,
+	}
+	for name, doc := range documents {
+		if err := archive.AddJSON(name, doc); err != nil {
+			archive.Close()
+			return 0, err
+		}
+	}
+
+	// the cover is also in the gallery
+	added := map[string]bool{}
+	for _, file := range files {
+		if added[file] {
+			continue
+		}
+		added[file] = true
+
+		if err := archive.AddFile(filepath.ToSlash(file), file); err != nil {
+			log.Printf("Data export skip file %s: %s", file, err.Error())
+		}
+	}
+
+	return archive.Close()
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"myapp/pkg/money"
+	"myapp/src/models"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExchangeRateUsecase struct {
+	xRepo models.ExchangeRateRepository
+	pRepo models.ProductRepository
+	// the currency of the rate 1, see configs.BaseCurrency
+	base string
+}
+
+// NewExchangeRateUsecase will create an object that represent the models.ExchangeRateUsecase interface
+func NewExchangeRateUsecase(rate models.ExchangeRateRepository, product models.ProductRepository, base string) models.ExchangeRateUsecase {
+	return &ExchangeRateUsecase{
+		xRepo: rate,
+		pRepo: product,
+		base:  base,
+	}
+}
+
+// ListRate implements models.ExchangeRateUsecase.
+func (uc *ExchangeRateUsecase) ListRate(c *fiber.Ctx) ([]*models.ExchangeRate, *fiber.Error) {
+	data, err := uc.xRepo.ListRate()
+	if err != nil {
+		return nil, err
+	}
+
+	// the base currency is listed first
+	return append([]*models.ExchangeRate{{Currency: uc.base, Rate: "1"}}, data...), nil
+}
+
+// Set implements models.ExchangeRateUsecase.
+func (uc *ExchangeRateUsecase) Set(c *fiber.Ctx, payload models.ExchangeRateInput) (models.ExchangeRate, *fiber.Error) {
+	currency := strings.ToUpper(c.Params("currency"))
+	if !money.IsCurrency(currency) {
+		return models.ExchangeRate{}, fiber.NewError(422, "Unknown currency "+currency+".")
+	}
+	if currency == uc.base {
+		return models.ExchangeRate{}, fiber.NewError(422, "The rate of the base currency is always 1.")
+	}
+
+	rate, errRate := money.ParseRate(payload.Rate)
+	if errRate != nil {
+		return models.ExchangeRate{}, fiber.NewError(422, errRate.Error())
+	}
+
+	// the column is numeric(20,10)
+	value := rate.FloatString(10)
+	whole, _, _ := strings.Cut(value, ".")
+	if _, errRate := money.ParseRate(value); errRate != nil || len(whole) > 10 {
+		return models.ExchangeRate{}, fiber.NewError(422, "The rate must be between 0.0000000001 and 9999999999.")
+	}
+
+	// create or replace the rate of the currency
+	obj, err := uc.xRepo.Get(currency)
+	if err != nil && err.Code != fiber.StatusNotFound {
+		return obj, err
+	}
+	obj.Currency = currency
+	obj.Rate = value
+
+	obj, err = uc.xRepo.Save(obj)
+	if err != nil {
+		return obj, err
+	}
+	// the catalog show the converted prices
+	uc.pRepo.ClearCatalogCache()
+
+	return obj, nil
+}
+
+// Delete implements models.ExchangeRateUsecase.
+func (uc *ExchangeRateUsecase) Delete(c *fiber.Ctx) *fiber.Error {
+	obj, err := uc.xRepo.Get(strings.ToUpper(c.Params("currency")))
+	if err != nil {
+		return err
+	}
+
+	if err := uc.xRepo.Delete(obj); err != nil {
+		return err
+	}
+
+	uc.pRepo.ClearCatalogCache()
+	return nil
+}
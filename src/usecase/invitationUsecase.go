@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"myapp/pkg/configs"
+	"myapp/pkg/response"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thanhpk/randstr"
+)
+
+type InvitationUsecase struct {
+	invRepo models.InvitationRepository
+	uRepo   models.UserRepository
+}
+
+// NewInvitationUsecase will create an object that represent the models.InvitationUsecase interface
+func NewInvitationUsecase(inv models.InvitationRepository, user models.UserRepository) models.InvitationUsecase {
+	return &InvitationUsecase{
+		invRepo: inv,
+		uRepo:   user,
+	}
+}
+
+// CheckInvitation implements models.InvitationUsecase.
+func (uc *InvitationUsecase) CheckInvitation(c *fiber.Ctx, code string) (models.Invitation, *fiber.Error) {
+	obj, err := uc.invRepo.FindByCode(code)
+	if err != nil {
+		return obj, err
+	}
+
+	if !obj.Usable() {
+		return obj, fiber.NewError(422, "Invitation is no longer valid.")
+	}
+
+	return obj, nil
+}
+
+// Revoke implements models.InvitationUsecase.
+func (uc *InvitationUsecase) Revoke(c *fiber.Ctx, asAdmin bool) *fiber.Error {
+	id := utils.StringToUint(c.Params("id"))
+
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	// get data
+	obj, err := uc.invRepo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	// check the owner of data
+	if !asAdmin && obj.InvitedByID != user.ID {
+		return fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	if obj.CurrentStatus() != models.InvitationPending {
+		return fiber.NewError(422, "Only pending invitation can be revoked.")
+	}
+
+	obj.Status = models.InvitationRevoked
+	if _, err := uc.invRepo.Update(obj); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Create implements models.InvitationUsecase.
+func (uc *InvitationUsecase) Create(c *fiber.Ctx, payload models.InvitationInput, asAdmin bool) (models.Invitation, *fiber.Error) {
+	var obj models.Invitation
+
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return obj, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	if !asAdmin {
+		config, _ := configs.LoadConfig(".")
+		if !config.InvitationByUser {
+			return obj, fiber.NewError(403, "Invitation by user is disabled.")
+		}
+
+		// user only can invite a regular user
+		if payload.Role != "" && payload.Role != models.InvitationRoleUser {
+			return obj, fiber.NewError(403, "You are not allowed to invite with role "+payload.Role)
+		}
+	}
+
+	// the invited email must not be registered
+	if err := uc.uRepo.EmailExists(payload.Email); err != nil {
+		return obj, err
+	}
+
+	// fill default values
+	if payload.Role == "" {
+		payload.Role = models.InvitationRoleUser
+	}
+	if payload.ExpiresIn == 0 {
+		payload.ExpiresIn = 72
+	}
+	if payload.MaxUses == 0 {
+		payload.MaxUses = 1
+	}
+
+	// Generate invitation code
+	code := randstr.String(64)
+
+	obj.Email = payload.Email
+	obj.Code = utils.Encode(code)
+	obj.Role = payload.Role
+	obj.Status = models.InvitationPending
+	obj.MaxUses = payload.MaxUses
+	obj.ExpiredAt = time.Now().Add(time.Duration(payload.ExpiresIn) * time.Hour)
+	obj.InvitedByID = user.ID
+
+	// save the data
+	obj, err := uc.invRepo.Create(obj)
+	if err != nil {
+		return obj, err
+	}
+
+	// Send invitation email
+	uc.invRepo.SendInvitationEmail(obj, code)
+
+	return obj, nil
+}
+
+// ListInvitation implements models.InvitationUsecase.
+func (uc *InvitationUsecase) ListInvitation(c *fiber.Ctx, all bool) (*response.Pagination, *fiber.Error) {
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	// 	Parse the query parameters
+	search := c.Query("search")
+	page := c.Query("page", "1")
+	limit := c.Query("per_page", "10")
+
+	// Convert the page and limit to integers
+	pageInt, _ := strconv.Atoi(page)
+	limitInt, _ := strconv.Atoi(limit)
+	pageInt, limitInt = response.ClampPage(pageInt, limitInt)
+
+	query, errQuery := models.InvitationQuery.Parse(c)
+	if errQuery != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, errQuery.Error())
+	}
+
+	// make param pagination struct
+	pagParam := response.ParamsPagination{
+		Page:      pageInt,
+		Limit:     limitInt,
+		SortQuery: query.OrderBy(),
+		Filter:    query.Scope,
+		Search:    search,
+		NoPage:    c.Query("no_page"),
+	}
+
+	invitedByID := user.ID
+	if all {
+		invitedByID = 0
+	}
+
+	pagination, err := uc.invRepo.ListInvitation(invitedByID, pagParam)
+	if err != nil {
+		return nil, err
+	}
+	return pagination, nil
+}
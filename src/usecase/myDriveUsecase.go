@@ -27,19 +27,19 @@ func NewMyDriveUsecase(md models.MyDriveRepository, user models.UserRepository)
 func (uc *MyDriveUsecase) Delete(c *fiber.Ctx) *fiber.Error {
 	id := c.Params("id")
 
-	user, errLocal := c.Locals("user").(models.User)
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
 	if !errLocal {
 		return fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
 	}
 
 	// get data
-	obj, err := uc.dRepo.Get(id)
+	obj, err := uc.dRepo.Get(tenant, id)
 	if err != nil {
 		return err
 	}
 
 	// check the owner of data
-	if obj.UserID != user.ID {
+	if !tenant.CanManage(obj.UserID) {
 		return fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
 	}
 
@@ -60,19 +60,19 @@ func (uc *MyDriveUsecase) Update(c *fiber.Ctx, payload models.MyDriveRenameInput
 	id := c.Params("id")
 	obj := models.MyDrive{}
 
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return obj, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
 	// get data
-	obj, err := uc.dRepo.Get(id)
+	obj, err := uc.dRepo.Get(tenant, id)
 	if err != nil {
 		return obj, err
 	}
 
-	user, errLocal := c.Locals("user").(models.User)
-	if !errLocal {
-		return obj, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
-	}
-
 	// check the owner of data
-	if obj.UserID != user.ID {
+	if !tenant.CanManage(obj.UserID) {
 		return obj, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
 	}
 
@@ -92,7 +92,12 @@ func (uc *MyDriveUsecase) Update(c *fiber.Ctx, payload models.MyDriveRenameInput
 func (uc *MyDriveUsecase) Get(c *fiber.Ctx) (models.MyDrive, *fiber.Error) {
 	id := c.Params("id")
 
-	obj, err := uc.dRepo.Get(id)
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.MyDrive{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.dRepo.Get(tenant, id)
 	if err != nil {
 		return obj, err
 	}
@@ -104,7 +109,7 @@ func (uc *MyDriveUsecase) Get(c *fiber.Ctx) (models.MyDrive, *fiber.Error) {
 func (uc *MyDriveUsecase) Create(c *fiber.Ctx) ([]*models.MyDrive, *fiber.Error) {
 	var listObj []*models.MyDrive
 
-	user, errLocal := c.Locals("user").(models.User)
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
 	if !errLocal {
 		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
 	}
@@ -122,7 +127,8 @@ func (uc *MyDriveUsecase) Create(c *fiber.Ctx) ([]*models.MyDrive, *fiber.Error)
 			// declare new model
 			var obj models.MyDrive
 			// fill the owner
-			obj.UserID = user.ID
+			obj.UserID = tenant.UserID
+			obj.OrganizationID = tenant.OrganizationPtr()
 
 			// get mime type
 			_fileType := string(models.FileFile)
@@ -160,38 +166,40 @@ func (uc *MyDriveUsecase) Create(c *fiber.Ctx) ([]*models.MyDrive, *fiber.Error)
 
 // MyDrive implements models.MyDriveUsecase.
 func (uc *MyDriveUsecase) MyDrive(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
-	user, errLocal := c.Locals("user").(models.User)
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
 	if !errLocal {
 		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
 	}
 
 	// 	Parse the query parameters
 	search := c.Query("search")
-	sortBy := c.Query("sort", "created_at|desc")
 	page := c.Query("page", "1")
 	limit := c.Query("per_page", "10")
 
 	// Convert the page and limit to integers
 	pageInt, _ := strconv.Atoi(page)
 	limitInt, _ := strconv.Atoi(limit)
+	pageInt, limitInt = response.ClampPage(pageInt, limitInt)
 
-	sortQuery, errSort := utils.ValidateAndReturnSortQuery(sortBy)
-	// log.Print(sortQuery)
-	if errSort != nil {
-		errD := fiber.NewError(fiber.StatusInternalServerError, errSort.Error())
-		return nil, errD
+	query, errQuery := models.MyDriveQuery.Parse(c)
+	if errQuery != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, errQuery.Error())
 	}
 
 	// make param pagination struct
 	pagParam := response.ParamsPagination{
-		Page:      pageInt,
-		Limit:     limitInt,
-		SortQuery: sortQuery,
-		Search:    search,
-		NoPage:    c.Query("no_page"),
-	}
-
-	pagination, err := uc.dRepo.MyDrive(user, pagParam)
+		Page:          pageInt,
+		Limit:         limitInt,
+		SortQuery:     query.OrderBy(),
+		Filter:        query.Scope,
+		Cursor:        c.Query("cursor"),
+		CursorMode:    c.Context().QueryArgs().Has("cursor"),
+		EstimateCount: c.Query("count") == "estimate",
+		Search:        search,
+		NoPage:        c.Query("no_page"),
+	}
+
+	pagination, err := uc.dRepo.MyDrive(tenant, pagParam)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"myapp/pkg/response"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type OrderUsecase struct {
+	oRepo models.OrderRepository
+}
+
+// NewOrderUsecase will create an object that represent the models.OrderUsecase interface
+func NewOrderUsecase(order models.OrderRepository) models.OrderUsecase {
+	return &OrderUsecase{
+		oRepo: order,
+	}
+}
+
+// MyOrder implements models.OrderUsecase.
+func (uc *OrderUsecase) MyOrder(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	// the purchases of the user in every workspace
+	scope := func(db *gorm.DB) *gorm.DB {
+		return db.Where("buyer_id = ?", tenant.UserID)
+	}
+
+	return uc.listOrder(c, scope)
+}
+
+// MySale implements models.OrderUsecase.
+func (uc *OrderUsecase) MySale(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	// the manager see all sales of the organization, the member only its own
+	scope := func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(utils.OrganizationThis(tenant.OrganizationID))
+		if !tenant.IsManager() {
+			db = db.Where("seller_id = ?", tenant.UserID)
+		}
+		return db
+	}
+
+	return uc.listOrder(c, scope)
+}
+
+// GetOrder implements models.OrderUsecase.
+func (uc *OrderUsecase) GetOrder(c *fiber.Ctx) (models.Order, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Order{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.oRepo.GetOrder(utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return obj, err
+	}
+
+	if !obj.IsBuyer(tenant.UserID) && !isSeller(tenant, obj) {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+
+	return obj, nil
+}
+
+// Cancel implements models.OrderUsecase.
+func (uc *OrderUsecase) Cancel(c *fiber.Ctx) (models.Order, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Order{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.oRepo.GetOrder(utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return obj, err
+	}
+
+	// only the buyer cancel its own order
+	if !obj.IsBuyer(tenant.UserID) {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	if obj.Status != models.OrderPending {
+		return obj, fiber.NewError(422, "Only the pending order can be cancelled.")
+	}
+
+	return uc.transition(obj, models.OrderCancelled, "", tenant.UserID)
+}
+
+// ChangeStatus implements models.OrderUsecase.
+func (uc *OrderUsecase) ChangeStatus(c *fiber.Ctx, payload models.OrderStatusInput) (models.Order, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Order{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.oRepo.GetOrder(utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return obj, err
+	}
+
+	// the seller move the order forward
+	if !isSeller(tenant, obj) {
+		if obj.IsBuyer(tenant.UserID) {
+			return obj, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+		}
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+
+	// paid and refunded go through the payment gateway, see PaymentUsecase
+	switch payload.Status {
+	case models.OrderShipped, models.OrderCompleted, models.OrderCancelled:
+	default:
+		return obj, fiber.NewError(422, "The order can only be shipped, completed or cancelled, the payment set it paid or refunded.")
+	}
+
+	return uc.transition(obj, payload.Status, strings.TrimSpace(payload.TrackingNumber), tenant.UserID)
+}
+
+// transition move the order to the next status and notify the buyer and the seller
+func (uc *OrderUsecase) transition(obj models.Order, next models.OrderStatus, trackingNumber string, userID uint) (models.Order, *fiber.Error) {
+	from := obj.Status
+	if !from.CanTransition(next) {
+		return obj, fiber.NewError(422, "The order can't be changed from "+string(from)+" to "+string(next)+".")
+	}
+
+	now := time.Now()
+	obj.Status = next
+	switch next {
+	case models.OrderShipped:
+		obj.ShippedAt = &now
+		if trackingNumber != "" {
+			obj.TrackingNumber = trackingNumber
+		}
+	case models.OrderCompleted:
+		obj.CompletedAt = &now
+	case models.OrderCancelled:
+		obj.CancelledAt = &now
+	}
+
+	obj, err := uc.oRepo.Transition(obj, from, userID)
+	if err != nil {
+		return obj, err
+	}
+
+	if obj.Buyer != nil {
+		uc.oRepo.SendOrderEmail(obj, *obj.Buyer)
+	}
+	if obj.Seller != nil {
+		uc.oRepo.SendOrderEmail(obj, *obj.Seller)
+	}
+
+	return obj, nil
+}
+
+func (uc *OrderUsecase) listOrder(c *fiber.Ctx, scope func(db *gorm.DB) *gorm.DB) (*response.Pagination, *fiber.Error) {
+	// 	Parse the query parameters
+	page := c.Query("page", "1")
+	limit := c.Query("per_page", "10")
+
+	// Convert the page and limit to integers
+	pageInt, _ := strconv.Atoi(page)
+	limitInt, _ := strconv.Atoi(limit)
+	pageInt, limitInt = response.ClampPage(pageInt, limitInt)
+
+	query, errQuery := models.OrderQuery.Parse(c)
+	if errQuery != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, errQuery.Error())
+	}
+
+	// make param pagination struct
+	pagParam := response.ParamsPagination{
+		Page:          pageInt,
+		Limit:         limitInt,
+		SortQuery:     query.OrderBy(),
+		Filter:        query.Scope,
+		Cursor:        c.Query("cursor"),
+		CursorMode:    c.Context().QueryArgs().Has("cursor"),
+		EstimateCount: c.Query("count") == "estimate",
+		NoPage:        c.Query("no_page"),
+	}
+
+	return uc.oRepo.ListOrder(scope, pagParam)
+}
+
+// isSeller check the order is a sale of the tenant
+func isSeller(tenant models.Tenant, obj models.Order) bool {
+	if orgKey(obj.OrganizationID) != tenant.OrganizationID {
+		return false
+	}
+	// the sale of a purged seller is left to the managers of the organization
+	if obj.SellerID == nil {
+		return tenant.IsManager()
+	}
+	return tenant.CanManage(*obj.SellerID)
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"myapp/src/models"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// memOrderRepository hold one order, the transition is saved when the status is unchanged
+type memOrderRepository struct {
+	models.OrderRepository
+	order models.Order
+}
+
+func (r *memOrderRepository) GetOrder(id uint) (models.Order, *fiber.Error) {
+	return r.order, nil
+}
+
+func (r *memOrderRepository) Transition(obj models.Order, from models.OrderStatus, userID uint) (models.Order, *fiber.Error) {
+	if r.order.Status != from {
+		return obj, fiber.NewError(409, "The order status has been changed, please reload the order.")
+	}
+	r.order = obj
+	return obj, nil
+}
+
+func (*memOrderRepository) SendOrderEmail(obj models.Order, to models.User) error {
+	return nil
+}
+
+// changeStatus call ChangeStatus in the route of the order as the tenant
+func changeStatus(t *testing.T, uc models.OrderUsecase, tenant models.Tenant, payload models.OrderStatusInput) (models.Order, *fiber.Error) {
+	var obj models.Order
+	var err *fiber.Error
+
+	app := fiber.New()
+	app.Patch("/orders/:id", func(c *fiber.Ctx) error {
+		c.Locals("tenant", tenant)
+		obj, err = uc.ChangeStatus(c, payload)
+		return nil
+	})
+	if _, errTest := app.Test(httptest.NewRequest(fiber.MethodPatch, "/orders/1", nil)); errTest != nil {
+		t.Fatal(errTest)
+	}
+
+	return obj, err
+}
+
+func TestOrderChangeStatus(t *testing.T) {
+	sellerID, buyerID := uint(1), uint(2)
+
+	tests := []struct {
+		from, next models.OrderStatus
+		code       int
+	}{
+		{models.OrderPaid, models.OrderShipped, 0},
+		{models.OrderShipped, models.OrderCompleted, 0},
+		{models.OrderPending, models.OrderCancelled, 0},
+		// only the payment set paid and refunded
+		{models.OrderPending, models.OrderPaid, 422},
+		{models.OrderPaid, models.OrderRefunded, 422},
+		{models.OrderCompleted, models.OrderRefunded, 422},
+		// outside of the state machine
+		{models.OrderPending, models.OrderShipped, 422},
+		{models.OrderCancelled, models.OrderShipped, 422},
+	}
+
+	for _, tt := range tests {
+		repo := &memOrderRepository{order: models.Order{Status: tt.from, BuyerID: &buyerID, SellerID: &sellerID}}
+		uc := NewOrderUsecase(repo)
+
+		obj, err := changeStatus(t, uc, models.Tenant{UserID: sellerID}, models.OrderStatusInput{Status: tt.next, TrackingNumber: " TRACK-1 "})
+
+		if tt.code != 0 {
+			if err == nil || err.Code != tt.code {
+				t.Errorf("%s to %s: got %v, want %d", tt.from, tt.next, err, tt.code)
+			}
+			if repo.order.Status != tt.from {
+				t.Errorf("%s to %s: the order has been changed to %s", tt.from, tt.next, repo.order.Status)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s to %s: %v", tt.from, tt.next, err)
+			continue
+		}
+		if obj.Status != tt.next || repo.order.Status != tt.next {
+			t.Errorf("%s to %s: got %s", tt.from, tt.next, repo.order.Status)
+		}
+		if tt.next == models.OrderShipped && (obj.ShippedAt == nil || obj.TrackingNumber != "TRACK-1") {
+			t.Errorf("the shipped order should have the time and the tracking number, got %v %q", obj.ShippedAt, obj.TrackingNumber)
+		}
+	}
+}
+
+func TestOrderChangeStatusByBuyer(t *testing.T) {
+	sellerID, buyerID := uint(1), uint(2)
+	repo := &memOrderRepository{order: models.Order{Status: models.OrderPaid, BuyerID: &buyerID, SellerID: &sellerID}}
+	uc := NewOrderUsecase(repo)
+
+	for userID, code := range map[uint]int{buyerID: 403, 3: 404} {
+		_, err := changeStatus(t, uc, models.Tenant{UserID: userID}, models.OrderStatusInput{Status: models.OrderShipped})
+		if err == nil || err.Code != code {
+			t.Errorf("user %d: got %v, want %d", userID, err, code)
+		}
+	}
+
+	// the sale of a purged seller is managed by the organization
+	orgID := uint(9)
+	repo.order.SellerID = nil
+	repo.order.OrganizationID = &orgID
+	admin := models.Tenant{UserID: 4, OrganizationID: orgID, Role: models.OrgRoleAdmin}
+	if _, err := changeStatus(t, uc, admin, models.OrderStatusInput{Status: models.OrderShipped}); err != nil {
+		t.Errorf("the admin of the organization: %v", err)
+	}
+}
@@ -0,0 +1,427 @@
+package usecase
+
+import (
+	"myapp/pkg/queryspec"
+	"myapp/pkg/response"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thanhpk/randstr"
+)
+
+type OrganizationUsecase struct {
+	orgRepo models.OrganizationRepository
+	uRepo   models.UserRepository
+}
+
+// NewOrganizationUsecase will create an object that represent the models.OrganizationUsecase interface
+func NewOrganizationUsecase(org models.OrganizationRepository, user models.UserRepository) models.OrganizationUsecase {
+	return &OrganizationUsecase{
+		orgRepo: org,
+		uRepo:   user,
+	}
+}
+
+// MyOrganization implements models.OrganizationUsecase.
+func (uc *OrganizationUsecase) MyOrganization(c *fiber.Ctx) ([]*models.OrganizationMember, *fiber.Error) {
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	return uc.orgRepo.MyOrganization(user.ID)
+}
+
+// Get implements models.OrganizationUsecase.
+func (uc *OrganizationUsecase) Get(c *fiber.Ctx) (models.Organization, *fiber.Error) {
+	member, err := uc.currentMember(c, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return models.Organization{}, err
+	}
+
+	return uc.orgRepo.Get(member.OrganizationID)
+}
+
+// Create implements models.OrganizationUsecase.
+func (uc *OrganizationUsecase) Create(c *fiber.Ctx, payload models.OrganizationInput) (models.Organization, *fiber.Error) {
+	obj := models.Organization{}
+
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return obj, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	// the creator is the owner of organization
+	obj.Name = strings.TrimSpace(payload.Name)
+	obj.Members = []*models.OrganizationMember{
+		{Role: models.OrgRoleOwner, UserID: user.ID},
+	}
+
+	return uc.orgRepo.Create(obj)
+}
+
+// Update implements models.OrganizationUsecase.
+func (uc *OrganizationUsecase) Update(c *fiber.Ctx, payload models.OrganizationInput) (models.Organization, *fiber.Error) {
+	obj := models.Organization{}
+
+	member, err := uc.currentMember(c, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return obj, err
+	}
+
+	if !member.CanManage() {
+		return obj, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	obj, err = uc.orgRepo.Get(member.OrganizationID)
+	if err != nil {
+		return obj, err
+	}
+
+	obj.Name = strings.TrimSpace(payload.Name)
+
+	return uc.orgRepo.Update(obj)
+}
+
+// Delete implements models.OrganizationUsecase.
+func (uc *OrganizationUsecase) Delete(c *fiber.Ctx) *fiber.Error {
+	member, err := uc.currentMember(c, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return err
+	}
+
+	// only the owner can delete the organization
+	if member.Role != models.OrgRoleOwner {
+		return fiber.NewError(403, "Only the owner can delete the organization.")
+	}
+
+	obj, err := uc.orgRepo.Get(member.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	return uc.orgRepo.Delete(obj)
+}
+
+// Switch implements models.OrganizationUsecase.
+func (uc *OrganizationUsecase) Switch(c *fiber.Ctx, orgID uint) (models.Token, *fiber.Error) {
+	var token models.Token
+
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return token, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	// 0 is the personal workspace
+	if orgID != 0 {
+		if _, err := uc.currentMember(c, orgID); err != nil {
+			return token, err
+		}
+	}
+
+	token, errT := uc.uRepo.GeneratePairToken(user.ID, orgID)
+	if errT != nil {
+		return token, fiber.NewError(500, errT.Error())
+	}
+
+	// revoke the current session, the new token replace it
+	tokenUuid, _ := c.Locals("token_uuid").(string)
+	uc.uRepo.DeleteToken(&models.AccessDetails{TokenUuid: tokenUuid, UserID: user.ID})
+
+	return token, nil
+}
+
+// ListMember implements models.OrganizationUsecase.
+func (uc *OrganizationUsecase) ListMember(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
+	member, err := uc.currentMember(c, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return nil, err
+	}
+
+	pagParam, err := uc.paginationParam(c, models.OrganizationMemberQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.orgRepo.ListMember(member.OrganizationID, pagParam)
+}
+
+// UpdateMember implements models.OrganizationUsecase.
+func (uc *OrganizationUsecase) UpdateMember(c *fiber.Ctx, payload models.OrganizationMemberInput) (models.OrganizationMember, *fiber.Error) {
+	var obj models.OrganizationMember
+
+	member, err := uc.currentMember(c, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return obj, err
+	}
+
+	if !member.CanManage() {
+		return obj, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	obj, err = uc.orgRepo.FindMember(member.OrganizationID, utils.StringToUint(c.Params("user_id")))
+	if err != nil {
+		return obj, err
+	}
+
+	role := models.OrganizationRole(payload.Role)
+
+	// only the owner can grant or revoke the owner role
+	if (role == models.OrgRoleOwner || obj.Role == models.OrgRoleOwner) && member.Role != models.OrgRoleOwner {
+		return obj, fiber.NewError(403, "Only the owner can change the owner role.")
+	}
+
+	// the organization must have at least one owner
+	if obj.Role == models.OrgRoleOwner && role != models.OrgRoleOwner {
+		if err := uc.ensureAnotherOwner(obj.OrganizationID); err != nil {
+			return obj, err
+		}
+	}
+
+	obj.Role = role
+
+	return uc.orgRepo.UpdateMember(obj)
+}
+
+// RemoveMember implements models.OrganizationUsecase.
+func (uc *OrganizationUsecase) RemoveMember(c *fiber.Ctx) *fiber.Error {
+	member, err := uc.currentMember(c, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return err
+	}
+
+	obj, err := uc.orgRepo.FindMember(member.OrganizationID, utils.StringToUint(c.Params("user_id")))
+	if err != nil {
+		return err
+	}
+
+	// every member can leave the organization
+	if obj.UserID != member.UserID {
+		if !member.CanManage() {
+			return fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+		}
+
+		if obj.Role == models.OrgRoleOwner && member.Role != models.OrgRoleOwner {
+			return fiber.NewError(403, "Only the owner can remove another owner.")
+		}
+	}
+
+	if obj.Role == models.OrgRoleOwner {
+		if err := uc.ensureAnotherOwner(obj.OrganizationID); err != nil {
+			return err
+		}
+	}
+
+	return uc.orgRepo.DeleteMember(obj)
+}
+
+// ListInvitation implements models.OrganizationUsecase.
+func (uc *OrganizationUsecase) ListInvitation(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
+	member, err := uc.currentMember(c, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return nil, err
+	}
+
+	if !member.CanManage() {
+		return nil, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	pagParam, err := uc.paginationParam(c, models.OrganizationInvitationQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.orgRepo.ListInvitation(member.OrganizationID, pagParam)
+}
+
+// Invite implements models.OrganizationUsecase.
+func (uc *OrganizationUsecase) Invite(c *fiber.Ctx, payload models.OrganizationInviteInput) (models.OrganizationInvitation, *fiber.Error) {
+	var obj models.OrganizationInvitation
+
+	member, err := uc.currentMember(c, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return obj, err
+	}
+
+	if !member.CanManage() {
+		return obj, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	org, err := uc.orgRepo.Get(member.OrganizationID)
+	if err != nil {
+		return obj, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(payload.Email))
+
+	// the email is already a member of organization
+	if user, err := uc.uRepo.FindUserByEmail(email); err == nil {
+		if _, err := uc.orgRepo.FindMember(org.ID, user.ID); err == nil {
+			return obj, fiber.NewError(422, "The user is already a member of this organization.")
+		}
+	}
+
+	role := models.OrgRoleMember
+	if payload.Role != "" {
+		role = models.OrganizationRole(payload.Role)
+	}
+
+	expiresIn := payload.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn = 72
+	}
+
+	// Generate invitation code
+	code := randstr.String(32)
+
+	obj.Email = email
+	obj.Code = utils.Encode(code)
+	obj.Role = role
+	obj.Status = models.InvitationPending
+	obj.ExpiredAt = time.Now().Add(time.Duration(expiresIn) * time.Hour)
+	obj.OrganizationID = org.ID
+	obj.InvitedByID = member.UserID
+
+	obj, err = uc.orgRepo.CreateInvitation(obj)
+	if err != nil {
+		return obj, err
+	}
+
+	uc.orgRepo.SendInvitationEmail(obj, org, code)
+
+	return obj, nil
+}
+
+// RevokeInvitation implements models.OrganizationUsecase.
+func (uc *OrganizationUsecase) RevokeInvitation(c *fiber.Ctx) *fiber.Error {
+	member, err := uc.currentMember(c, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return err
+	}
+
+	if !member.CanManage() {
+		return fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	obj, err := uc.orgRepo.GetInvitation(utils.StringToUint(c.Params("invitation_id")))
+	if err != nil {
+		return err
+	}
+
+	if obj.OrganizationID != member.OrganizationID {
+		return fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+
+	if obj.CurrentStatus() != models.InvitationPending {
+		return fiber.NewError(422, "Only a pending invitation can be revoked.")
+	}
+
+	obj.Status = models.InvitationRevoked
+	_, err = uc.orgRepo.UpdateInvitation(obj)
+
+	return err
+}
+
+// AcceptInvitation implements models.OrganizationUsecase.
+func (uc *OrganizationUsecase) AcceptInvitation(c *fiber.Ctx, code string) (models.OrganizationMember, *fiber.Error) {
+	var obj models.OrganizationMember
+
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return obj, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	invitation, err := uc.orgRepo.FindInvitationByCode(code)
+	if err != nil {
+		return obj, err
+	}
+
+	// the invitation is only for the invited email
+	if !strings.EqualFold(invitation.Email, user.Email) {
+		return obj, fiber.NewError(403, "This invitation was sent to another email.")
+	}
+
+	if invitation.CurrentStatus() != models.InvitationPending {
+		return obj, fiber.NewError(422, "The invitation is "+string(invitation.CurrentStatus())+".")
+	}
+
+	obj.Role = invitation.Role
+	obj.OrganizationID = invitation.OrganizationID
+	obj.UserID = user.ID
+
+	obj, err = uc.orgRepo.CreateMember(obj)
+	if err != nil {
+		return obj, err
+	}
+
+	invitation.Status = models.InvitationAccepted
+	if _, err := uc.orgRepo.UpdateInvitation(invitation); err != nil {
+		return obj, err
+	}
+
+	obj.Organization = invitation.Organization
+
+	return obj, nil
+}
+
+// currentMember return the membership of the current user in the organization
+func (uc *OrganizationUsecase) currentMember(c *fiber.Ctx, orgID uint) (models.OrganizationMember, *fiber.Error) {
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return models.OrganizationMember{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	member, err := uc.orgRepo.FindMember(orgID, user.ID)
+	if err != nil {
+		// hide the organization from the non member
+		return member, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+
+	return member, nil
+}
+
+// ensureAnotherOwner prevent the organization to lose its last owner
+func (uc *OrganizationUsecase) ensureAnotherOwner(orgID uint) *fiber.Error {
+	count, err := uc.orgRepo.CountOwner(orgID)
+	if err != nil {
+		return err
+	}
+
+	if count <= 1 {
+		return fiber.NewError(422, "The organization must have at least one owner.")
+	}
+
+	return nil
+}
+
+// paginationParam parse the pagination, filter and sort query against the whitelist of spec
+func (uc *OrganizationUsecase) paginationParam(c *fiber.Ctx, spec queryspec.Spec) (response.ParamsPagination, *fiber.Error) {
+	// 	Parse the query parameters
+	search := c.Query("search")
+	page := c.Query("page", "1")
+	limit := c.Query("per_page", "10")
+
+	// Convert the page and limit to integers
+	pageInt, _ := strconv.Atoi(page)
+	limitInt, _ := strconv.Atoi(limit)
+	pageInt, limitInt = response.ClampPage(pageInt, limitInt)
+
+	query, errQuery := spec.Parse(c)
+	if errQuery != nil {
+		return response.ParamsPagination{}, fiber.NewError(fiber.StatusBadRequest, errQuery.Error())
+	}
+
+	// make param pagination struct
+	return response.ParamsPagination{
+		Page:      pageInt,
+		Limit:     limitInt,
+		SortQuery: query.OrderBy(),
+		Filter:    query.Scope,
+		Search:    search,
+		NoPage:    c.Query("no_page"),
+	}, nil
+}
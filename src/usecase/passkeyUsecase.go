@@ -0,0 +1,304 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type PasskeyUsecase struct {
+	pkRepo   models.PasskeyRepository
+	uRepo    models.UserRepository
+	webAuthn *webauthn.WebAuthn
+}
+
+// NewPasskeyUsecase will create an object that represent the models.PasskeyUsecase interface
+func NewPasskeyUsecase(pk models.PasskeyRepository, user models.UserRepository, wa *webauthn.WebAuthn) models.PasskeyUsecase {
+	return &PasskeyUsecase{
+		pkRepo:   pk,
+		uRepo:    user,
+		webAuthn: wa,
+	}
+}
+
+func registrationSessionKey(userID uint) string {
+	return fmt.Sprintf("PasskeyRegister++%d", userID)
+}
+
+func loginSessionKey(sessionID string) string {
+	return fmt.Sprintf("PasskeyLogin++%s", sessionID)
+}
+
+// passkeyUser load the user with all of their passkeys
+func (uc *PasskeyUsecase) passkeyUser(user models.User) (models.PasskeyUser, *fiber.Error) {
+	passkeys, err := uc.pkRepo.ListPasskey(user.ID)
+	if err != nil {
+		return models.PasskeyUser{}, err
+	}
+	return models.PasskeyUser{User: user, Passkeys: passkeys}, nil
+}
+
+// BeginRegistration implements models.PasskeyUsecase.
+func (uc *PasskeyUsecase) BeginRegistration(c *fiber.Ctx) (*protocol.CredentialCreation, *fiber.Error) {
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	pkUser, err := uc.passkeyUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	// exclude the registered passkeys, so the same authenticator is not registered twice
+	var exclusions []protocol.CredentialDescriptor
+	for _, cred := range pkUser.WebAuthnCredentials() {
+		exclusions = append(exclusions, cred.Descriptor())
+	}
+
+	options, session, errWa := uc.webAuthn.BeginRegistration(pkUser, webauthn.WithExclusions(exclusions))
+	if errWa != nil {
+		return nil, fiber.NewError(500, errWa.Error())
+	}
+
+	// store challenge in redis
+	if errSave := uc.pkRepo.SaveSession(registrationSessionKey(user.ID), *session); errSave != nil {
+		return nil, fiber.NewError(500, errSave.Error())
+	}
+
+	return options, nil
+}
+
+// FinishRegistration implements models.PasskeyUsecase.
+func (uc *PasskeyUsecase) FinishRegistration(c *fiber.Ctx, name string) (models.Passkey, *fiber.Error) {
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return models.Passkey{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	session, err := uc.pkRepo.PopSession(registrationSessionKey(user.ID))
+	if err != nil {
+		return models.Passkey{}, err
+	}
+
+	parsed, errParse := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(c.Body()))
+	if errParse != nil {
+		return models.Passkey{}, fiber.NewError(400, errParse.Error())
+	}
+
+	pkUser, err := uc.passkeyUser(user)
+	if err != nil {
+		return models.Passkey{}, err
+	}
+
+	credential, errWa := uc.webAuthn.CreateCredential(pkUser, session, parsed)
+	if errWa != nil {
+		return models.Passkey{}, fiber.NewError(400, errWa.Error())
+	}
+
+	if name == "" {
+		name = fmt.Sprintf("Passkey %d", len(pkUser.Passkeys)+1)
+	}
+
+	// save the credential
+	obj, err := uc.pkRepo.Create(models.NewPasskey(user.ID, name, credential))
+	if err != nil {
+		return obj, err
+	}
+
+	return obj, nil
+}
+
+// BeginLogin implements models.PasskeyUsecase.
+func (uc *PasskeyUsecase) BeginLogin(ctx context.Context, payload models.PasskeyLoginInput) (models.PasskeyLoginOptions, *fiber.Error) {
+	res := models.PasskeyLoginOptions{SessionID: uuid.NewString()}
+
+	var options *protocol.CredentialAssertion
+	var session *webauthn.SessionData
+	var errWa error
+
+	if payload.Email == "" {
+		// discoverable login, the authenticator choose the account
+		options, session, errWa = uc.webAuthn.BeginDiscoverableLogin()
+	} else {
+		user, err := uc.uRepo.FindUserByIdentity(payload.Email)
+		if err != nil {
+			return res, err
+		}
+
+		pkUser, err := uc.passkeyUser(user)
+		if err != nil {
+			return res, err
+		}
+
+		if len(pkUser.Passkeys) == 0 {
+			return res, fiber.NewError(400, "This account doesn't have any passkey.")
+		}
+
+		options, session, errWa = uc.webAuthn.BeginLogin(pkUser)
+	}
+	if errWa != nil {
+		return res, fiber.NewError(500, errWa.Error())
+	}
+
+	// store challenge in redis
+	if errSave := uc.pkRepo.SaveSession(loginSessionKey(res.SessionID), *session); errSave != nil {
+		return res, fiber.NewError(500, errSave.Error())
+	}
+
+	res.Options = options
+	return res, nil
+}
+
+// FinishLogin implements models.PasskeyUsecase.
+func (uc *PasskeyUsecase) FinishLogin(ctx context.Context, sessionID string, body []byte) (models.Token, *fiber.Error) {
+	session, err := uc.pkRepo.PopSession(loginSessionKey(sessionID))
+	if err != nil {
+		return models.Token{}, err
+	}
+
+	parsed, errParse := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(body))
+	if errParse != nil {
+		return models.Token{}, fiber.NewError(400, errParse.Error())
+	}
+
+	// find the passkey used by the authenticator
+	passkey, err := uc.pkRepo.FindByCredentialID(parsed.RawID)
+	if err != nil {
+		return models.Token{}, fiber.NewError(401, "Invalid passkey.")
+	}
+
+	pkUser, err := uc.passkeyUser(passkey.User)
+	if err != nil {
+		return models.Token{}, err
+	}
+
+	var credential *webauthn.Credential
+	var errWa error
+	if session.UserID == nil {
+		credential, errWa = uc.webAuthn.ValidateDiscoverableLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+			if !bytes.Equal(userHandle, pkUser.WebAuthnID()) {
+				return nil, fmt.Errorf("user handle doesn't match the passkey owner")
+			}
+			return pkUser, nil
+		}, session, parsed)
+	} else {
+		credential, errWa = uc.webAuthn.ValidateLogin(pkUser, session, parsed)
+	}
+	if errWa != nil {
+		return models.Token{}, fiber.NewError(401, errWa.Error())
+	}
+
+	// update sign counter
+	now := time.Now()
+	passkey.SignCount = credential.Authenticator.SignCount
+	passkey.CloneWarning = credential.Authenticator.CloneWarning
+	passkey.BackupState = credential.Flags.BackupState
+	passkey.LastUsedAt = &now
+	if _, err := uc.pkRepo.Update(passkey); err != nil {
+		return models.Token{}, err
+	}
+
+	// the sign counter went backwards, the authenticator may be cloned
+	if credential.Authenticator.CloneWarning {
+		return models.Token{}, fiber.NewError(401, "This passkey may have been cloned, please use another sign in method.")
+	}
+
+	if !passkey.User.Verified {
+		return models.Token{}, fiber.NewError(400, "Your account is not active yet, please verify your email.")
+	}
+	if passkey.User.IsLocked() {
+		return models.Token{}, fiber.NewError(403, utils.ERR_ACCOUNT_LOCKED)
+	}
+
+	token, errToken := uc.uRepo.GeneratePairToken(passkey.UserID, 0)
+	if errToken != nil {
+		return token, fiber.NewError(500, errToken.Error())
+	}
+
+	return token, nil
+}
+
+// ListPasskey implements models.PasskeyUsecase.
+func (uc *PasskeyUsecase) ListPasskey(c *fiber.Ctx) ([]*models.Passkey, *fiber.Error) {
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	data, err := uc.pkRepo.ListPasskey(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Rename implements models.PasskeyUsecase.
+func (uc *PasskeyUsecase) Rename(c *fiber.Ctx, payload models.PasskeyRenameInput) (models.Passkey, *fiber.Error) {
+	id := utils.StringToUint(c.Params("id"))
+
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return models.Passkey{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	// get data
+	obj, err := uc.pkRepo.Get(id)
+	if err != nil {
+		return obj, err
+	}
+
+	// check the owner of data
+	if obj.UserID != user.ID {
+		return obj, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	// fill update data
+	obj.Name = payload.Name
+
+	// do update
+	obj, err = uc.pkRepo.Update(obj)
+	if err != nil {
+		return obj, err
+	}
+
+	return obj, nil
+}
+
+// Delete implements models.PasskeyUsecase.
+func (uc *PasskeyUsecase) Delete(c *fiber.Ctx) *fiber.Error {
+	id := utils.StringToUint(c.Params("id"))
+
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	// get data
+	obj, err := uc.pkRepo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	// check the owner of data
+	if obj.UserID != user.ID {
+		return fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	// deleted obj
+	err = uc.pkRepo.Delete(obj)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
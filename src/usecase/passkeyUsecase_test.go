@@ -0,0 +1,416 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	testRPID   = "localhost"
+	testOrigin = "http://localhost:3000"
+)
+
+// softAuthenticator is a software authenticator with one ES256 credential and a sign counter
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentialID := make([]byte, 16)
+	rand.Read(credentialID)
+	return &softAuthenticator{key: key, credentialID: credentialID}
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (a *softAuthenticator) clientData(kind string, challenge protocol.URLEncodedBase64) []byte {
+	data, _ := json.Marshal(map[string]string{
+		"type":      kind,
+		"challenge": challenge.String(),
+		"origin":    testOrigin,
+	})
+	return data
+}
+
+// authData is the rpIdHash, the flags and the counter, then the attested credential when registering
+func (a *softAuthenticator) authData(t *testing.T, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	flags := protocol.FlagUserPresent | protocol.FlagUserVerified
+
+	var buf bytes.Buffer
+	buf.Write(rpIDHash[:])
+	if attested {
+		flags |= protocol.FlagAttestedCredentialData
+	}
+	buf.WriteByte(byte(flags))
+	binary.Write(&buf, binary.BigEndian, a.signCount)
+
+	if attested {
+		publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+			PublicKeyData: webauthncose.PublicKeyData{
+				KeyType:   int64(webauthncose.EllipticKey),
+				Algorithm: int64(webauthncose.AlgES256),
+			},
+			Curve:  1, // P-256
+			XCoord: a.key.X.FillBytes(make([]byte, 32)),
+			YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(make([]byte, 16)) // AAGUID
+		binary.Write(&buf, binary.BigEndian, uint16(len(a.credentialID)))
+		buf.Write(a.credentialID)
+		buf.Write(publicKey)
+	}
+	return buf.Bytes()
+}
+
+// create answer navigator.credentials.create() with the "none" attestation
+func (a *softAuthenticator) create(t *testing.T, options *protocol.CredentialCreation) []byte {
+	a.userHandle = options.Response.User.ID.(protocol.URLEncodedBase64)
+
+	attestation, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": a.authData(t, true),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"id":    b64(a.credentialID),
+		"rawId": b64(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    b64(a.clientData("webauthn.create", options.Response.Challenge)),
+			"attestationObject": b64(attestation),
+		},
+	})
+	return body
+}
+
+// get answer navigator.credentials.get(), the counter is incremented unless replayed
+func (a *softAuthenticator) get(t *testing.T, options *protocol.CredentialAssertion, increment bool) []byte {
+	if increment {
+		a.signCount++
+	}
+
+	authData := a.authData(t, false)
+	clientData := a.clientData("webauthn.get", options.Response.Challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"id":    b64(a.credentialID),
+		"rawId": b64(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    b64(clientData),
+			"authenticatorData": b64(authData),
+			"signature":         b64(signature),
+			"userHandle":        b64(a.userHandle),
+		},
+	})
+	return body
+}
+
+// memPasskeyRepository keep the passkeys and the sessions in memory
+type memPasskeyRepository struct {
+	sessions map[string]webauthn.SessionData
+	passkeys []*models.Passkey
+	user     models.User
+}
+
+func (r *memPasskeyRepository) SaveSession(key string, session webauthn.SessionData) error {
+	r.sessions[key] = session
+	return nil
+}
+
+func (r *memPasskeyRepository) PopSession(key string) (webauthn.SessionData, *fiber.Error) {
+	session, ok := r.sessions[key]
+	if !ok {
+		return session, fiber.NewError(400, "The session has expired, please try again.")
+	}
+	delete(r.sessions, key)
+	return session, nil
+}
+
+func (r *memPasskeyRepository) ListPasskey(userID uint) ([]*models.Passkey, *fiber.Error) {
+	var data []*models.Passkey
+	for _, obj := range r.passkeys {
+		if obj.UserID == userID {
+			copied := *obj
+			data = append(data, &copied)
+		}
+	}
+	return data, nil
+}
+
+func (r *memPasskeyRepository) Get(id uint) (models.Passkey, *fiber.Error) {
+	for _, obj := range r.passkeys {
+		if obj.ID == id {
+			return *obj, nil
+		}
+	}
+	return models.Passkey{}, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+}
+
+func (r *memPasskeyRepository) FindByCredentialID(credentialID []byte) (models.Passkey, *fiber.Error) {
+	for _, obj := range r.passkeys {
+		if bytes.Equal(obj.CredentialID, credentialID) {
+			found := *obj
+			found.User = r.user
+			return found, nil
+		}
+	}
+	return models.Passkey{}, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+}
+
+func (r *memPasskeyRepository) Create(obj models.Passkey) (models.Passkey, *fiber.Error) {
+	obj.ID = uint(len(r.passkeys) + 1)
+	r.passkeys = append(r.passkeys, &obj)
+	return obj, nil
+}
+
+func (r *memPasskeyRepository) Update(obj models.Passkey) (models.Passkey, *fiber.Error) {
+	for i, stored := range r.passkeys {
+		if stored.ID == obj.ID {
+			r.passkeys[i] = &obj
+			return obj, nil
+		}
+	}
+	return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+}
+
+func (r *memPasskeyRepository) Delete(obj models.Passkey) *fiber.Error {
+	return nil
+}
+
+// passkeyUserRepository is the part of models.UserRepository used by the passkey login
+type passkeyUserRepository struct {
+	models.UserRepository
+	user models.User
+}
+
+func (r *passkeyUserRepository) FindUserByIdentity(identity string) (models.User, *fiber.Error) {
+	if identity != r.user.Email {
+		return models.User{}, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return r.user, nil
+}
+
+func (r *passkeyUserRepository) GeneratePairToken(userID uint, organizationID uint) (models.Token, error) {
+	return models.Token{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func newPasskeyTest(t *testing.T) (models.PasskeyUsecase, *memPasskeyRepository, models.User) {
+	wa, err := webauthn.New(&webauthn.Config{
+		RPID:          testRPID,
+		RPDisplayName: "myapp",
+		RPOrigins:     []string{testOrigin},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := models.User{Email: "jane@example.com", Username: "jane", Verified: true}
+	user.ID = 1
+
+	pkRepo := &memPasskeyRepository{sessions: map[string]webauthn.SessionData{}, user: user}
+	uc := NewPasskeyUsecase(pkRepo, &passkeyUserRepository{user: user}, wa)
+	return uc, pkRepo, user
+}
+
+// register run the registration ceremony of the authenticator for the user
+func register(t *testing.T, app *fiber.App, uc models.PasskeyUsecase, user models.User, authenticator *softAuthenticator) models.Passkey {
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	c.Locals("user", user)
+
+	options, err := uc.BeginRegistration(c)
+	if err != nil {
+		t.Fatalf("BeginRegistration: %v", err)
+	}
+
+	c.Request().SetBody(authenticator.create(t, options))
+	passkey, err := uc.FinishRegistration(c, "")
+	if err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+	return passkey
+}
+
+func login(uc models.PasskeyUsecase, email string, answer func(*protocol.CredentialAssertion) []byte) (models.Token, *fiber.Error) {
+	options, err := uc.BeginLogin(context.TODO(), models.PasskeyLoginInput{Email: email})
+	if err != nil {
+		return models.Token{}, err
+	}
+	return uc.FinishLogin(context.TODO(), options.SessionID, answer(options.Options))
+}
+
+func TestPasskeyRegistrationAndLogin(t *testing.T) {
+	app := fiber.New()
+	uc, pkRepo, user := newPasskeyTest(t)
+	authenticator := newSoftAuthenticator(t)
+
+	passkey := register(t, app, uc, user, authenticator)
+	if passkey.Name != "Passkey 1" || !bytes.Equal(passkey.CredentialID, authenticator.credentialID) {
+		t.Fatalf("unexpected passkey %+v", passkey)
+	}
+
+	// by email
+	token, err := login(uc, user.Email, func(options *protocol.CredentialAssertion) []byte {
+		return authenticator.get(t, options, true)
+	})
+	if err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+	if token.AccessToken == "" {
+		t.Fatal("no token")
+	}
+	if stored := pkRepo.passkeys[0]; stored.SignCount != 1 || stored.LastUsedAt == nil {
+		t.Fatalf("the counter isn't stored: %+v", stored)
+	}
+
+	// discoverable, without email
+	if _, err := login(uc, "", func(options *protocol.CredentialAssertion) []byte {
+		return authenticator.get(t, options, true)
+	}); err != nil {
+		t.Fatalf("discoverable FinishLogin: %v", err)
+	}
+	if pkRepo.passkeys[0].SignCount != 2 {
+		t.Fatalf("sign count %d, want 2", pkRepo.passkeys[0].SignCount)
+	}
+}
+
+func TestPasskeyRegisterTwice(t *testing.T) {
+	app := fiber.New()
+	uc, _, user := newPasskeyTest(t)
+	authenticator := newSoftAuthenticator(t)
+	register(t, app, uc, user, authenticator)
+
+	// the registered credential is excluded
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	c.Locals("user", user)
+	options, err := uc.BeginRegistration(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(options.Response.CredentialExcludeList) != 1 {
+		t.Fatalf("excluded %d credentials, want 1", len(options.Response.CredentialExcludeList))
+	}
+}
+
+func TestPasskeyReplayedResponse(t *testing.T) {
+	app := fiber.New()
+	uc, _, user := newPasskeyTest(t)
+	authenticator := newSoftAuthenticator(t)
+	register(t, app, uc, user, authenticator)
+
+	options, err := uc.BeginLogin(context.TODO(), models.PasskeyLoginInput{Email: user.Email})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := authenticator.get(t, options.Options, true)
+	if _, err := uc.FinishLogin(context.TODO(), options.SessionID, body); err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+
+	// the session is consumed by the first login
+	if _, err := uc.FinishLogin(context.TODO(), options.SessionID, body); err == nil {
+		t.Fatal("the replayed response is accepted")
+	}
+}
+
+func TestPasskeyClonedSignCount(t *testing.T) {
+	app := fiber.New()
+	uc, pkRepo, user := newPasskeyTest(t)
+	authenticator := newSoftAuthenticator(t)
+	register(t, app, uc, user, authenticator)
+
+	answer := func(increment bool) func(*protocol.CredentialAssertion) []byte {
+		return func(options *protocol.CredentialAssertion) []byte {
+			return authenticator.get(t, options, increment)
+		}
+	}
+
+	if _, err := login(uc, user.Email, answer(true)); err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+
+	// a clone sign the new challenge with the same counter
+	_, err := login(uc, user.Email, answer(false))
+	if err == nil || err.Code != 401 {
+		t.Fatalf("the cloned authenticator is accepted: %v", err)
+	}
+	if !pkRepo.passkeys[0].CloneWarning {
+		t.Fatal("the clone warning isn't stored")
+	}
+}
+
+func TestPasskeyWrongSignature(t *testing.T) {
+	app := fiber.New()
+	uc, _, user := newPasskeyTest(t)
+	authenticator := newSoftAuthenticator(t)
+	register(t, app, uc, user, authenticator)
+
+	// another key answer with the registered credential ID
+	impostor := newSoftAuthenticator(t)
+	impostor.credentialID = authenticator.credentialID
+	impostor.userHandle = authenticator.userHandle
+
+	_, err := login(uc, user.Email, func(options *protocol.CredentialAssertion) []byte {
+		return impostor.get(t, options, true)
+	})
+	if err == nil || err.Code != 401 {
+		t.Fatalf("the wrong signature is accepted: %v", err)
+	}
+}
+
+func TestPasskeySuspendedUser(t *testing.T) {
+	app := fiber.New()
+	uc, pkRepo, user := newPasskeyTest(t)
+	authenticator := newSoftAuthenticator(t)
+	register(t, app, uc, user, authenticator)
+
+	pkRepo.user.UserProfile.StatusID = models.StatusSuspended
+	_, err := login(uc, user.Email, func(options *protocol.CredentialAssertion) []byte {
+		return authenticator.get(t, options, true)
+	})
+	if err == nil || err.Code != 403 {
+		t.Fatalf("the suspended user is signed in: %v", err)
+	}
+}
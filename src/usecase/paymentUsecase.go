@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"myapp/pkg/payment"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PaymentUsecase struct {
+	payRepo models.PaymentRepository
+	oRepo   models.OrderRepository
+	gateway models.PaymentGateway
+}
+
+// NewPaymentUsecase will create an object that represent the models.PaymentUsecase interface
+func NewPaymentUsecase(pay models.PaymentRepository, order models.OrderRepository, gateway models.PaymentGateway) models.PaymentUsecase {
+	return &PaymentUsecase{
+		payRepo: pay,
+		oRepo:   order,
+		gateway: gateway,
+	}
+}
+
+// GetPayment implements models.PaymentUsecase.
+func (uc *PaymentUsecase) GetPayment(c *fiber.Ctx) (models.Payment, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Payment{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	order, err := uc.oRepo.GetOrder(utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return models.Payment{}, err
+	}
+	if !order.IsBuyer(tenant.UserID) && !isSeller(tenant, order) {
+		return models.Payment{}, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+
+	obj, err := uc.payRepo.LatestPayment(order.ID)
+	if err != nil {
+		return obj, err
+	}
+
+	// the client secret is only for the buyer
+	if !order.IsBuyer(tenant.UserID) {
+		obj.ClientSecret = ""
+	}
+
+	return obj, nil
+}
+
+// CreatePayment implements models.PaymentUsecase.
+func (uc *PaymentUsecase) CreatePayment(c *fiber.Ctx) (models.Payment, *fiber.Error) {
+	order, err := uc.buyerOrder(c)
+	if err != nil {
+		return models.Payment{}, err
+	}
+	if order.Status != models.OrderPending {
+		return models.Payment{}, fiber.NewError(422, "Only the pending order can be paid.")
+	}
+
+	// the pending payment is reused, a new attempt is only made after a failure
+	latest, err := uc.payRepo.LatestPayment(order.ID)
+	if err == nil && latest.Status == payment.StatusPending && latest.Provider == uc.gateway.Name() {
+		return latest, nil
+	}
+	if err != nil && err.Code != 404 {
+		return models.Payment{}, err
+	}
+
+	intent, errGateway := uc.gateway.CreateIntent(c.UserContext(), payment.IntentRequest{
+		Amount:         order.Total,
+		Reference:      fmt.Sprintf("order_%d", order.ID),
+		Description:    fmt.Sprintf("Order #%d", order.ID),
+		IdempotencyKey: fmt.Sprintf("order-%d-%d", order.ID, latest.ID),
+	})
+	if errGateway != nil {
+		return models.Payment{}, fiber.NewError(502, errGateway.Error())
+	}
+
+	obj := models.Payment{
+		Provider:      uc.gateway.Name(),
+		IntentID:      intent.ID,
+		ClientSecret:  intent.ClientSecret,
+		Status:        payment.StatusPending,
+		Amount:        order.Total,
+		FailureReason: intent.FailureReason,
+		OrderID:       order.ID,
+	}
+
+	return uc.payRepo.Create(obj)
+}
+
+// ConfirmPayment implements models.PaymentUsecase.
+func (uc *PaymentUsecase) ConfirmPayment(c *fiber.Ctx) (models.Payment, *fiber.Error) {
+	order, err := uc.buyerOrder(c)
+	if err != nil {
+		return models.Payment{}, err
+	}
+
+	obj, err := uc.payRepo.LatestPayment(order.ID)
+	if err != nil {
+		return obj, err
+	}
+	if obj.Provider != uc.gateway.Name() {
+		return obj, fiber.NewError(422, "The payment was made with another provider.")
+	}
+	if obj.Status != payment.StatusPending && obj.Status != payment.StatusFailed {
+		return obj, fiber.NewError(422, "The payment is already "+string(obj.Status)+".")
+	}
+
+	intent, errGateway := uc.gateway.Confirm(c.UserContext(), obj.IntentID)
+	if errGateway != nil {
+		return obj, fiber.NewError(502, errGateway.Error())
+	}
+
+	// the answer of the provider is applied like its webhook, the webhook of the same status is then ignored
+	result, err := uc.apply(payment.Event{
+		ID:            fmt.Sprintf("confirm:%s:%s", intent.ID, intent.Status),
+		Type:          "confirm",
+		IntentID:      intent.ID,
+		Status:        intent.Status,
+		Amount:        intent.Amount,
+		FailureReason: intent.FailureReason,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		return obj, err
+	}
+
+	return result.Payment, nil
+}
+
+// Refund implements models.PaymentUsecase.
+func (uc *PaymentUsecase) Refund(c *fiber.Ctx) (models.Payment, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Payment{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	order, err := uc.oRepo.GetOrder(utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return models.Payment{}, err
+	}
+
+	// the seller give the money back
+	if !isSeller(tenant, order) {
+		if order.IsBuyer(tenant.UserID) {
+			return models.Payment{}, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+		}
+		return models.Payment{}, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	if !order.Status.CanTransition(models.OrderRefunded) {
+		return models.Payment{}, fiber.NewError(422, "The "+string(order.Status)+" order can't be refunded.")
+	}
+
+	obj, err := uc.payRepo.LatestPayment(order.ID)
+	if err != nil {
+		return obj, err
+	}
+	if obj.Status != payment.StatusSucceeded {
+		return obj, fiber.NewError(422, "Only the succeeded payment can be refunded.")
+	}
+
+	result, err := uc.refund(obj)
+	if err != nil {
+		return obj, err
+	}
+
+	return result.Payment, nil
+}
+
+// Webhook implements models.PaymentUsecase.
+func (uc *PaymentUsecase) Webhook(c *fiber.Ctx) *fiber.Error {
+	if c.Params("provider") != uc.gateway.Name() {
+		return fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+
+	event, errVerify := uc.gateway.VerifyWebhook(c.Body(), func(key string) string { return c.Get(key) })
+	if errors.Is(errVerify, payment.ErrInvalidSignature) {
+		return fiber.NewError(401, errVerify.Error())
+	}
+	if errVerify != nil {
+		return fiber.NewError(400, errVerify.Error())
+	}
+
+	// the event types not related to the order are acknowledged
+	if event.Status == "" {
+		return nil
+	}
+
+	_, err := uc.apply(event)
+	return err
+}
+
+// apply the event and notify the buyer and the seller when the order has changed
+func (uc *PaymentUsecase) apply(event payment.Event) (models.PaymentResult, *fiber.Error) {
+	result, err := uc.payRepo.ApplyEvent(uc.gateway.Name(), event)
+	if err != nil {
+		return result, err
+	}
+
+	// the buyer paid the order cancelled in the meantime, the money is given back
+	if result.Applied && result.Payment.Status == payment.StatusSucceeded && result.Order.Status == models.OrderCancelled {
+		if _, err := uc.refund(result.Payment); err != nil {
+			log.Printf("failed to refund the payment %d of the cancelled order %d: %s", result.Payment.ID, result.Order.ID, err.Message)
+		}
+		return result, nil
+	}
+
+	if result.OrderChanged {
+		order, err := uc.oRepo.GetOrder(result.Order.ID)
+		if err == nil {
+			if order.Buyer != nil {
+				uc.oRepo.SendOrderEmail(order, *order.Buyer)
+			}
+			if order.Seller != nil {
+				uc.oRepo.SendOrderEmail(order, *order.Seller)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// refund the full payment at the provider and apply it
+func (uc *PaymentUsecase) refund(obj models.Payment) (models.PaymentResult, *fiber.Error) {
+	refund, errGateway := uc.gateway.Refund(context.Background(), obj.IntentID, obj.Amount)
+	if errGateway != nil {
+		return models.PaymentResult{}, fiber.NewError(502, errGateway.Error())
+	}
+
+	return uc.apply(payment.Event{
+		ID:        "refund:" + refund.ID,
+		Type:      "refund",
+		IntentID:  obj.IntentID,
+		Status:    payment.StatusRefunded,
+		Amount:    refund.Amount,
+		CreatedAt: time.Now(),
+	})
+}
+
+// buyerOrder return the order of the current user
+func (uc *PaymentUsecase) buyerOrder(c *fiber.Ctx) (models.Order, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Order{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	order, err := uc.oRepo.GetOrder(utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return order, err
+	}
+	if !order.IsBuyer(tenant.UserID) {
+		return order, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+
+	return order, nil
+}
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"fmt"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// the maximum number of images in the product gallery
+const maxProductImages = 10
+
+type ProductImageUsecase struct {
+	iRepo models.ProductImageRepository
+	pRepo models.ProductRepository
+}
+
+// NewProductImageUsecase will create an object that represent the models.ProductImageUsecase interface
+func NewProductImageUsecase(image models.ProductImageRepository, product models.ProductRepository) models.ProductImageUsecase {
+	return &ProductImageUsecase{
+		iRepo: image,
+		pRepo: product,
+	}
+}
+
+// ListImage implements models.ProductImageUsecase.
+func (uc *ProductImageUsecase) ListImage(c *fiber.Ctx) ([]*models.ProductImage, *fiber.Error) {
+	product, err := uc.product(c, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.iRepo.ListImage(product.ID)
+}
+
+// AddImage implements models.ProductImageUsecase.
+func (uc *ProductImageUsecase) AddImage(c *fiber.Ctx, payload models.ProductImageInput) ([]*models.ProductImage, *fiber.Error) {
+	product, err := uc.product(c, true)
+	if err != nil {
+		return nil, err
+	}
+
+	uploaded, err := uploadProductImages(c, product, strings.TrimSpace(payload.AltText))
+	if err != nil {
+		return nil, err
+	}
+	if len(uploaded) == 0 {
+		return nil, fiber.NewError(422, "Please upload at least one image.")
+	}
+
+	images, err := uc.iRepo.AddImages(product, uploaded)
+	if err != nil {
+		removeProductImages(uploaded)
+		return nil, err
+	}
+
+	// the first uploaded image become the new cover
+	if payload.IsCover {
+		if err := uc.iRepo.SetCover(product.ID, images[0].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// the cover and the gallery are in the catalog
+	uc.pRepo.ClearCatalogCache()
+
+	return uc.iRepo.ListImage(product.ID)
+}
+
+// UpdateImage implements models.ProductImageUsecase.
+func (uc *ProductImageUsecase) UpdateImage(c *fiber.Ctx, payload models.ProductImageInput) (models.ProductImage, *fiber.Error) {
+	product, err := uc.product(c, true)
+	if err != nil {
+		return models.ProductImage{}, err
+	}
+
+	obj, err := uc.iRepo.GetImage(product.ID, utils.StringToUint(c.Params("image_id")))
+	if err != nil {
+		return obj, err
+	}
+
+	obj.AltText = strings.TrimSpace(payload.AltText)
+	obj, err = uc.iRepo.UpdateImage(obj)
+	if err != nil {
+		return obj, err
+	}
+
+	// the cover is changed, unset is ignored because the product always has a cover
+	if payload.IsCover && !obj.IsCover {
+		if err := uc.iRepo.SetCover(product.ID, obj.ID); err != nil {
+			return obj, err
+		}
+		obj.IsCover = true
+	}
+
+	uc.pRepo.ClearCatalogCache()
+
+	return obj, nil
+}
+
+// Reorder implements models.ProductImageUsecase.
+func (uc *ProductImageUsecase) Reorder(c *fiber.Ctx, payload models.ProductImageOrderInput) ([]*models.ProductImage, *fiber.Error) {
+	product, err := uc.product(c, true)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := uc.iRepo.ListImage(product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the new order must contain every image exactly once
+	current := make(map[uint]bool, len(data))
+	for _, obj := range data {
+		current[obj.ID] = true
+	}
+	if len(payload.ImageIDs) != len(data) {
+		return nil, fiber.NewError(422, "The order must contain all images of the product.")
+	}
+	for _, id := range payload.ImageIDs {
+		if !current[id] {
+			return nil, fiber.NewError(422, fmt.Sprintf("Image %d doesn't exists or is listed twice.", id))
+		}
+		delete(current, id)
+	}
+
+	if err := uc.iRepo.Reorder(product.ID, payload.ImageIDs); err != nil {
+		return nil, err
+	}
+
+	uc.pRepo.ClearCatalogCache()
+
+	return uc.iRepo.ListImage(product.ID)
+}
+
+// DeleteImage implements models.ProductImageUsecase.
+func (uc *ProductImageUsecase) DeleteImage(c *fiber.Ctx) *fiber.Error {
+	product, err := uc.product(c, true)
+	if err != nil {
+		return err
+	}
+
+	obj, err := uc.iRepo.GetImage(product.ID, utils.StringToUint(c.Params("image_id")))
+	if err != nil {
+		return err
+	}
+
+	if err := uc.iRepo.DeleteImage(obj); err != nil {
+		return err
+	}
+
+	uc.pRepo.ClearCatalogCache()
+
+	return nil
+}
+
+// product return the product visible in the tenant,
+// manage check the current user is allowed to change it
+func (uc *ProductImageUsecase) product(c *fiber.Ctx, manage bool) (models.Product, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Product{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	product, err := uc.pRepo.GetProduct(tenant, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return product, err
+	}
+
+	// check the owner of data
+	if manage && !tenant.CanManage(product.UserID) {
+		return product, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	return product, nil
+}
+
+// uploadProductImages save the uploaded "image" files of the multipart form,
+// the product gallery can't have more than maxProductImages
+func uploadProductImages(c *fiber.Ctx, product models.Product, altText string) ([]*models.ProductImage, *fiber.Error) {
+	var images []*models.ProductImage
+
+	// MultipartForm POST
+	form, err := c.MultipartForm()
+	if err != nil {
+		return images, nil
+	}
+	files := form.File["image"]
+
+	count := len(product.Images)
+	if count == 0 && product.Image != nil {
+		// the image uploaded before the gallery
+		count = 1
+	}
+	if count+len(files) > maxProductImages {
+		return nil, fiber.NewError(422, fmt.Sprintf("A product can't have more than %d images.", maxProductImages))
+	}
+
+	// Loop through files:
+	for _, file := range files {
+		// Save the files to disk:
+		imageUrl, errFile := utils.ImageUpload(file, "products")
+		if errFile != nil {
+			removeProductImages(images)
+			return nil, fiber.NewError(500, errFile.Error())
+		}
+
+		images = append(images, &models.ProductImage{Path: imageUrl, AltText: altText})
+	}
+
+	return images, nil
+}
+
+// removeProductImages remove the uploaded files when the images can't be saved
+func removeProductImages(images []*models.ProductImage) {
+	for _, obj := range images {
+		if obj.IsLocal() {
+			utils.RemoveFileSilence(obj.Path, string(models.ImageFile))
+		}
+	}
+}
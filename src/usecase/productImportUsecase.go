@@ -0,0 +1,439 @@
+package usecase
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"myapp/pkg/money"
+	"myapp/pkg/spreadsheet"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// the number of files imported at the same time
+	importWorkers = 2
+	// the progress is saved every n rows
+	importProgressEvery = 50
+)
+
+type ProductImportUsecase struct {
+	importRepo models.ProductImportRepository
+	pRepo      models.ProductRepository
+	cRepo      models.CategoryRepository
+	// the currency of the price without currency, see configs.BaseCurrency
+	currency string
+	workers  chan struct{}
+}
+
+// NewProductImportUsecase will create an object that represent the models.ProductImportUsecase interface
+func NewProductImportUsecase(productImport models.ProductImportRepository, product models.ProductRepository, category models.CategoryRepository, currency string) models.ProductImportUsecase {
+	return &ProductImportUsecase{
+		importRepo: productImport,
+		pRepo:      product,
+		cRepo:      category,
+		currency:   currency,
+		workers:    make(chan struct{}, importWorkers),
+	}
+}
+
+// importFailure is a row of the error report
+type importFailure struct {
+	Row        int
+	ExternalID string
+	Message    string
+}
+
+// Import implements models.ProductImportUsecase.
+func (uc *ProductImportUsecase) Import(c *fiber.Ctx) (models.ProductImport, *fiber.Error) {
+	var obj models.ProductImport
+
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return obj, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	fileHeader, errFile := c.FormFile("file")
+	if errFile != nil {
+		return obj, fiber.NewError(422, "Please upload the CSV or XLSX file.")
+	}
+	format := spreadsheet.Format(fileHeader.Filename)
+	if format == "" {
+		return obj, fiber.NewError(422, "Only the CSV and XLSX files can be imported.")
+	}
+	if fileHeader.Size > models.MaxImportSize {
+		return obj, fiber.NewError(422, fmt.Sprintf("The file can't be larger than %d MB.", models.MaxImportSize>>20))
+	}
+
+	// only one import at a time, a job older than a day was lost on restart
+	imports, err := uc.importRepo.ListImport(tenant)
+	if err != nil {
+		return obj, err
+	}
+	for _, active := range imports {
+		if active.Status != models.ImportPending && active.Status != models.ImportProcessing {
+			continue
+		}
+		if time.Since(active.CreatedAt) < 24*time.Hour {
+			return obj, fiber.NewError(422, "Your previous import is still in progress.")
+		}
+
+		active.Status = models.ImportFailed
+		active.Error = "The import was interrupted."
+		uc.importRepo.Update(*active)
+	}
+
+	filePath := fmt.Sprintf("imports/%d/import-%s.%s", tenant.UserID, time.Now().Format("20060102150405"), format)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+	if err := c.SaveFile(fileHeader, filePath); err != nil {
+		return obj, fiber.NewError(500, err.Error())
+	}
+
+	obj.Status = models.ImportPending
+	obj.Format = format
+	obj.FileName = filepath.Base(fileHeader.Filename)
+	obj.FilePath = filePath
+	obj.UserID = tenant.UserID
+	obj.OrganizationID = tenant.OrganizationPtr()
+
+	obj, err = uc.importRepo.Create(obj)
+	if err != nil {
+		os.Remove(filePath)
+		return obj, err
+	}
+
+	// import the rows in background
+	go uc.run(obj, tenant)
+
+	return obj, nil
+}
+
+// ListImport implements models.ProductImportUsecase.
+func (uc *ProductImportUsecase) ListImport(c *fiber.Ctx) ([]*models.ProductImport, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	return uc.importRepo.ListImport(tenant)
+}
+
+// GetImport implements models.ProductImportUsecase.
+func (uc *ProductImportUsecase) GetImport(c *fiber.Ctx) (models.ProductImport, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.ProductImport{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	return uc.importRepo.GetImport(tenant, utils.StringToUint(c.Params("import_id")))
+}
+
+// Report implements models.ProductImportUsecase.
+func (uc *ProductImportUsecase) Report(c *fiber.Ctx) (models.ProductImport, *fiber.Error) {
+	obj, err := uc.GetImport(c)
+	if err != nil {
+		return obj, err
+	}
+
+	if obj.ReportPath == "" {
+		return obj, fiber.NewError(404, "The import has no error report.")
+	}
+
+	return obj, nil
+}
+
+// Export implements models.ProductImportUsecase.
+func (uc *ProductImportUsecase) Export(c *fiber.Ctx) *fiber.Error {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	format := strings.ToLower(c.Query("format", spreadsheet.CSV))
+	contentType, ok := spreadsheet.ContentType[format]
+	if !ok {
+		return fiber.NewError(400, "The format must be csv or xlsx.")
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Attachment(fmt.Sprintf("products-%s.%s", time.Now().Format("20060102"), format))
+
+	// the rows are written while they are read, the products are never all in memory
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		writer, err := spreadsheet.NewWriter(w, format)
+		if err != nil {
+			return
+		}
+
+		errWrite := writer.Write(models.ProductColumns)
+		errD := uc.pRepo.EachProduct(tenant, 200, func(data []*models.Product) error {
+			if errWrite != nil {
+				return errWrite
+			}
+			for _, obj := range data {
+				if err := writer.Write(productRow(obj)); err != nil {
+					return err
+				}
+			}
+			return w.Flush()
+		})
+		if errD != nil {
+			log.Printf("Product export of user %d error: %s", tenant.UserID, errD.Message)
+		}
+
+		writer.Close()
+		w.Flush()
+	})
+
+	return nil
+}
+
+func (uc *ProductImportUsecase) run(obj models.ProductImport, tenant models.Tenant) {
+	uc.workers <- struct{}{}
+	defer func() { <-uc.workers }()
+
+	obj.Status = models.ImportProcessing
+	obj, _ = uc.importRepo.Update(obj)
+
+	failures, err := uc.importFile(&obj, tenant)
+	if err != nil {
+		log.Printf("Product import %d error: %s", obj.ID, err.Error())
+		obj.Status = models.ImportFailed
+		obj.Error = err.Error()
+	} else {
+		obj.Status = models.ImportDone
+	}
+
+	if len(failures) > 0 {
+		reportPath := strings.TrimSuffix(obj.FilePath, "."+obj.Format) + "-errors." + obj.Format
+		if err := writeImportReport(reportPath, obj.Format, failures); err != nil {
+			log.Printf("Product import %d report error: %s", obj.ID, err.Error())
+			os.Remove(reportPath)
+		} else {
+			obj.ReportPath = reportPath
+		}
+	}
+
+	// the uploaded file isn't needed anymore
+	os.Remove(obj.FilePath)
+	obj.FilePath = ""
+
+	now := time.Now()
+	obj.FinishedAt = &now
+	uc.importRepo.Update(obj)
+
+	if obj.CreatedRows > 0 || obj.UpdatedRows > 0 {
+		uc.cRepo.ClearCountProduct(obj.OrganizationID)
+		uc.pRepo.ClearCatalogCache()
+	}
+}
+
+// importFile upsert the rows of the file, the invalid rows are returned for the report
+func (uc *ProductImportUsecase) importFile(obj *models.ProductImport, tenant models.Tenant) ([]importFailure, error) {
+	file, err := os.Open(obj.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := spreadsheet.ReadAll(file, info.Size(), obj.Format)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read the file: %s", err.Error())
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("The file is empty.")
+	}
+
+	// the columns are found by the header
+	columns := map[string]int{}
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"external_id", "title", "price"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("The column %s is missing.", name)
+		}
+	}
+	cell := func(row []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	// the blank rows are skipped, the row number is the line of the file
+	var lines []int
+	for i, row := range rows[1:] {
+		if strings.TrimSpace(strings.Join(row, "")) != "" {
+			lines = append(lines, i+2)
+		}
+	}
+	if len(lines) > models.MaxImportRows {
+		return nil, fmt.Errorf("The file can't have more than %d rows.", models.MaxImportRows)
+	}
+
+	obj.TotalRows = len(lines)
+	*obj, _ = uc.importRepo.Update(*obj)
+
+	var failures []importFailure
+	for i, line := range lines {
+		row := rows[line-1]
+		payload := models.ProductInput{
+			ExternalID:  cell(row, "external_id"),
+			Title:       cell(row, "title"),
+			Description: cell(row, "description"),
+			Price:       money.Decimal(cell(row, "price")),
+			Currency:    strings.ToUpper(cell(row, "currency")),
+		}
+		if tags := cell(row, "tags"); tags != "" {
+			payload.Tags = strings.Split(tags, ",")
+		}
+
+		created, message := uc.importRow(tenant, payload, cell(row, "category_id"))
+		switch {
+		case message != "":
+			obj.FailedRows++
+			failures = append(failures, importFailure{Row: line, ExternalID: payload.ExternalID, Message: message})
+		case created:
+			obj.CreatedRows++
+		default:
+			obj.UpdatedRows++
+		}
+		obj.ProcessedRows++
+
+		if (i+1)%importProgressEvery == 0 {
+			*obj, _ = uc.importRepo.Update(*obj)
+		}
+	}
+
+	return failures, nil
+}
+
+// importRow create or update the product of the external ID, the message is the reason of the rejected row
+func (uc *ProductImportUsecase) importRow(tenant models.Tenant, payload models.ProductInput, categoryID string) (bool, string) {
+	var messages []string
+	if categoryID != "" {
+		id, err := strconv.ParseUint(categoryID, 10, 32)
+		if err != nil {
+			messages = append(messages, "category_id must be a number")
+		}
+		payload.CategoryID = uint(id)
+	}
+	if payload.ExternalID == "" {
+		messages = append(messages, "external_id is required")
+	}
+	errD := models.ValidateStruct(payload)
+	for _, detail := range errD.Errors {
+		messages = append(messages, detail.Message)
+	}
+	if len(messages) > 0 {
+		return false, strings.Join(messages, "; ")
+	}
+
+	obj, err := uc.pRepo.FindByExternalID(tenant.UserID, payload.ExternalID)
+	created := err != nil && err.Code == 404
+	switch {
+	case created:
+		// the new product is a draft until it's published
+		obj = models.Product{
+			UserID:         tenant.UserID,
+			OrganizationID: tenant.OrganizationPtr(),
+			Status:         models.ProductDraft,
+			ExternalID:     &payload.ExternalID,
+		}
+	case err != nil:
+		return false, err.Message
+	case orgKey(obj.OrganizationID) != tenant.OrganizationID:
+		return false, "The external ID is used by a product of another workspace."
+	}
+
+	price, err := parseProductPrice(payload, obj.Price.Currency, uc.currency)
+	if err != nil {
+		return false, err.Message
+	}
+
+	obj.Title = payload.Title
+	obj.Description = payload.Description
+	obj.Price = price
+	if err := fillCategoryAndTags(uc.cRepo, &obj, payload); err != nil {
+		return false, err.Message
+	}
+
+	if created {
+		_, err = uc.pRepo.Create(obj, tenant.UserID)
+	} else {
+		_, err = uc.pRepo.Update(obj, tenant.UserID)
+	}
+	if err != nil {
+		return false, err.Message
+	}
+
+	return created, ""
+}
+
+// productRow is the product in the columns of models.ProductColumns
+func productRow(obj *models.Product) []string {
+	var externalID, categoryID string
+	if obj.ExternalID != nil {
+		externalID = *obj.ExternalID
+	}
+	if obj.CategoryID != nil {
+		categoryID = strconv.FormatUint(uint64(*obj.CategoryID), 10)
+	}
+	tags := make([]string, 0, len(obj.Tags))
+	for _, tag := range obj.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	return []string{
+		externalID,
+		obj.Title,
+		obj.Description,
+		obj.Price.String(),
+		obj.Price.Currency,
+		categoryID,
+		strings.Join(tags, ", "),
+		string(obj.Status),
+		strconv.FormatUint(uint64(obj.ID), 10),
+	}
+}
+
+// writeImportReport write the rejected rows in the format of the imported file
+func writeImportReport(filePath string, format string, failures []importFailure) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer, err := spreadsheet.NewWriter(file, format)
+	if err != nil {
+		return err
+	}
+	if err := writer.Write([]string{"row", "external_id", "errors"}); err != nil {
+		return err
+	}
+	for _, failure := range failures {
+		if err := writer.Write([]string{strconv.Itoa(failure.Row), failure.ExternalID, failure.Message}); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
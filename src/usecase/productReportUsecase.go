@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"myapp/pkg/response"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductReportUsecase struct {
+	rRepo models.ProductReportRepository
+	pRepo models.ProductRepository
+	cRepo models.CategoryRepository
+}
+
+// NewProductReportUsecase will create an object that represent the models.ProductReportUsecase interface
+func NewProductReportUsecase(report models.ProductReportRepository, product models.ProductRepository, category models.CategoryRepository) models.ProductReportUsecase {
+	return &ProductReportUsecase{
+		rRepo: report,
+		pRepo: product,
+		cRepo: category,
+	}
+}
+
+// Report implements models.ProductReportUsecase.
+func (uc *ProductReportUsecase) Report(c *fiber.Ctx, payload models.ProductReportInput) (models.ProductReport, *fiber.Error) {
+	var obj models.ProductReport
+
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return obj, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	// the product must be visible to the reporter
+	product, err := uc.pRepo.GetProduct(tenant, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return obj, err
+	}
+	if product.UserID == tenant.UserID {
+		return obj, fiber.NewError(422, "You can't report your own product.")
+	}
+
+	reported, err := uc.rRepo.HasOpenReport(product.ID, tenant.UserID)
+	if err != nil {
+		return obj, err
+	}
+	if reported {
+		return obj, fiber.NewError(409, "You have already reported this product.")
+	}
+
+	obj.Reason = payload.Reason
+	obj.Details = payload.Details
+	obj.Status = models.ReportOpen
+	obj.ProductID = product.ID
+	obj.UserID = tenant.UserID
+
+	return uc.rRepo.Create(obj)
+}
+
+// ListReport implements models.ProductReportUsecase.
+func (uc *ProductReportUsecase) ListReport(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
+	// 	Parse the query parameters
+	page := c.Query("page", "1")
+	limit := c.Query("per_page", "10")
+
+	// Convert the page and limit to integers
+	pageInt, _ := strconv.Atoi(page)
+	limitInt, _ := strconv.Atoi(limit)
+	pageInt, limitInt = response.ClampPage(pageInt, limitInt)
+
+	query, errQuery := models.ProductReportQuery.Parse(c)
+	if errQuery != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, errQuery.Error())
+	}
+
+	// make param pagination struct
+	pagParam := response.ParamsPagination{
+		Page:          pageInt,
+		Limit:         limitInt,
+		SortQuery:     query.OrderBy(),
+		Filter:        query.Scope,
+		Cursor:        c.Query("cursor"),
+		CursorMode:    c.Context().QueryArgs().Has("cursor"),
+		EstimateCount: c.Query("count") == "estimate",
+		NoPage:        c.Query("no_page"),
+	}
+
+	return uc.rRepo.ListReport(pagParam)
+}
+
+// GetReport implements models.ProductReportUsecase.
+func (uc *ProductReportUsecase) GetReport(c *fiber.Ctx) (models.ProductReport, *fiber.Error) {
+	return uc.rRepo.GetReport(utils.StringToUint(c.Params("report_id")))
+}
+
+// Resolve implements models.ProductReportUsecase.
+func (uc *ProductReportUsecase) Resolve(c *fiber.Ctx, payload models.ReportResolveInput) (models.ProductReport, *fiber.Error) {
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return models.ProductReport{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.rRepo.GetReport(utils.StringToUint(c.Params("report_id")))
+	if err != nil {
+		return obj, err
+	}
+	if obj.Status != models.ReportOpen {
+		return obj, fiber.NewError(422, "The report is already resolved.")
+	}
+	product := *obj.Product
+
+	// the deleted product is hidden too, it stays hidden when the seller restore it
+	if payload.Action == models.ModerationDelete && !product.DeletedAt.Valid {
+		if err := uc.pRepo.Delete(product, user.ID); err != nil {
+			return obj, err
+		}
+	}
+
+	now := time.Now()
+	obj.Status = models.ReportResolved
+	obj.Action = &payload.Action
+	obj.Note = payload.Note
+	obj.ResolvedAt = &now
+	obj.ResolvedByID = &user.ID
+
+	if _, err := uc.rRepo.Resolve(obj); err != nil {
+		return obj, err
+	}
+
+	switch payload.Action {
+	case models.ModerationHide, models.ModerationDelete:
+		// the category count only has the live products
+		uc.cRepo.ClearCountProduct(product.OrganizationID)
+		uc.pRepo.ClearCatalogCache()
+		uc.rRepo.SendModerationEmail(product, payload.Action, payload.Note)
+	case models.ModerationWarn:
+		uc.rRepo.SendModerationEmail(product, payload.Action, payload.Note)
+	}
+
+	return uc.rRepo.GetReport(obj.ID)
+}
+
+// Unhide implements models.ProductReportUsecase.
+func (uc *ProductReportUsecase) Unhide(c *fiber.Ctx) *fiber.Error {
+	product, err := uc.rRepo.Unhide(utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return err
+	}
+
+	uc.cRepo.ClearCountProduct(product.OrganizationID)
+	uc.pRepo.ClearCatalogCache()
+
+	return nil
+}
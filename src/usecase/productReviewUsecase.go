@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"myapp/pkg/response"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductReviewUsecase struct {
+	rRepo models.ProductReviewRepository
+	pRepo models.ProductRepository
+}
+
+// NewProductReviewUsecase will create an object that represent the models.ProductReviewUsecase interface
+func NewProductReviewUsecase(review models.ProductReviewRepository, product models.ProductRepository) models.ProductReviewUsecase {
+	return &ProductReviewUsecase{
+		rRepo: review,
+		pRepo: product,
+	}
+}
+
+// ListReview implements models.ProductReviewUsecase.
+func (uc *ProductReviewUsecase) ListReview(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
+	product, tenant, err := uc.product(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// the hidden reviews are only listed to their author
+	filter := models.ReviewFilter{ProductID: product.ID, Status: models.ReviewApproved, AuthorID: tenant.UserID}
+
+	return uc.listReview(c, filter)
+}
+
+// CreateReview implements models.ProductReviewUsecase.
+func (uc *ProductReviewUsecase) CreateReview(c *fiber.Ctx, payload models.ProductReviewInput) (models.ProductReview, *fiber.Error) {
+	var obj models.ProductReview
+
+	product, tenant, err := uc.product(c)
+	if err != nil {
+		return obj, err
+	}
+
+	// the seller can't rate its own product
+	if product.UserID == tenant.UserID {
+		return obj, fiber.NewError(403, "You can't review your own product.")
+	}
+
+	reviewed, err := uc.rRepo.HasReviewed(product.ID, tenant.UserID)
+	if err != nil {
+		return obj, err
+	}
+	if reviewed {
+		return obj, fiber.NewError(409, "You have already reviewed this product.")
+	}
+
+	payload.Sanitize()
+
+	photo, err := uploadReviewPhoto(c)
+	if err != nil {
+		return obj, err
+	}
+
+	// fill the form data
+	obj.Rating = payload.Rating
+	obj.Title = payload.Title
+	obj.Body = payload.Body
+	obj.Photo = photo
+	obj.Status = models.ReviewApproved
+	obj.ProductID = product.ID
+	obj.UserID = tenant.UserID
+
+	obj, err = uc.rRepo.Create(obj)
+	if err != nil {
+		removeReviewPhoto(photo)
+		return obj, err
+	}
+	// the catalog show the rating of the product
+	uc.pRepo.ClearCatalogCache()
+
+	return obj, nil
+}
+
+// UpdateReview implements models.ProductReviewUsecase.
+func (uc *ProductReviewUsecase) UpdateReview(c *fiber.Ctx, payload models.ProductReviewInput) (models.ProductReview, *fiber.Error) {
+	obj, err := uc.ownReview(c)
+	if err != nil {
+		return obj, err
+	}
+
+	payload.Sanitize()
+
+	photo, err := uploadReviewPhoto(c)
+	if err != nil {
+		return obj, err
+	}
+
+	// the old photo is removed after the review is saved
+	oldPhoto := obj.Photo
+	if photo != nil || payload.RemovePhoto {
+		obj.Photo = photo
+	} else {
+		oldPhoto = nil
+	}
+
+	// fill update data, the moderation status is kept
+	obj.Rating = payload.Rating
+	obj.Title = payload.Title
+	obj.Body = payload.Body
+
+	obj, err = uc.rRepo.Update(obj)
+	if err != nil {
+		removeReviewPhoto(photo)
+		return obj, err
+	}
+	removeReviewPhoto(oldPhoto)
+	uc.pRepo.ClearCatalogCache()
+
+	return obj, nil
+}
+
+// DeleteReview implements models.ProductReviewUsecase.
+func (uc *ProductReviewUsecase) DeleteReview(c *fiber.Ctx) *fiber.Error {
+	obj, err := uc.ownReview(c)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.rRepo.Delete(obj); err != nil {
+		return err
+	}
+	removeReviewPhoto(obj.Photo)
+	uc.pRepo.ClearCatalogCache()
+
+	return nil
+}
+
+// ListAllReview implements models.ProductReviewUsecase.
+func (uc *ProductReviewUsecase) ListAllReview(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
+	return uc.listReview(c, models.ReviewFilter{})
+}
+
+// Moderate implements models.ProductReviewUsecase.
+func (uc *ProductReviewUsecase) Moderate(c *fiber.Ctx, payload models.ReviewModerationInput) (models.ProductReview, *fiber.Error) {
+	obj, err := uc.rRepo.GetReview(utils.StringToUint(c.Params("review_id")))
+	if err != nil {
+		return obj, err
+	}
+
+	// the hidden review isn't counted in the product rating
+	obj.Status = payload.Status
+	obj.ModerationNote = payload.Note
+
+	obj, err = uc.rRepo.Update(obj)
+	if err != nil {
+		return obj, err
+	}
+	uc.pRepo.ClearCatalogCache()
+
+	return obj, nil
+}
+
+// AdminDeleteReview implements models.ProductReviewUsecase.
+func (uc *ProductReviewUsecase) AdminDeleteReview(c *fiber.Ctx) *fiber.Error {
+	obj, err := uc.rRepo.GetReview(utils.StringToUint(c.Params("review_id")))
+	if err != nil {
+		return err
+	}
+
+	if err := uc.rRepo.Delete(obj); err != nil {
+		return err
+	}
+	removeReviewPhoto(obj.Photo)
+	uc.pRepo.ClearCatalogCache()
+
+	return nil
+}
+
+func (uc *ProductReviewUsecase) listReview(c *fiber.Ctx, filter models.ReviewFilter) (*response.Pagination, *fiber.Error) {
+	// 	Parse the query parameters
+	page := c.Query("page", "1")
+	limit := c.Query("per_page", "10")
+
+	// Convert the page and limit to integers
+	pageInt, _ := strconv.Atoi(page)
+	limitInt, _ := strconv.Atoi(limit)
+	pageInt, limitInt = response.ClampPage(pageInt, limitInt)
+
+	query, errQuery := models.ProductReviewQuery.Parse(c)
+	if errQuery != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, errQuery.Error())
+	}
+
+	// make param pagination struct
+	pagParam := response.ParamsPagination{
+		Page:          pageInt,
+		Limit:         limitInt,
+		SortQuery:     query.OrderBy(),
+		Filter:        query.Scope,
+		Cursor:        c.Query("cursor"),
+		CursorMode:    c.Context().QueryArgs().Has("cursor"),
+		EstimateCount: c.Query("count") == "estimate",
+		NoPage:        c.Query("no_page"),
+	}
+
+	return uc.rRepo.ListReview(filter, pagParam)
+}
+
+// product return the product visible in the tenant
+func (uc *ProductReviewUsecase) product(c *fiber.Ctx) (models.Product, models.Tenant, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Product{}, tenant, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	product, err := uc.pRepo.GetProduct(tenant, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return product, tenant, err
+	}
+
+	return product, tenant, nil
+}
+
+// ownReview return the review of the product written by the current user
+func (uc *ProductReviewUsecase) ownReview(c *fiber.Ctx) (models.ProductReview, *fiber.Error) {
+	product, tenant, err := uc.product(c)
+	if err != nil {
+		return models.ProductReview{}, err
+	}
+
+	obj, err := uc.rRepo.GetReview(utils.StringToUint(c.Params("review_id")))
+	if err != nil {
+		return obj, err
+	}
+	if obj.ProductID != product.ID {
+		return obj, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+
+	// check the author of data
+	if obj.UserID != tenant.UserID {
+		return obj, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	return obj, nil
+}
+
+// uploadReviewPhoto save the uploaded "photo" file of the multipart form, nil without file
+func uploadReviewPhoto(c *fiber.Ctx) (*string, *fiber.Error) {
+	file, err := c.FormFile("photo")
+	if err != nil {
+		return nil, nil
+	}
+
+	photo, errFile := utils.ImageUpload(file, "reviews")
+	if errFile != nil {
+		return nil, fiber.NewError(500, errFile.Error())
+	}
+
+	return &photo, nil
+}
+
+// removeReviewPhoto remove the photo file of the review
+func removeReviewPhoto(photo *string) {
+	if photo != nil {
+		utils.RemoveFileSilence(*photo, string(models.ImageFile))
+	}
+}
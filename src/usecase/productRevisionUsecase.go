@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"myapp/pkg/response"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductRevisionUsecase struct {
+	rRepo models.ProductRevisionRepository
+	pRepo models.ProductRepository
+	cRepo models.CategoryRepository
+}
+
+// NewProductRevisionUsecase will create an object that represent the models.ProductRevisionUsecase interface
+func NewProductRevisionUsecase(revision models.ProductRevisionRepository, product models.ProductRepository, category models.CategoryRepository) models.ProductRevisionUsecase {
+	return &ProductRevisionUsecase{
+		rRepo: revision,
+		pRepo: product,
+		cRepo: category,
+	}
+}
+
+// History implements models.ProductRevisionUsecase.
+func (uc *ProductRevisionUsecase) History(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
+	obj, _, err := uc.product(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.listRevision(c, obj.ID)
+}
+
+// AdminHistory implements models.ProductRevisionUsecase.
+func (uc *ProductRevisionUsecase) AdminHistory(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
+	obj, err := uc.rRepo.GetProductUnscoped(utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.listRevision(c, obj.ID)
+}
+
+// Revert implements models.ProductRevisionUsecase.
+func (uc *ProductRevisionUsecase) Revert(c *fiber.Ctx) (models.Product, *fiber.Error) {
+	obj, tenant, err := uc.product(c)
+	if err != nil {
+		return obj, err
+	}
+
+	if err := uc.revert(obj, utils.StringToUint(c.Params("revision")), tenant.UserID); err != nil {
+		return obj, err
+	}
+
+	return uc.pRepo.GetProduct(tenant, obj.ID)
+}
+
+// AdminRevert implements models.ProductRevisionUsecase.
+func (uc *ProductRevisionUsecase) AdminRevert(c *fiber.Ctx) (models.Product, *fiber.Error) {
+	user, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return models.Product{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.rRepo.GetProductUnscoped(utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return obj, err
+	}
+	if obj.DeletedAt.Valid {
+		return obj, fiber.NewError(422, "The deleted product can't be reverted.")
+	}
+
+	if err := uc.revert(obj, utils.StringToUint(c.Params("revision")), user.ID); err != nil {
+		return obj, err
+	}
+
+	return uc.rRepo.GetProductUnscoped(obj.ID)
+}
+
+// revert restore the tracked fields of the product as they were after the revision
+func (uc *ProductRevisionUsecase) revert(obj models.Product, revisionID uint, actorID uint) *fiber.Error {
+	revision, err := uc.rRepo.GetRevision(obj.ID, revisionID)
+	if err != nil {
+		return err
+	}
+
+	later, err := uc.rRepo.RevisionsAfter(obj.ID, revision.ID)
+	if err != nil {
+		return err
+	}
+	if len(later) == 0 {
+		return fiber.NewError(422, "The product is already at this revision.")
+	}
+
+	target, errRewind := obj.Snapshot().Rewind(later)
+	if errRewind != nil {
+		return fiber.NewError(500, errRewind.Error())
+	}
+
+	obj.Title = target.Title
+	obj.Description = target.Description
+	obj.Price.Amount = target.PriceAmount
+	obj.Price.Currency = target.PriceCurrency
+	obj.Status = target.Status
+	obj.IsEnable = target.Status == models.ProductPublished
+	obj.PublishAt = target.PublishAt
+	obj.UnpublishAt = target.UnpublishAt
+
+	// the category and the external ID may be used since the revision
+	categoryID := uint(0)
+	if target.CategoryID != nil {
+		categoryID = *target.CategoryID
+	}
+	if err := fillCategoryAndTags(uc.cRepo, &obj, models.ProductInput{CategoryID: categoryID, Tags: target.Tags}); err != nil {
+		return err
+	}
+
+	obj.ExternalID = nil
+	if target.ExternalID != nil {
+		if err := setExternalID(uc.pRepo, &obj, *target.ExternalID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := uc.pRepo.Revert(obj, revision.ID, actorID); err != nil {
+		return err
+	}
+
+	uc.cRepo.ClearCountProduct(obj.OrganizationID)
+	uc.pRepo.ClearCatalogCache()
+
+	return nil
+}
+
+// product return the product the user can manage in the tenant
+func (uc *ProductRevisionUsecase) product(c *fiber.Ctx) (models.Product, models.Tenant, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Product{}, tenant, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.pRepo.GetProduct(tenant, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return obj, tenant, err
+	}
+
+	// check the owner of data
+	if !tenant.CanManage(obj.UserID) {
+		return obj, tenant, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	return obj, tenant, nil
+}
+
+func (uc *ProductRevisionUsecase) listRevision(c *fiber.Ctx, productID uint) (*response.Pagination, *fiber.Error) {
+	// 	Parse the query parameters
+	page := c.Query("page", "1")
+	limit := c.Query("per_page", "10")
+
+	// Convert the page and limit to integers
+	pageInt, _ := strconv.Atoi(page)
+	limitInt, _ := strconv.Atoi(limit)
+	pageInt, limitInt = response.ClampPage(pageInt, limitInt)
+
+	query, errQuery := models.ProductRevisionQuery.Parse(c)
+	if errQuery != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, errQuery.Error())
+	}
+
+	// make param pagination struct
+	pagParam := response.ParamsPagination{
+		Page:          pageInt,
+		Limit:         limitInt,
+		SortQuery:     query.OrderBy(),
+		Filter:        query.Scope,
+		Cursor:        c.Query("cursor"),
+		CursorMode:    c.Context().QueryArgs().Has("cursor"),
+		EstimateCount: c.Query("count") == "estimate",
+		NoPage:        c.Query("no_page"),
+	}
+
+	return uc.rRepo.ListRevision(productID, pagParam)
+}
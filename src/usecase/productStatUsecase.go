@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"log"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductStatUsecase struct {
+	sRepo models.ProductStatRepository
+	pRepo models.ProductRepository
+}
+
+// NewProductStatUsecase will create an object that represent the models.ProductStatUsecase interface
+func NewProductStatUsecase(stat models.ProductStatRepository, product models.ProductRepository) models.ProductStatUsecase {
+	return &ProductStatUsecase{
+		sRepo: stat,
+		pRepo: product,
+	}
+}
+
+// Stats implements models.ProductStatUsecase.
+func (uc *ProductStatUsecase) Stats(c *fiber.Ctx) (models.ProductStats, *fiber.Error) {
+	var stats models.ProductStats
+
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return stats, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.pRepo.GetProduct(tenant, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return stats, err
+	}
+
+	// check the owner of data
+	if !tenant.CanManage(obj.UserID) {
+		return stats, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	period, err := models.ParseStatPeriod(c)
+	if err != nil {
+		return stats, err
+	}
+
+	data, err := uc.sRepo.ListStat(obj.ID, period)
+	if err != nil {
+		return stats, err
+	}
+
+	byDay := make(map[string]*models.ProductStat, len(data))
+	for _, stat := range data {
+		byDay[stat.Day.UTC().Format(time.DateOnly)] = stat
+	}
+
+	stats.ProductID = obj.ID
+	stats.From = period.From.Format(time.DateOnly)
+	stats.To = period.To.Format(time.DateOnly)
+	stats.Series = make([]*models.ProductStat, 0, period.Days())
+	for day := period.From; !day.After(period.To); day = day.AddDate(0, 0, 1) {
+		stat, ok := byDay[day.Format(time.DateOnly)]
+		if !ok {
+			stat = &models.ProductStat{ProductID: obj.ID, Day: day}
+		}
+		stats.Totals.Add(*stat)
+		stats.Series = append(stats.Series, stat)
+	}
+
+	return stats, nil
+}
+
+// TopProducts implements models.ProductStatUsecase.
+func (uc *ProductStatUsecase) TopProducts(c *fiber.Ctx) ([]*models.TopProduct, *fiber.Error) {
+	period, err := models.ParseStatPeriod(c)
+	if err != nil {
+		return nil, err
+	}
+
+	metric := models.StatMetric(c.Query("sort", string(models.StatView)))
+	switch metric {
+	case models.StatView, models.StatImpression, models.StatFavorite:
+	default:
+		return nil, fiber.NewError(fiber.StatusBadRequest, "The sort must be views, impressions or favorites.")
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 1 || limit > 100 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "The limit must be between 1 and 100.")
+	}
+
+	return uc.sRepo.TopProducts(period, metric, limit)
+}
+
+// FlushStats implements models.ProductStatUsecase.
+func (uc *ProductStatUsecase) FlushStats() error {
+	flushed, err := uc.sRepo.Flush()
+	if err != nil {
+		return err
+	}
+
+	if flushed > 0 {
+		log.Printf("📈 %d product stat(s) flushed", flushed)
+	}
+
+	return nil
+}
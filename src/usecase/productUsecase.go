@@ -1,24 +1,52 @@
 package usecase
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"myapp/pkg/configs"
+	"myapp/pkg/money"
 	"myapp/pkg/response"
 	"myapp/pkg/utils"
 	"myapp/src/models"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// the pages of the public catalog cached in redis, the next pages are rarely requested
+const catalogCachedPages = 5
+
 type ProductUsecase struct {
 	pRepo models.ProductRepository
 	uRepo models.UserRepository
+	cRepo models.CategoryRepository
+	iRepo models.ProductImageRepository
+	xRepo models.ExchangeRateRepository
+	wRepo models.WishlistRepository
+	sRepo models.ProductStatRepository
+	// the currency of the price without currency, see configs.BaseCurrency
+	currency string
+	// the lifetime of the cached catalog pages, 0 is no cache
+	catalogTTL time.Duration
 }
 
 // NewProductUsecase will create an object that represent the models.ProductUsecase interface
-func NewProductUsecase(product models.ProductRepository, user models.UserRepository) models.ProductUsecase {
+func NewProductUsecase(product models.ProductRepository, user models.UserRepository, category models.CategoryRepository, image models.ProductImageRepository, rate models.ExchangeRateRepository, wishlist models.WishlistRepository, stat models.ProductStatRepository, currency string, catalogTTL time.Duration) models.ProductUsecase {
 	return &ProductUsecase{
-		pRepo: product,
-		uRepo: user,
+		pRepo:      product,
+		uRepo:      user,
+		cRepo:      category,
+		iRepo:      image,
+		xRepo:      rate,
+		wRepo:      wishlist,
+		sRepo:      stat,
+		currency:   currency,
+		catalogTTL: catalogTTL,
 	}
 }
 
@@ -26,28 +54,31 @@ func NewProductUsecase(product models.ProductRepository, user models.UserReposit
 func (uc *ProductUsecase) Delete(c *fiber.Ctx) *fiber.Error {
 	id := utils.StringToUint(c.Params("id"))
 
-	user, errLocal := c.Locals("user").(models.User)
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
 	if !errLocal {
 		return fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
 	}
 
 	// get data
-	obj, err := uc.pRepo.GetProduct(id)
+	obj, err := uc.pRepo.GetProduct(tenant, id)
 	if err != nil {
 		return err
 	}
 
 	// check the owner of data
-	if obj.UserID != user.ID {
+	if !tenant.CanManage(obj.UserID) {
 		return fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
 	}
 
 	// deleted obj
-	err = uc.pRepo.Delete(obj)
+	err = uc.pRepo.Delete(obj, tenant.UserID)
 	if err != nil {
 		return err
 	}
 
+	uc.cRepo.ClearCountProduct(obj.OrganizationID)
+	uc.pRepo.ClearCatalogCache()
+
 	return nil
 }
 
@@ -56,183 +87,700 @@ func (uc *ProductUsecase) Update(c *fiber.Ctx, payload models.ProductInput) (mod
 	obj := models.Product{}
 	id := utils.StringToUint(c.Params("id"))
 
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return obj, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
 	// get product data
-	obj, err := uc.pRepo.GetProduct(id)
+	obj, err := uc.pRepo.GetProduct(tenant, id)
 	if err != nil {
 		return obj, err
 	}
 
-	user, errLocal := c.Locals("user").(models.User)
-	if !errLocal {
-		return obj, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
-	}
-
 	// check the owner of data
-	if obj.UserID != user.ID {
+	if !tenant.CanManage(obj.UserID) {
 		return obj, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
 	}
 
-	// MultipartForm POST
-	if form, err := c.MultipartForm(); err == nil {
-		files := form.File["image"]
-
-		// Loop through files:
-		for _, file := range files {
-			// // Save the files to disk:
-			imageUrl, errFile := utils.ImageUpload(file, "products")
-			if errFile != nil {
-				return obj, fiber.NewError(500, errFile.Error())
-			}
+	price, err := parseProductPrice(payload, obj.Price.Currency, uc.currency)
+	if err != nil {
+		return obj, err
+	}
+	if err := setExternalID(uc.pRepo, &obj, payload.ExternalID); err != nil {
+		return obj, err
+	}
 
-			// update user photo path
-			obj.Image = &imageUrl
-		}
+	// the uploaded files are added to the gallery
+	images, err := uploadProductImages(c, obj, "")
+	if err != nil {
+		return obj, err
 	}
 
 	// fill update data
 	obj.Title = payload.Title
 	obj.Description = payload.Description
-	obj.Price = payload.Price
+	obj.Price = price
+
+	if err := fillCategoryAndTags(uc.cRepo, &obj, payload); err != nil {
+		removeProductImages(images)
+		return obj, err
+	}
 
 	// do update
-	obj, err = uc.pRepo.Update(obj)
+	obj, err = uc.pRepo.Update(obj, tenant.UserID)
 	if err != nil {
+		removeProductImages(images)
 		return obj, err
 	}
 
-	return obj, nil
+	uc.cRepo.ClearCountProduct(obj.OrganizationID)
+	uc.pRepo.ClearCatalogCache()
+
+	if len(images) > 0 {
+		if _, err := uc.iRepo.AddImages(obj, images); err != nil {
+			removeProductImages(images)
+			return obj, err
+		}
+	}
+
+	return uc.pRepo.GetProduct(tenant, obj.ID)
 }
 
 // Create implements models.ProductUsecase.
 func (uc *ProductUsecase) Create(c *fiber.Ctx, payload models.ProductInput) (models.Product, *fiber.Error) {
 	var obj models.Product
 
-	user, errLocal := c.Locals("user").(models.User)
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
 	if !errLocal {
 		return obj, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
 	}
 
-	// fill the owner
-	obj.UserID = user.ID
-
-	// MultipartForm POST
-	if form, err := c.MultipartForm(); err == nil {
-		files := form.File["image"]
+	// fill the owner, the new product is a draft until it's published
+	obj.UserID = tenant.UserID
+	obj.OrganizationID = tenant.OrganizationPtr()
+	obj.Status = models.ProductDraft
 
-		// Loop through files:
-		for _, file := range files {
-			// // Save the files to disk:
-			imageUrl, errFile := utils.ImageUpload(file, "products")
-			if errFile != nil {
-				return obj, fiber.NewError(500, errFile.Error())
-			}
+	price, err := parseProductPrice(payload, uc.currency, uc.currency)
+	if err != nil {
+		return obj, err
+	}
+	if err := setExternalID(uc.pRepo, &obj, payload.ExternalID); err != nil {
+		return obj, err
+	}
 
-			// update user photo path
-			obj.Image = &imageUrl
-		}
+	// the uploaded files are added to the gallery
+	images, err := uploadProductImages(c, obj, "")
+	if err != nil {
+		return obj, err
 	}
 
 	// fill the form data
 	obj.Title = payload.Title
 	obj.Description = payload.Description
-	obj.Price = payload.Price
+	obj.Price = price
+
+	if err := fillCategoryAndTags(uc.cRepo, &obj, payload); err != nil {
+		removeProductImages(images)
+		return obj, err
+	}
 
 	// save the data
-	obj, err := uc.pRepo.Create(obj)
+	obj, err = uc.pRepo.Create(obj, tenant.UserID)
+	if err != nil {
+		removeProductImages(images)
+		return obj, err
+	}
+
+	uc.cRepo.ClearCountProduct(obj.OrganizationID)
+	uc.pRepo.ClearCatalogCache()
+
+	if len(images) > 0 {
+		if _, err := uc.iRepo.AddImages(obj, images); err != nil {
+			removeProductImages(images)
+			return obj, err
+		}
+	}
+
+	return uc.pRepo.GetProduct(tenant, obj.ID)
+}
+
+// ChangeStatus implements models.ProductUsecase.
+func (uc *ProductUsecase) ChangeStatus(c *fiber.Ctx, payload models.ProductStatusInput) (models.Product, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Product{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.pRepo.GetProduct(tenant, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return obj, err
+	}
+
+	// check the owner of data
+	if !tenant.CanManage(obj.UserID) {
+		return obj, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	now := time.Now()
+	switch payload.Status {
+	case models.ProductScheduled:
+		if payload.PublishAt == nil || !payload.PublishAt.After(now) {
+			return obj, fiber.NewError(422, "The publish time of the scheduled product must be in the future.")
+		}
+		obj.PublishAt = payload.PublishAt
+	case models.ProductPublished:
+		if payload.PublishAt != nil && payload.PublishAt.After(now) {
+			return obj, fiber.NewError(422, "Please use the scheduled status to publish the product later.")
+		}
+		// keep the first publish time when it's already published
+		if obj.Status != models.ProductPublished || obj.PublishAt == nil {
+			obj.PublishAt = &now
+		}
+	case models.ProductDraft:
+		obj.PublishAt = nil
+	}
+
+	obj.UnpublishAt = nil
+	if payload.UnpublishAt != nil {
+		if payload.Status != models.ProductScheduled && payload.Status != models.ProductPublished {
+			return obj, fiber.NewError(422, "Only the scheduled or published product can have the unpublish time.")
+		}
+		if !payload.UnpublishAt.After(now) || !payload.UnpublishAt.After(*obj.PublishAt) {
+			return obj, fiber.NewError(422, "The unpublish time must be after the publish time.")
+		}
+		obj.UnpublishAt = payload.UnpublishAt
+	}
+
+	obj.Status = payload.Status
+	obj.IsEnable = obj.Status == models.ProductPublished
+
+	obj, err = uc.pRepo.UpdateStatus(obj, tenant.UserID)
 	if err != nil {
 		return obj, err
 	}
 
+	// the category count only has the live products
+	uc.cRepo.ClearCountProduct(obj.OrganizationID)
+	uc.pRepo.ClearCatalogCache()
+
 	return obj, nil
 }
 
+// PublishScheduled implements models.ProductUsecase.
+func (uc *ProductUsecase) PublishScheduled() error {
+	now := time.Now()
+
+	published, err := uc.pRepo.PublishDue(now)
+	if err != nil {
+		return err
+	}
+
+	archived, err := uc.pRepo.ArchiveDue(now)
+	if err != nil {
+		return err
+	}
+
+	// the category count only has the live products
+	cleared := map[uint]bool{}
+	for _, obj := range append(published, archived...) {
+		var orgID uint
+		if obj.OrganizationID != nil {
+			orgID = *obj.OrganizationID
+		}
+		if !cleared[orgID] {
+			uc.cRepo.ClearCountProduct(obj.OrganizationID)
+			cleared[orgID] = true
+		}
+	}
+
+	if len(published) > 0 || len(archived) > 0 {
+		uc.pRepo.ClearCatalogCache()
+		log.Printf("📢 %d product(s) published, %d product(s) archived", len(published), len(archived))
+	}
+
+	return nil
+}
+
+// ListTrash implements models.ProductUsecase.
+func (uc *ProductUsecase) ListTrash(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	// 	Parse the query parameters
+	page := c.Query("page", "1")
+	limit := c.Query("per_page", "10")
+
+	// Convert the page and limit to integers
+	pageInt, _ := strconv.Atoi(page)
+	limitInt, _ := strconv.Atoi(limit)
+	pageInt, limitInt = response.ClampPage(pageInt, limitInt)
+
+	query, errQuery := models.ProductTrashQuery.Parse(c)
+	if errQuery != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, errQuery.Error())
+	}
+
+	// make param pagination struct
+	pagParam := response.ParamsPagination{
+		Page:          pageInt,
+		Limit:         limitInt,
+		SortQuery:     query.OrderBy(),
+		Filter:        query.Scope,
+		Cursor:        c.Query("cursor"),
+		CursorMode:    c.Context().QueryArgs().Has("cursor"),
+		EstimateCount: c.Query("count") == "estimate",
+		NoPage:        c.Query("no_page"),
+	}
+
+	return uc.pRepo.ListTrash(tenant, pagParam)
+}
+
+// Restore implements models.ProductUsecase.
+func (uc *ProductUsecase) Restore(c *fiber.Ctx) (models.Product, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Product{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.pRepo.GetTrashed(tenant, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return obj, err
+	}
+
+	// the external ID could be used by a new product since the deletion
+	if obj.ExternalID != nil {
+		other, err := uc.pRepo.FindByExternalID(obj.UserID, *obj.ExternalID)
+		if err == nil && other.ID != obj.ID {
+			return obj, fiber.NewError(409, "The external ID is already used by another product.")
+		}
+		if err != nil && err.Code != 404 {
+			return obj, err
+		}
+	}
+
+	obj, err = uc.pRepo.Restore(obj, tenant.UserID)
+	if err != nil {
+		return obj, err
+	}
+
+	uc.cRepo.ClearCountProduct(obj.OrganizationID)
+	uc.pRepo.ClearCatalogCache()
+
+	return uc.pRepo.GetProduct(tenant, obj.ID)
+}
+
+// DeletePermanently implements models.ProductUsecase.
+func (uc *ProductUsecase) DeletePermanently(c *fiber.Ctx) *fiber.Error {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.pRepo.GetTrashed(tenant, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return err
+	}
+
+	return uc.pRepo.PermanentDelete(obj)
+}
+
+// PurgeTrash implements models.ProductUsecase.
+func (uc *ProductUsecase) PurgeTrash() error {
+	config, _ := configs.LoadConfig(".")
+	before := time.Now().Add(-config.TrashRetention())
+
+	purged := 0
+	for {
+		data, err := uc.pRepo.ListTrashDue(before, 100)
+		if err != nil {
+			return err
+		}
+
+		done := 0
+		for _, obj := range data {
+			if err := uc.pRepo.PermanentDelete(*obj); err != nil {
+				log.Printf("Purge product %d error: %s", obj.ID, err.Message)
+				continue
+			}
+			done++
+		}
+		purged += done
+
+		// the failed products are retried on the next run
+		if len(data) < 100 || done == 0 {
+			break
+		}
+	}
+
+	if purged > 0 {
+		log.Printf("🗑️ %d product(s) purged from the trash", purged)
+	}
+
+	return nil
+}
+
 // GetProduct implements models.ProductUsecase.
 func (uc *ProductUsecase) GetProduct(c *fiber.Ctx) (models.Product, *fiber.Error) {
 	id := utils.StringToUint(c.Params("id"))
 
-	obj, err := uc.pRepo.GetProduct(id)
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Product{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.pRepo.GetProduct(tenant, id)
 	if err != nil {
 		return obj, err
 	}
 
+	if err := uc.fillFavorites(tenant.UserID, []*models.Product{&obj}); err != nil {
+		return obj, err
+	}
+
+	uc.trackView(tenant, obj)
+
 	return obj, nil
 }
 
+// PublicProduct implements models.ProductUsecase.
+func (uc *ProductUsecase) PublicProduct(c *fiber.Ctx) (models.PublicProduct, time.Time, *fiber.Error) {
+	// the empty tenant see the live products outside of any organization
+	tenant := models.Tenant{}
+	// the rating and the favorite count change without the product, they change the catalog version,
+	// read before the product like the catalog page
+	version := uc.pRepo.CatalogModifiedAt()
+
+	obj, err := uc.pRepo.GetProduct(tenant, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return models.PublicProduct{}, time.Time{}, err
+	}
+
+	if err := uc.fillFavorites(tenant.UserID, []*models.Product{&obj}); err != nil {
+		return models.PublicProduct{}, time.Time{}, err
+	}
+
+	uc.trackView(tenant, obj)
+
+	modified := obj.LastModified()
+	if version.After(modified) {
+		modified = version
+	}
+	return obj.Public(), modified, nil
+}
+
+// GetBySlug implements models.ProductUsecase.
+func (uc *ProductUsecase) GetBySlug(c *fiber.Ctx) (models.Product, string, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Product{}, "", fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, redirect, err := uc.bySlug(tenant, c.Params("slug"))
+	if err != nil || redirect != "" {
+		return obj, redirect, err
+	}
+
+	if err := uc.fillFavorites(tenant.UserID, []*models.Product{&obj}); err != nil {
+		return obj, "", err
+	}
+
+	uc.trackView(tenant, obj)
+
+	return obj, "", nil
+}
+
+// trackView count the view of the product detail, the owner opening the product isn't a view
+func (uc *ProductUsecase) trackView(tenant models.Tenant, obj models.Product) {
+	if obj.UserID != tenant.UserID {
+		uc.sRepo.Track(models.StatView, obj.ID)
+	}
+}
+
+// PublicBySlug implements models.ProductUsecase.
+func (uc *ProductUsecase) PublicBySlug(slug string) (models.Product, string, *fiber.Error) {
+	// the empty tenant see the live products outside of any organization
+	return uc.bySlug(models.Tenant{}, slug)
+}
+
+// bySlug return the product of the slug, or the current slug when the slug is a previous one
+func (uc *ProductUsecase) bySlug(tenant models.Tenant, slug string) (models.Product, string, *fiber.Error) {
+	obj, err := uc.pRepo.GetProductBySlug(tenant, slug)
+	if err == nil || err.Code != 404 {
+		return obj, "", err
+	}
+
+	current, errRedirect := uc.pRepo.FindSlugRedirect(slug)
+	if errRedirect != nil {
+		if errRedirect.Code == 404 {
+			return obj, "", err
+		}
+		return obj, "", errRedirect
+	}
+
+	return obj, current, nil
+}
+
 // MyProduct implements models.ProductUsecase.
 func (uc *ProductUsecase) MyProduct(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
-	user, errLocal := c.Locals("user").(models.User)
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
 	if !errLocal {
 		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
 	}
 
 	// 	Parse the query parameters
-	search := c.Query("search")
-	sortBy := c.Query("sort", "id|desc")
+	search := strings.TrimSpace(c.Query("search"))
+	// the best match first when searching, the rank can't be used by the cursor pagination
+	spec := models.ProductQuery
+	if search != "" && !c.Context().QueryArgs().Has("cursor") {
+		spec.DefaultSort = "-rank"
+	}
 	page := c.Query("page", "1")
 	limit := c.Query("per_page", "10")
 
 	// Convert the page and limit to integers
 	pageInt, _ := strconv.Atoi(page)
 	limitInt, _ := strconv.Atoi(limit)
+	pageInt, limitInt = response.ClampPage(pageInt, limitInt)
 
-	sortQuery, errSort := utils.ValidateAndReturnSortQuery(sortBy)
-	// log.Print(sortQuery)
-	if errSort != nil {
-		errD := fiber.NewError(fiber.StatusInternalServerError, errSort.Error())
-		return nil, errD
+	query, errQuery := spec.Parse(c)
+	if errQuery != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, errQuery.Error())
 	}
 
 	// make param pagination struct
 	pagParam := response.ParamsPagination{
-		Page:      pageInt,
-		Limit:     limitInt,
-		SortQuery: sortQuery,
-		Search:    search,
-		NoPage:    c.Query("no_page"),
+		Page:          pageInt,
+		Limit:         limitInt,
+		SortQuery:     query.OrderBy(),
+		Filter:        query.Scope,
+		Cursor:        c.Query("cursor"),
+		CursorMode:    c.Context().QueryArgs().Has("cursor"),
+		EstimateCount: c.Query("count") == "estimate",
+		Search:        search,
+		NoPage:        c.Query("no_page"),
 	}
 
-	pagination, err := uc.pRepo.MyProduct(user, pagParam)
+	pagination, err := uc.pRepo.MyProduct(tenant, pagParam)
 	if err != nil {
 		return nil, err
 	}
+
+	products, _ := pagination.Data.([]*models.Product)
+	if err := uc.fillFavorites(tenant.UserID, products); err != nil {
+		return nil, err
+	}
 	return pagination, nil
 }
 
 // ListProduct implements models.ProductUsecase.
 func (uc *ProductUsecase) ListProduct(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	return uc.listCatalog(c, tenant)
+}
+
+// PublicListProduct implements models.ProductUsecase.
+func (uc *ProductUsecase) PublicListProduct(c *fiber.Ctx) (models.CatalogPage, *fiber.Error) {
+	// read the version before the products, a write during the query expire the page
+	page := models.CatalogPage{ModifiedAt: uc.pRepo.CatalogModifiedAt()}
+
+	key, cacheable := catalogCacheKey(c)
+	cacheable = cacheable && uc.catalogTTL > 0
+	if cacheable {
+		if cached, ok := uc.pRepo.GetCatalogPage(page.ModifiedAt, key); ok {
+			uc.sRepo.Track(models.StatImpression, cached.ProductIDs...)
+			return cached, nil
+		}
+	}
+
+	// the empty tenant see the live products outside of any organization
+	pagination, err := uc.listCatalog(c, models.Tenant{})
+	if err != nil {
+		return page, err
+	}
+
+	products, _ := pagination.Data.([]*models.Product)
+	data := make([]models.PublicProduct, 0, len(products))
+	page.ProductIDs = make([]uint, 0, len(products))
+	for _, obj := range products {
+		data = append(data, obj.Public())
+		page.ProductIDs = append(page.ProductIDs, obj.ID)
+	}
+	pagination.Data = data
+
+	body, errJSON := json.Marshal(pagination)
+	if errJSON != nil {
+		return page, fiber.NewError(500, errJSON.Error())
+	}
+	page.Body = body
+
+	if cacheable {
+		uc.pRepo.SetCatalogPage(key, page, uc.catalogTTL)
+	}
+	return page, nil
+}
+
+// catalogCacheKey is the hash of the sorted query of the page,
+// only the first pages without search are cached, the cursor and the search are spread over too many keys
+func catalogCacheKey(c *fiber.Ctx) (string, bool) {
+	query, err := url.ParseQuery(string(c.Context().QueryArgs().QueryString()))
+	if err != nil || query.Has("cursor") || strings.TrimSpace(query.Get("search")) != "" {
+		return "", false
+	}
+
+	// the page is keyed by the bounds the listing use, so the out of range values share one key
+	pageInt, _ := strconv.Atoi(c.Query("page", "1"))
+	limitInt, _ := strconv.Atoi(c.Query("per_page", "10"))
+	pageInt, limitInt = response.ClampPage(pageInt, limitInt)
+	if pageInt > catalogCachedPages {
+		return "", false
+	}
+	query.Set("page", strconv.Itoa(pageInt))
+	query.Set("per_page", strconv.Itoa(limitInt))
+
+	sum := sha1.Sum([]byte(query.Encode()))
+	return hex.EncodeToString(sum[:]), true
+}
+
+// listCatalog list the live products of the catalog visible in the tenant, each product is an impression
+func (uc *ProductUsecase) listCatalog(c *fiber.Ctx, tenant models.Tenant) (*response.Pagination, *fiber.Error) {
 	// 	Parse the query parameters
-	search := c.Query("search")
-	sortBy := c.Query("sort", "id|desc")
+	search := strings.TrimSpace(c.Query("search"))
+	// the best match first when searching, the rank can't be used by the cursor pagination
+	spec := models.ProductQuery
+	if search != "" && !c.Context().QueryArgs().Has("cursor") {
+		spec.DefaultSort = "-rank"
+	}
 	page := c.Query("page", "1")
 	limit := c.Query("per_page", "10")
 
 	// Convert the page and limit to integers
 	pageInt, _ := strconv.Atoi(page)
 	limitInt, _ := strconv.Atoi(limit)
+	pageInt, limitInt = response.ClampPage(pageInt, limitInt)
 
-	sortQuery, errSort := utils.ValidateAndReturnSortQuery(sortBy)
-	// log.Print(sortQuery)
-	if errSort != nil {
-		errD := fiber.NewError(fiber.StatusInternalServerError, errSort.Error())
-		return nil, errD
+	query, errQuery := spec.Parse(c)
+	if errQuery != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, errQuery.Error())
 	}
 
 	// make param pagination struct
 	pagParam := response.ParamsPagination{
-		Page:      pageInt,
-		Limit:     limitInt,
-		SortQuery: sortQuery,
-		Search:    search,
-		NoPage:    c.Query("no_page"),
+		Page:          pageInt,
+		Limit:         limitInt,
+		SortQuery:     query.OrderBy(),
+		Filter:        query.Scope,
+		Cursor:        c.Query("cursor"),
+		CursorMode:    c.Context().QueryArgs().Has("cursor"),
+		EstimateCount: c.Query("count") == "estimate",
+		Search:        search,
+		NoPage:        c.Query("no_page"),
 	}
 
-	pagination, err := uc.pRepo.ListProduct(pagParam)
+	// filter by category tree and tags
+	var filter models.ProductFilter
+	if categoryID := utils.StringToUint(c.Query("category_id")); categoryID != 0 {
+		ids, err := uc.cRepo.DescendantIDs(categoryID)
+		if err != nil {
+			return nil, err
+		}
+		// an unknown category match nothing
+		filter.CategoryIDs = append([]uint{}, ids...)
+	}
+	filter.Tags = models.NormalizeTags([]string{c.Query("tags")})
+
+	// the prices can be converted to the currency of the client
+	currency := strings.ToUpper(c.Query("currency"))
+	var rates models.Rates
+	if currency != "" {
+		if !money.IsCurrency(currency) {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Unknown currency "+currency+".")
+		}
+		data, err := uc.xRepo.ListRate()
+		if err != nil {
+			return nil, err
+		}
+		rates = models.NewRates(uc.currency, data)
+		if rates[currency] == nil {
+			return nil, fiber.NewError(422, "There is no exchange rate of "+currency+".")
+		}
+	}
+
+	pagination, err := uc.pRepo.ListProduct(tenant, pagParam, filter)
 	if err != nil {
 		return nil, err
 	}
+
+	products, _ := pagination.Data.([]*models.Product)
+	if err := uc.fillFavorites(tenant.UserID, products); err != nil {
+		return nil, err
+	}
+
+	// each product of the page is an impression
+	ids := make([]uint, 0, len(products))
+	for _, obj := range products {
+		ids = append(ids, obj.ID)
+	}
+	uc.sRepo.Track(models.StatImpression, ids...)
+
+	if rates != nil {
+		for _, obj := range products {
+			// the price without rate keep converted_price empty
+			if price, errConvert := rates.Convert(obj.Price, currency); errConvert == nil {
+				obj.ConvertedPrice = &price
+			}
+		}
+	}
 	return pagination, nil
 }
 
+// fillFavorites fill the favorite flag and count of the products with one query
+func (uc *ProductUsecase) fillFavorites(userID uint, products []*models.Product) *fiber.Error {
+	ids := make([]uint, 0, len(products))
+	for _, obj := range products {
+		ids = append(ids, obj.ID)
+	}
+
+	stats, err := uc.wRepo.FavoriteStats(userID, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range products {
+		stat := stats[obj.ID]
+		obj.IsFavorited = stat.Favorited
+		obj.FavoriteCount = stat.Total
+	}
+	return nil
+}
+
+// parseProductPrice read the decimal price in the currency of the payload, or the fallback currency,
+// or the default currency
+func parseProductPrice(payload models.ProductInput, fallback string, defaultCurrency string) (money.Money, *fiber.Error) {
+	currency := payload.Currency
+	if currency == "" {
+		currency = fallback
+	}
+	if currency == "" {
+		currency = defaultCurrency
+	}
+
+	price, errPrice := payload.Price.Parse(currency)
+	if errPrice != nil {
+		return price, fiber.NewError(422, errPrice.Error())
+	}
+	if price.Amount < 0 {
+		return price, fiber.NewError(422, "The price can't be negative.")
+	}
+	return price, nil
+}
+
 // PopulateProducts implements models.ProductUsecase.
 func (uc *ProductUsecase) PopulateProducts(userID uint, n int) *fiber.Error {
 	// find user
@@ -245,5 +793,49 @@ func (uc *ProductUsecase) PopulateProducts(userID uint, n int) *fiber.Error {
 	if err != nil {
 		return err
 	}
+
+	uc.cRepo.ClearCountProduct(nil)
+	uc.pRepo.ClearCatalogCache()
+
+	return nil
+}
+
+// fillCategoryAndTags set the category and the tags of product from the payload
+func fillCategoryAndTags(cRepo models.CategoryRepository, obj *models.Product, payload models.ProductInput) *fiber.Error {
+	obj.CategoryID = nil
+	obj.Category = nil
+	if payload.CategoryID != 0 {
+		category, err := cRepo.Get(payload.CategoryID)
+		if err != nil {
+			return fiber.NewError(422, "Category doesn't exists.")
+		}
+		obj.CategoryID = &category.ID
+	}
+
+	tags, err := cRepo.FirstOrCreateTag(models.NormalizeTags(payload.Tags))
+	if err != nil {
+		return err
+	}
+	obj.Tags = tags
+
+	return nil
+}
+
+// setExternalID set the seller reference of the product, it must be unique among the products of the seller
+func setExternalID(pRepo models.ProductRepository, obj *models.Product, externalID string) *fiber.Error {
+	externalID = strings.TrimSpace(externalID)
+	if externalID == "" || (obj.ExternalID != nil && *obj.ExternalID == externalID) {
+		return nil
+	}
+
+	other, err := pRepo.FindByExternalID(obj.UserID, externalID)
+	if err == nil && other.ID != obj.ID {
+		return fiber.NewError(409, "The external ID is already used by another product.")
+	}
+	if err != nil && err.Code != 404 {
+		return err
+	}
+
+	obj.ExternalID = &externalID
 	return nil
 }
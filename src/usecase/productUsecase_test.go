@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"myapp/src/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// catalogProductRepository hold the product and the version of the catalog
+type catalogProductRepository struct {
+	models.ProductRepository
+	product models.Product
+	version time.Time
+}
+
+func (r *catalogProductRepository) GetProduct(tenant models.Tenant, id uint) (models.Product, *fiber.Error) {
+	return r.product, nil
+}
+
+func (r *catalogProductRepository) CatalogModifiedAt() time.Time {
+	return r.version
+}
+
+type favoriteRepository struct {
+	models.WishlistRepository
+}
+
+func (favoriteRepository) FavoriteStats(userID uint, productIDs []uint) (map[uint]models.FavoriteStat, *fiber.Error) {
+	return map[uint]models.FavoriteStat{}, nil
+}
+
+type noStatRepository struct {
+	models.ProductStatRepository
+}
+
+func (noStatRepository) Track(metric models.StatMetric, productIDs ...uint) {}
+
+// TestPublicProductModified check the rating and the favorites changed after the product are in the Last-Modified
+func TestPublicProductModified(t *testing.T) {
+	updated := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	product := models.Product{Title: "Shoe", Status: models.ProductPublished}
+	product.ID = 1
+	product.UpdatedAt = updated
+	variant := &models.ProductVariant{}
+	variant.UpdatedAt = updated.Add(time.Hour)
+	product.Variants = []*models.ProductVariant{variant}
+
+	tests := []struct {
+		version, want time.Time
+	}{
+		// the last change is the variant
+		{updated, updated.Add(time.Hour)},
+		// a review or a favorite after the product
+		{updated.Add(2 * time.Hour), updated.Add(2 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		repo := &catalogProductRepository{product: product, version: tt.version}
+		uc := NewProductUsecase(repo, nil, nil, nil, nil, favoriteRepository{}, noStatRepository{}, "USD", 0)
+
+		var modified time.Time
+		app := fiber.New()
+		app.Get("/catalog/products/:id", func(c *fiber.Ctx) error {
+			_, modified, _ = uc.PublicProduct(c)
+			return nil
+		})
+		if _, err := app.Test(httptest.NewRequest(http.MethodGet, "/catalog/products/1", nil)); err != nil {
+			t.Fatal(err)
+		}
+
+		if !modified.Equal(tt.want) {
+			t.Errorf("version %s: got %s, want %s", tt.version, modified, tt.want)
+		}
+	}
+}
+
+func TestCatalogCacheKey(t *testing.T) {
+	app := fiber.New()
+	key := func(query string) (string, bool) {
+		c := app.AcquireCtx(&fasthttp.RequestCtx{})
+		defer app.ReleaseCtx(c)
+		c.Request().SetRequestURI("/api/v1/catalog/products?" + query)
+		return catalogCacheKey(c)
+	}
+
+	first, ok := key("")
+	if !ok {
+		t.Fatal("the first page should be cached")
+	}
+
+	// the out of range values share the key of their bounds
+	same := map[string]string{
+		"page=1&per_page=10":      first,
+		"per_page=-1":             first,
+		"page=0&per_page=abc":     first,
+		"per_page=10&sort=-price": "",
+	}
+	limit, _ := key("per_page=100")
+	same["per_page=1000000"] = limit
+	same["per_page=101"] = limit
+
+	for query, want := range same {
+		got, ok := key(query)
+		if !ok {
+			t.Errorf("%q should be cached", query)
+			continue
+		}
+		if want != "" && got != want {
+			t.Errorf("%q has its own key", query)
+		}
+		if want == "" && got == first {
+			t.Errorf("%q share the key of the first page", query)
+		}
+	}
+
+	for _, query := range []string{"page=99", "cursor=", "search=shoe"} {
+		if _, ok := key(query); ok {
+			t.Errorf("%q shouldn't be cached", query)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"myapp/pkg/money"
+	"myapp/pkg/response"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductVariantUsecase struct {
+	vRepo models.ProductVariantRepository
+	pRepo models.ProductRepository
+}
+
+// NewProductVariantUsecase will create an object that represent the models.ProductVariantUsecase interface
+func NewProductVariantUsecase(variant models.ProductVariantRepository, product models.ProductRepository) models.ProductVariantUsecase {
+	return &ProductVariantUsecase{
+		vRepo: variant,
+		pRepo: product,
+	}
+}
+
+// ListVariant implements models.ProductVariantUsecase.
+func (uc *ProductVariantUsecase) ListVariant(c *fiber.Ctx) ([]*models.ProductVariant, *fiber.Error) {
+	product, _, err := uc.product(c, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.vRepo.ListVariant(product.ID)
+}
+
+// GetVariant implements models.ProductVariantUsecase.
+func (uc *ProductVariantUsecase) GetVariant(c *fiber.Ctx) (models.ProductVariant, *fiber.Error) {
+	product, _, err := uc.product(c, false)
+	if err != nil {
+		return models.ProductVariant{}, err
+	}
+
+	return uc.vRepo.GetVariant(product.ID, utils.StringToUint(c.Params("variant_id")))
+}
+
+// Create implements models.ProductVariantUsecase.
+func (uc *ProductVariantUsecase) Create(c *fiber.Ctx, payload models.ProductVariantInput) (models.ProductVariant, *fiber.Error) {
+	var obj models.ProductVariant
+
+	product, tenant, err := uc.product(c, true)
+	if err != nil {
+		return obj, err
+	}
+
+	payload.Sanitize()
+
+	price, err := variantPrice(product, payload.Price)
+	if err != nil {
+		return obj, err
+	}
+
+	// fill the form data
+	obj.SKU = payload.SKU
+	obj.Options = payload.Options
+	obj.PriceAmount = price
+	obj.Stock = payload.Stock
+	obj.LowStockThreshold = payload.LowStockThreshold
+	obj.IsEnable = payload.IsEnable == nil || *payload.IsEnable
+	obj.ProductID = product.ID
+
+	return uc.vRepo.Create(obj, tenant.UserID)
+}
+
+// Update implements models.ProductVariantUsecase.
+func (uc *ProductVariantUsecase) Update(c *fiber.Ctx, payload models.ProductVariantInput) (models.ProductVariant, *fiber.Error) {
+	product, _, err := uc.product(c, true)
+	if err != nil {
+		return models.ProductVariant{}, err
+	}
+
+	obj, err := uc.vRepo.GetVariant(product.ID, utils.StringToUint(c.Params("variant_id")))
+	if err != nil {
+		return obj, err
+	}
+
+	payload.Sanitize()
+
+	price, err := variantPrice(product, payload.Price)
+	if err != nil {
+		return obj, err
+	}
+
+	// fill update data, the stock is changed by stock adjustment
+	obj.SKU = payload.SKU
+	obj.Options = payload.Options
+	obj.PriceAmount = price
+	obj.LowStockThreshold = payload.LowStockThreshold
+	if payload.IsEnable != nil {
+		obj.IsEnable = *payload.IsEnable
+	}
+
+	return uc.vRepo.Update(obj)
+}
+
+// Delete implements models.ProductVariantUsecase.
+func (uc *ProductVariantUsecase) Delete(c *fiber.Ctx) *fiber.Error {
+	product, _, err := uc.product(c, true)
+	if err != nil {
+		return err
+	}
+
+	obj, err := uc.vRepo.GetVariant(product.ID, utils.StringToUint(c.Params("variant_id")))
+	if err != nil {
+		return err
+	}
+
+	return uc.vRepo.Delete(obj)
+}
+
+// AdjustStock implements models.ProductVariantUsecase.
+func (uc *ProductVariantUsecase) AdjustStock(c *fiber.Ctx, payload models.StockAdjustmentInput) (models.StockMovement, *fiber.Error) {
+	movement := models.StockMovement{
+		Change: payload.Change,
+		Reason: models.StockReason(payload.Reason),
+		Note:   payload.Note,
+	}
+
+	product, tenant, err := uc.product(c, true)
+	if err != nil {
+		return movement, err
+	}
+
+	obj, err := uc.vRepo.GetVariant(product.ID, utils.StringToUint(c.Params("variant_id")))
+	if err != nil {
+		return movement, err
+	}
+
+	movement.UserID = &tenant.UserID
+
+	obj, movement, err = uc.vRepo.AdjustStock(obj, movement)
+	if err != nil {
+		return movement, err
+	}
+
+	// notify the owner once, when the stock cross the threshold
+	if obj.CrossedLowStock(movement.Change) {
+		uc.vRepo.SendLowStockEmail(product.User, product, obj)
+	}
+
+	return movement, nil
+}
+
+// ListStockMovement implements models.ProductVariantUsecase.
+func (uc *ProductVariantUsecase) ListStockMovement(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
+	product, _, err := uc.product(c, true)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := uc.vRepo.GetVariant(product.ID, utils.StringToUint(c.Params("variant_id")))
+	if err != nil {
+		return nil, err
+	}
+
+	// 	Parse the query parameters
+	page := c.Query("page", "1")
+	limit := c.Query("per_page", "10")
+
+	// Convert the page and limit to integers
+	pageInt, _ := strconv.Atoi(page)
+	limitInt, _ := strconv.Atoi(limit)
+	pageInt, limitInt = response.ClampPage(pageInt, limitInt)
+
+	query, errQuery := models.StockMovementQuery.Parse(c)
+	if errQuery != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, errQuery.Error())
+	}
+
+	// make param pagination struct
+	pagParam := response.ParamsPagination{
+		Page:          pageInt,
+		Limit:         limitInt,
+		SortQuery:     query.OrderBy(),
+		Filter:        query.Scope,
+		Cursor:        c.Query("cursor"),
+		CursorMode:    c.Context().QueryArgs().Has("cursor"),
+		EstimateCount: c.Query("count") == "estimate",
+		NoPage:        c.Query("no_page"),
+	}
+
+	return uc.vRepo.ListStockMovement(obj.ID, pagParam)
+}
+
+// product return the parent product visible in the tenant,
+// manage check the current user is allowed to change it
+func (uc *ProductVariantUsecase) product(c *fiber.Ctx, manage bool) (models.Product, models.Tenant, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Product{}, tenant, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	product, err := uc.pRepo.GetProduct(tenant, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return product, tenant, err
+	}
+
+	// check the owner of data
+	if manage && !tenant.CanManage(product.UserID) {
+		return product, tenant, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	return product, tenant, nil
+}
+
+// variantPrice read the price override in the currency of the product, nil use the price of product
+func variantPrice(product models.Product, value *money.Decimal) (*int64, *fiber.Error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+
+	price, err := value.Parse(product.Price.Currency)
+	if err != nil {
+		return nil, fiber.NewError(422, err.Error())
+	}
+	if price.Amount < 0 {
+		return nil, fiber.NewError(422, "The price can't be negative.")
+	}
+	return &price.Amount, nil
+}
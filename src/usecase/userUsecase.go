@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"log"
+	"myapp/pkg/configs"
 	"myapp/pkg/response"
 	"myapp/pkg/utils"
 	"myapp/src/models"
@@ -9,16 +11,19 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/thanhpk/randstr"
 )
 
 type UserUsecase struct {
 	userRepo models.UserRepository
+	invRepo  models.InvitationRepository
 }
 
 // NewUserUsecase will create an object that represent the models.UserUsecase interface
-func NewUserUsecase(userRepo models.UserRepository) models.UserUsecase {
+func NewUserUsecase(userRepo models.UserRepository, invRepo models.InvitationRepository) models.UserUsecase {
 	return &UserUsecase{
 		userRepo: userRepo,
+		invRepo:  invRepo,
 	}
 }
 
@@ -26,28 +31,30 @@ func NewUserUsecase(userRepo models.UserRepository) models.UserUsecase {
 func (uc *UserUsecase) ListUser(c *fiber.Ctx) (*response.Pagination, []*models.User, *fiber.Error) {
 	// 	Parse the query parameters
 	search := c.Query("search")
-	sortBy := c.Query("sort", "id|desc")
 	page := c.Query("page", "1")
 	limit := c.Query("per_page", "10")
 
 	// Convert the page and limit to integers
 	pageInt, _ := strconv.Atoi(page)
 	limitInt, _ := strconv.Atoi(limit)
+	pageInt, limitInt = response.ClampPage(pageInt, limitInt)
 
-	sortQuery, errSort := utils.ValidateAndReturnSortQuery(sortBy)
-	// log.Print(sortQuery)
-	if errSort != nil {
-		errD := fiber.NewError(fiber.StatusInternalServerError, errSort.Error())
-		return nil, nil, errD
+	query, errQuery := models.UserQuery.Parse(c)
+	if errQuery != nil {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, errQuery.Error())
 	}
 
 	// make param pagination struct
 	pagParam := response.ParamsPagination{
-		Page:      pageInt,
-		Limit:     limitInt,
-		SortQuery: sortQuery,
-		Search:    search,
-		NoPage:    c.Query("no_page"),
+		Page:          pageInt,
+		Limit:         limitInt,
+		SortQuery:     query.OrderBy(),
+		Filter:        query.Scope,
+		Cursor:        c.Query("cursor"),
+		CursorMode:    c.Context().QueryArgs().Has("cursor"),
+		EstimateCount: c.Query("count") == "estimate",
+		Search:        search,
+		NoPage:        c.Query("no_page"),
 	}
 
 	pagination, data, err := uc.userRepo.ListUser(pagParam)
@@ -57,10 +64,198 @@ func (uc *UserUsecase) ListUser(c *fiber.Ctx) (*response.Pagination, []*models.U
 	return pagination, data, nil
 }
 
+// GetUser implements models.UserUsecase.
+func (uc *UserUsecase) GetUser(c *fiber.Ctx, id uint) (models.User, *fiber.Error) {
+	return uc.userRepo.FindUserById(id)
+}
+
+// CreateUser implements models.UserUsecase.
+func (uc *UserUsecase) CreateUser(c *fiber.Ctx, payload models.AdminCreateUserInput) (models.User, *fiber.Error) {
+	// cek email of user
+	if err := uc.userRepo.EmailExists(payload.Email); err != nil {
+		return models.User{}, err
+	}
+
+	// cek username of user
+	if err := uc.userRepo.UsernameExists(payload.Username); err != nil {
+		return models.User{}, err
+	}
+
+	user := models.User{
+		Username:  payload.Username,
+		Password:  payload.Password,
+		Email:     payload.Email,
+		FirstName: payload.FirstName,
+		LastName:  payload.LastName,
+		IsStaff:   payload.IsStaff,
+		UserProfile: models.UserProfile{
+			Phone:    payload.Phone,
+			StatusID: models.StatusPending,
+		},
+	}
+
+	if payload.Verified {
+		now := time.Now()
+		user.Verified = true
+		user.VerifiedAt = &now
+		user.UserProfile.StatusID = models.StatusActive
+	}
+	if payload.StatusID != 0 {
+		user.UserProfile.StatusID = payload.StatusID
+	}
+
+	if err := uc.userRepo.Create(user); err != nil {
+		return user, err
+	}
+
+	user, err := uc.userRepo.FindUserByEmail(user.Email)
+	if err != nil {
+		return user, err
+	}
+
+	// send verification email for unverified user
+	if !user.Verified {
+		uc.userRepo.ResendVerificationCode(user)
+	}
+
+	return uc.userRepo.FindUserById(user.ID)
+}
+
+// UpdateUser implements models.UserUsecase.
+func (uc *UserUsecase) UpdateUser(c *fiber.Ctx, id uint, payload models.AdminUpdateUserInput) (models.User, *fiber.Error) {
+	user, err := uc.adminTarget(c, id)
+	if err != nil {
+		return user, err
+	}
+
+	// the new email and username must not be registered
+	if payload.Email != user.Email {
+		if err := uc.userRepo.EmailExists(payload.Email); err != nil {
+			return user, err
+		}
+	}
+	if payload.Username != user.Username {
+		if err := uc.userRepo.UsernameExists(payload.Username); err != nil {
+			return user, err
+		}
+	}
+
+	if payload.Birthday != "" {
+		dateBirthday, errFormat := time.Parse(time.DateOnly, payload.Birthday)
+		if errFormat != nil {
+			return user, fiber.NewError(422, errFormat.Error())
+		}
+		user.UserProfile.Birthday = &dateBirthday
+	}
+
+	// fill user updates
+	user.Username = payload.Username
+	user.Email = payload.Email
+	user.FirstName = payload.FirstName
+	user.LastName = payload.LastName
+	user.UserProfile.Phone = payload.Phone
+
+	return uc.userRepo.Update(user)
+}
+
+// UpdateUserStatus implements models.UserUsecase.
+func (uc *UserUsecase) UpdateUserStatus(c *fiber.Ctx, id uint, statusID uint) (models.User, *fiber.Error) {
+	user, err := uc.adminTarget(c, id)
+	if err != nil {
+		return user, err
+	}
+
+	user.UserProfile.StatusID = statusID
+	user.UserProfile.Status = models.Status{}
+	if _, err := uc.userRepo.Update(user); err != nil {
+		return user, err
+	}
+
+	return uc.userRepo.FindUserById(id)
+}
+
+// UpdateUserStaff implements models.UserUsecase.
+func (uc *UserUsecase) UpdateUserStaff(c *fiber.Ctx, id uint, isStaff bool) (models.User, *fiber.Error) {
+	user, err := uc.adminTarget(c, id)
+	if err != nil {
+		return user, err
+	}
+
+	user.IsStaff = isStaff
+	return uc.userRepo.Update(user)
+}
+
+// VerifyUser implements models.UserUsecase.
+func (uc *UserUsecase) VerifyUser(c *fiber.Ctx, id uint) (models.User, *fiber.Error) {
+	user, err := uc.adminTarget(c, id)
+	if err != nil {
+		return user, err
+	}
+
+	if user.Verified {
+		return user, fiber.NewError(422, "User already verified.")
+	}
+
+	now := time.Now()
+	user.VerificationCode = ""
+	user.Verified = true
+	user.VerifiedAt = &now
+	if user.UserProfile.StatusID == models.StatusPending {
+		user.UserProfile.StatusID = models.StatusActive
+		user.UserProfile.Status = models.Status{}
+	}
+
+	if _, err := uc.userRepo.Update(user); err != nil {
+		return user, err
+	}
+
+	return uc.userRepo.FindUserById(id)
+}
+
+// ResetUserPassword implements models.UserUsecase.
+func (uc *UserUsecase) ResetUserPassword(c *fiber.Ctx, id uint) *fiber.Error {
+	user, err := uc.adminTarget(c, id)
+	if err != nil {
+		return err
+	}
+
+	message := "An administrator requested a password reset for your account. Here, your OTP code for reset your password:"
+	// do request OTP, same flow as forgot password
+	if err := uc.userRepo.RequestOTPEmail(user, message); err != nil {
+		return err
+	}
+	return nil
+}
+
+// adminTarget get the user to be managed, admin can't manage their own account and
+// only a superuser can manage another superuser
+func (uc *UserUsecase) adminTarget(c *fiber.Ctx, id uint) (models.User, *fiber.Error) {
+	admin, errLocal := c.Locals("user").(models.User)
+	if !errLocal {
+		return models.User{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	if admin.ID == id {
+		return models.User{}, fiber.NewError(403, "You are not allowed to manage your own account.")
+	}
+
+	user, err := uc.userRepo.FindUserById(id)
+	if err != nil {
+		return user, err
+	}
+
+	// only superuser can manage another superuser
+	if user.IsSuperuser && !admin.IsSuperuser {
+		return user, fiber.NewError(403, utils.ERR_FORBIDDEN_UPDATE)
+	}
+
+	return user, nil
+}
+
 // PermanentDeleteUser implements models.UserUsecase.
 func (uc *UserUsecase) PermanentDeleteUser(c *fiber.Ctx, id uint) *fiber.Error {
 	// get user data
-	user, err := uc.userRepo.FindUserById(id)
+	user, err := uc.adminTarget(c, id)
 	if err != nil {
 		return err
 	}
@@ -77,7 +272,7 @@ func (uc *UserUsecase) PermanentDeleteUser(c *fiber.Ctx, id uint) *fiber.Error {
 // DeleteUser implements models.UserUsecase.
 func (uc *UserUsecase) DeleteUser(c *fiber.Ctx, id uint) *fiber.Error {
 	// get user data
-	user, err := uc.userRepo.FindUserById(id)
+	user, err := uc.adminTarget(c, id)
 	if err != nil {
 		return err
 	}
@@ -110,30 +305,174 @@ func (uc *UserUsecase) RestoreUser(c *fiber.Ctx, email string) *fiber.Error {
 		return err
 	}
 
+	// cancel the pending deletion, so the account is not purged
+	if obj, err := uc.userRepo.FindPendingDeletion(user.ID); err == nil {
+		now := time.Now()
+		obj.Status = models.DeletionCancelled
+		obj.CancelledAt = &now
+		uc.userRepo.UpdateDeletion(obj)
+	}
+
 	return nil
 }
 
 // DeleteAccount implements models.UserUsecase.
-func (uc *UserUsecase) DeleteAccount(c *fiber.Ctx, otp string) *fiber.Error {
+func (uc *UserUsecase) DeleteAccount(c *fiber.Ctx, otp string) (models.AccountDeletion, *fiber.Error) {
+	var obj models.AccountDeletion
+
 	user, errLocal := c.Locals("user").(models.User)
 	if !errLocal {
-		return fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+		return obj, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
 	}
 
 	// find OTP Request
-	_, err := uc.userRepo.FindOTPRequest(otp)
+	otpR, err := uc.userRepo.FindOTPRequest(otp)
 	if err != nil {
-		return err
+		return obj, err
 	}
 
+	// OTP must be requested by the current user
+	if otpR.Email != user.Email || time.Now().After(otpR.ExpiredAt) {
+		return obj, fiber.NewError(422, "Invalid OTP code or OTP code has expired.")
+	}
+
+	config, _ := configs.LoadConfig(".")
+	graceDays := config.AccountDeletionGraceDays
+	if graceDays <= 0 {
+		graceDays = 14
+	}
+
+	// Generate cancel code
+	code := randstr.String(64)
+
+	obj.Email = user.Email
+	obj.Code = utils.Encode(code)
+	obj.Status = models.DeletionPending
+	obj.ScheduledAt = time.Now().AddDate(0, 0, graceDays)
+	obj.UserID = &user.ID
+
+	obj, err = uc.userRepo.ScheduleDeletion(obj)
+	if err != nil {
+		return obj, err
+	}
+
+	// deactivate the account during the grace period
 	err = uc.userRepo.Delete(user)
+	if err != nil {
+		return obj, err
+	}
+
+	// Send cancel link
+	uc.userRepo.SendDeletionEmail(user, obj, code)
+
+	return obj, nil
+}
+
+// CancelDeleteAccountByCode implements models.UserUsecase.
+func (uc *UserUsecase) CancelDeleteAccountByCode(ctx context.Context, code string) *fiber.Error {
+	obj, err := uc.userRepo.FindDeletionByCode(code)
+	if err != nil {
+		return err
+	}
+
+	if time.Now().After(obj.ScheduledAt) {
+		return fiber.NewError(422, "The grace period is over, this account can no longer be recovered.")
+	}
+
+	// restore the account
+	if obj.UserID != nil {
+		if err := uc.userRepo.RestoreUser(*obj.UserID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	obj.Status = models.DeletionCancelled
+	obj.CancelledAt = &now
+
+	return uc.userRepo.UpdateDeletion(obj)
+}
+
+// PurgeDeletedAccounts implements models.UserUsecase.
+func (uc *UserUsecase) PurgeDeletedAccounts() error {
+	data, err := uc.userRepo.ListDueDeletion(time.Now())
 	if err != nil {
 		return err
 	}
 
+	purged := 0
+	for _, obj := range data {
+		now := time.Now()
+
+		// the user could be removed by admin already
+		if obj.UserID != nil {
+			if user, err := uc.userRepo.FindUserUnscoped(*obj.UserID); err == nil {
+				// the account was restored, only the deleted account is purged
+				if !user.DeletedAt.Valid {
+					obj.Status = models.DeletionCancelled
+					obj.CancelledAt = &now
+					if err := uc.userRepo.UpdateDeletion(*obj); err != nil {
+						return err
+					}
+					continue
+				}
+
+				if err := uc.userRepo.PermanentDelete(user); err != nil {
+					log.Printf("Purge account %s error: %s", obj.Email, err.Message)
+					continue
+				}
+			}
+		}
+
+		obj.Status = models.DeletionPurged
+		obj.PurgedAt = &now
+		obj.UserID = nil
+		if err := uc.userRepo.UpdateDeletion(*obj); err != nil {
+			return err
+		}
+		purged++
+	}
+
+	if purged > 0 {
+		log.Printf("🗑️ %d account(s) purged", purged)
+	}
+
 	return nil
 }
 
+// ListPendingDeletion implements models.UserUsecase.
+func (uc *UserUsecase) ListPendingDeletion(c *fiber.Ctx) (*response.Pagination, *fiber.Error) {
+	// 	Parse the query parameters
+	search := c.Query("search")
+	page := c.Query("page", "1")
+	limit := c.Query("per_page", "10")
+
+	// Convert the page and limit to integers
+	pageInt, _ := strconv.Atoi(page)
+	limitInt, _ := strconv.Atoi(limit)
+	pageInt, limitInt = response.ClampPage(pageInt, limitInt)
+
+	query, errQuery := models.AccountDeletionQuery.Parse(c)
+	if errQuery != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, errQuery.Error())
+	}
+
+	// make param pagination struct
+	pagParam := response.ParamsPagination{
+		Page:      pageInt,
+		Limit:     limitInt,
+		SortQuery: query.OrderBy(),
+		Filter:    query.Scope,
+		Search:    search,
+	}
+
+	pagination, err := uc.userRepo.ListPendingDeletion(pagParam)
+	if err != nil {
+		return nil, err
+	}
+	return pagination, nil
+}
+
 // RequestDeleteAccount implements models.UserUsecase.
 func (uc *UserUsecase) RequestDeleteAccount(c *fiber.Ctx, user models.User) *fiber.Error {
 	message := "Here, your OTP code for delete the account:"
@@ -374,6 +713,11 @@ func (uc *UserUsecase) Login(ctx context.Context, payload models.LoginInput) (mo
 		return models.Token{}, fiber.NewError(400, "Invalid Email or Password.")
 	}
 
+	// the status is told only to the owner of the password
+	if user.IsLocked() {
+		return models.Token{}, fiber.NewError(403, utils.ERR_ACCOUNT_LOCKED)
+	}
+
 	data, err := uc.userRepo.Login(user)
 	if err != nil {
 		return models.Token{}, err
@@ -382,16 +726,44 @@ func (uc *UserUsecase) Login(ctx context.Context, payload models.LoginInput) (mo
 }
 
 // Register implements models.UserUsecase.
-func (uc *UserUsecase) Register(ctx context.Context, payload models.RegisterInput) *fiber.Error {
+func (uc *UserUsecase) Register(ctx context.Context, payload models.RegisterInput) (models.User, *fiber.Error) {
+	config, _ := configs.LoadConfig(".")
+
+	// check registration mode
+	switch models.RegistrationMode(config.RegistrationMode) {
+	case models.RegistrationClosed:
+		return models.User{}, fiber.NewError(403, "Registration is closed.")
+	case models.RegistrationInvite:
+		if payload.InvitationCode == "" {
+			return models.User{}, fiber.NewError(403, "Registration is by invitation only.")
+		}
+	}
+
+	var invitation *models.Invitation
+	if payload.InvitationCode != "" {
+		inv, err := uc.invRepo.FindByCode(payload.InvitationCode)
+		if err != nil {
+			return models.User{}, err
+		}
+
+		if !inv.Usable() {
+			return models.User{}, fiber.NewError(422, "Invitation is no longer valid.")
+		}
+
+		if !inv.MatchEmail(payload.Email) {
+			return models.User{}, fiber.NewError(422, "Invitation is not for this email.")
+		}
+		invitation = &inv
+	}
 
 	// cek email of user
 	if err := uc.userRepo.EmailExists(payload.Email); err != nil {
-		return err
+		return models.User{}, err
 	}
 
 	// cek username of user
 	if err := uc.userRepo.UsernameExists(payload.Username); err != nil {
-		return err
+		return models.User{}, err
 	}
 
 	user := models.User{
@@ -406,9 +778,24 @@ func (uc *UserUsecase) Register(ctx context.Context, payload models.RegisterInpu
 		},
 	}
 
+	if invitation != nil {
+		// claim the invitation first, so it can't be used more than max uses
+		if err := uc.invRepo.MarkUsed(*invitation); err != nil {
+			return models.User{}, err
+		}
+
+		// invited email is pre-verified
+		now := time.Now()
+		user.Verified = true
+		user.VerifiedAt = &now
+		user.IsStaff = invitation.Role == models.InvitationRoleStaff
+		user.UserProfile.StatusID = 1 // active
+		user.UserProfile.Role = invitation.Role
+	}
+
 	err := uc.userRepo.Register(user)
 	if err != nil {
-		return err
+		return user, err
 	}
-	return nil
+	return user, nil
 }
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"myapp/pkg/utils"
+	"myapp/src/models"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// adminUserRepository hold the users, the writes are counted
+type adminUserRepository struct {
+	models.UserRepository
+	users  map[uint]models.User
+	writes int
+}
+
+func (r *adminUserRepository) FindUserById(id uint) (models.User, *fiber.Error) {
+	user, ok := r.users[id]
+	if !ok {
+		return user, fiber.NewError(404, utils.ERR_DATA_NOT_FOUND)
+	}
+	return user, nil
+}
+
+func (*adminUserRepository) EmailExists(email string) *fiber.Error {
+	return nil
+}
+
+func (*adminUserRepository) UsernameExists(username string) *fiber.Error {
+	return nil
+}
+
+func (r *adminUserRepository) Update(obj models.User) (models.User, *fiber.Error) {
+	r.writes++
+	r.users[obj.ID] = obj
+	return obj, nil
+}
+
+func (r *adminUserRepository) RequestOTPEmail(obj models.User, message string) *fiber.Error {
+	r.writes++
+	return nil
+}
+
+func (r *adminUserRepository) Delete(obj models.User) *fiber.Error {
+	r.writes++
+	return nil
+}
+
+func (r *adminUserRepository) PermanentDelete(obj models.User) *fiber.Error {
+	r.writes++
+	return nil
+}
+
+func TestAdminUserTarget(t *testing.T) {
+	superuser := models.User{Email: "root@example.com", IsStaff: true, IsSuperuser: true}
+	superuser.ID = 1
+	staff := models.User{Email: "staff@example.com", IsStaff: true}
+	staff.ID = 2
+	member := models.User{Email: "member@example.com"}
+	member.ID = 3
+
+	writes := map[string]func(uc models.UserUsecase, c *fiber.Ctx, id uint) *fiber.Error{
+		"update": func(uc models.UserUsecase, c *fiber.Ctx, id uint) *fiber.Error {
+			_, err := uc.UpdateUser(c, id, models.AdminUpdateUserInput{Email: "taken@example.com", Username: "taken"})
+			return err
+		},
+		"status": func(uc models.UserUsecase, c *fiber.Ctx, id uint) *fiber.Error {
+			_, err := uc.UpdateUserStatus(c, id, models.StatusSuspended)
+			return err
+		},
+		"staff": func(uc models.UserUsecase, c *fiber.Ctx, id uint) *fiber.Error {
+			_, err := uc.UpdateUserStaff(c, id, false)
+			return err
+		},
+		"verify": func(uc models.UserUsecase, c *fiber.Ctx, id uint) *fiber.Error {
+			_, err := uc.VerifyUser(c, id)
+			return err
+		},
+		"reset password": func(uc models.UserUsecase, c *fiber.Ctx, id uint) *fiber.Error {
+			return uc.ResetUserPassword(c, id)
+		},
+		"delete":           func(uc models.UserUsecase, c *fiber.Ctx, id uint) *fiber.Error { return uc.DeleteUser(c, id) },
+		"permanent delete": func(uc models.UserUsecase, c *fiber.Ctx, id uint) *fiber.Error { return uc.PermanentDeleteUser(c, id) },
+	}
+
+	tests := []struct {
+		name          string
+		admin, target models.User
+		code          int
+	}{
+		{"staff on superuser", staff, superuser, 403},
+		{"staff on itself", staff, staff, 403},
+		{"superuser on itself", superuser, superuser, 403},
+		{"staff on member", staff, member, 0},
+		{"superuser on staff", superuser, staff, 0},
+	}
+
+	app := fiber.New()
+	for _, tt := range tests {
+		for action, write := range writes {
+			repo := &adminUserRepository{users: map[uint]models.User{superuser.ID: superuser, staff.ID: staff, member.ID: member}}
+			uc := NewUserUsecase(repo, nil)
+
+			c := app.AcquireCtx(&fasthttp.RequestCtx{})
+			c.Locals("user", tt.admin)
+			err := write(uc, c, tt.target.ID)
+			app.ReleaseCtx(c)
+
+			if tt.code == 0 {
+				if err != nil || repo.writes == 0 {
+					t.Errorf("%s, %s: got %v after %d writes", tt.name, action, err, repo.writes)
+				}
+				continue
+			}
+			if err == nil || err.Code != tt.code {
+				t.Errorf("%s, %s: got %v, want %d", tt.name, action, err, tt.code)
+			}
+			if repo.writes != 0 {
+				t.Errorf("%s, %s: the user has been changed", tt.name, action)
+			}
+		}
+	}
+}
+
+// loginUserRepository hold the user signing in
+type loginUserRepository struct {
+	models.UserRepository
+	user models.User
+}
+
+func (r *loginUserRepository) FindUserByIdentity(identity string) (models.User, *fiber.Error) {
+	return r.user, nil
+}
+
+func (r *loginUserRepository) Login(obj models.User) (models.Token, *fiber.Error) {
+	return models.Token{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func TestLoginStatus(t *testing.T) {
+	user := models.User{Email: "jane@example.com", Verified: true}
+	password, err := user.HashPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Password = password
+
+	tests := []struct {
+		status   uint
+		password string
+		code     int
+	}{
+		{models.StatusActive, "secret", 0},
+		{models.StatusPending, "secret", 0},
+		{models.StatusInactive, "secret", 403},
+		{models.StatusSuspended, "secret", 403},
+		// the status isn't told without the password
+		{models.StatusSuspended, "wrong", 400},
+	}
+
+	for _, tt := range tests {
+		user.UserProfile.StatusID = tt.status
+		uc := NewUserUsecase(&loginUserRepository{user: user}, nil)
+
+		_, err := uc.Login(context.TODO(), models.LoginInput{Email: user.Email, Password: tt.password})
+		if tt.code == 0 && err != nil {
+			t.Errorf("status %d: %v", tt.status, err)
+		}
+		if tt.code != 0 && (err == nil || err.Code != tt.code) {
+			t.Errorf("status %d, password %q: got %v, want %d", tt.status, tt.password, err, tt.code)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"myapp/pkg/utils"
+	"myapp/src/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WishlistUsecase struct {
+	wRepo models.WishlistRepository
+	pRepo models.ProductRepository
+	sRepo models.ProductStatRepository
+}
+
+// NewWishlistUsecase will create an object that represent the models.WishlistUsecase interface
+func NewWishlistUsecase(wishlist models.WishlistRepository, product models.ProductRepository, stat models.ProductStatRepository) models.WishlistUsecase {
+	return &WishlistUsecase{
+		wRepo: wishlist,
+		pRepo: product,
+		sRepo: stat,
+	}
+}
+
+// ListWishlist implements models.WishlistUsecase.
+func (uc *WishlistUsecase) ListWishlist(c *fiber.Ctx) ([]*models.Wishlist, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return nil, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	return uc.wRepo.ListWishlist(tenant.UserID)
+}
+
+// GetWishlist implements models.WishlistUsecase.
+func (uc *WishlistUsecase) GetWishlist(c *fiber.Ctx) (models.Wishlist, *fiber.Error) {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return models.Wishlist{}, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	return uc.wRepo.GetWishlist(tenant.UserID, utils.StringToUint(c.Params("id")))
+}
+
+// GetShared implements models.WishlistUsecase.
+func (uc *WishlistUsecase) GetShared(c *fiber.Ctx) (models.Wishlist, *fiber.Error) {
+	obj, err := uc.wRepo.GetShared(c.Params("token"))
+	if err != nil {
+		return obj, err
+	}
+
+	// the visitor don't need the secret
+	obj.ShareToken = nil
+	return obj, nil
+}
+
+// Create implements models.WishlistUsecase.
+func (uc *WishlistUsecase) Create(c *fiber.Ctx, payload models.WishlistInput) (models.Wishlist, *fiber.Error) {
+	var obj models.Wishlist
+
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return obj, fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	payload.Sanitize()
+
+	obj.Name = payload.Name
+	obj.UserID = tenant.UserID
+	if err := setShareToken(&obj, payload.IsPublic); err != nil {
+		return obj, err
+	}
+
+	return uc.wRepo.Create(obj)
+}
+
+// Update implements models.WishlistUsecase.
+func (uc *WishlistUsecase) Update(c *fiber.Ctx, payload models.WishlistInput) (models.Wishlist, *fiber.Error) {
+	obj, err := uc.GetWishlist(c)
+	if err != nil {
+		return obj, err
+	}
+
+	payload.Sanitize()
+
+	obj.Name = payload.Name
+	if err := setShareToken(&obj, payload.IsPublic); err != nil {
+		return obj, err
+	}
+
+	return uc.wRepo.Update(obj)
+}
+
+// Delete implements models.WishlistUsecase.
+func (uc *WishlistUsecase) Delete(c *fiber.Ctx) *fiber.Error {
+	obj, err := uc.GetWishlist(c)
+	if err != nil {
+		return err
+	}
+
+	if obj.IsDefault {
+		return fiber.NewError(422, "The favorites can't be deleted.")
+	}
+
+	if err := uc.wRepo.Delete(obj); err != nil {
+		return err
+	}
+
+	uc.pRepo.ClearCatalogCache()
+	return nil
+}
+
+// AddItem implements models.WishlistUsecase.
+func (uc *WishlistUsecase) AddItem(c *fiber.Ctx, payload models.WishlistItemInput) (models.Wishlist, *fiber.Error) {
+	obj, err := uc.GetWishlist(c)
+	if err != nil {
+		return obj, err
+	}
+
+	// the product must be visible to the user
+	tenant, _ := c.Locals("tenant").(models.Tenant)
+	if _, err := uc.pRepo.GetProduct(tenant, payload.ProductID); err != nil {
+		return obj, err
+	}
+
+	added, err := uc.wRepo.AddItem(obj, payload.ProductID)
+	if err != nil {
+		return obj, err
+	}
+	if added {
+		uc.sRepo.Track(models.StatFavorite, payload.ProductID)
+		// the catalog show the favorite count
+		uc.pRepo.ClearCatalogCache()
+	}
+
+	return uc.GetWishlist(c)
+}
+
+// RemoveItem implements models.WishlistUsecase.
+func (uc *WishlistUsecase) RemoveItem(c *fiber.Ctx) *fiber.Error {
+	obj, err := uc.GetWishlist(c)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.wRepo.RemoveItem(obj, utils.StringToUint(c.Params("product_id"))); err != nil {
+		return err
+	}
+
+	uc.pRepo.ClearCatalogCache()
+	return nil
+}
+
+// Favorite implements models.WishlistUsecase.
+func (uc *WishlistUsecase) Favorite(c *fiber.Ctx) *fiber.Error {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	product, err := uc.pRepo.GetProduct(tenant, utils.StringToUint(c.Params("id")))
+	if err != nil {
+		return err
+	}
+
+	obj, err := uc.wRepo.DefaultWishlist(tenant.UserID)
+	if err != nil {
+		return err
+	}
+
+	added, err := uc.wRepo.AddItem(obj, product.ID)
+	if err != nil {
+		return err
+	}
+	if added {
+		uc.sRepo.Track(models.StatFavorite, product.ID)
+		uc.pRepo.ClearCatalogCache()
+	}
+
+	return nil
+}
+
+// Unfavorite implements models.WishlistUsecase.
+func (uc *WishlistUsecase) Unfavorite(c *fiber.Ctx) *fiber.Error {
+	tenant, errLocal := c.Locals("tenant").(models.Tenant)
+	if !errLocal {
+		return fiber.NewError(500, utils.ERR_CURRENT_USER_NOT_FOUND)
+	}
+
+	obj, err := uc.wRepo.DefaultWishlist(tenant.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.wRepo.RemoveItem(obj, utils.StringToUint(c.Params("id"))); err != nil {
+		return err
+	}
+
+	uc.pRepo.ClearCatalogCache()
+	return nil
+}
+
+// setShareToken create the secret of the public link, or revoke it
+func setShareToken(obj *models.Wishlist, public bool) *fiber.Error {
+	if !public {
+		obj.ShareToken = nil
+		return nil
+	}
+	if obj.ShareToken != nil {
+		return nil
+	}
+
+	token, err := utils.GenerateRandomStringURLSafe(32)
+	if err != nil {
+		return fiber.NewError(500, err.Error())
+	}
+	obj.ShareToken = &token
+	return nil
+}